@@ -2,33 +2,81 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 // Config holds application configuration
 type Config struct {
-	Port               string
-	Environment        string
-	DatabaseURL        string
-	LogLevel           string
-	GitHubClientID     string
-	GitHubClientSecret string
-	SessionSecret      string
-	BaseURL            string
-	UseLocalAuth       bool
+	Port                    string
+	Environment             string
+	DatabaseURL             string
+	LogLevel                string
+	GitHubClientID          string
+	GitHubClientSecret      string
+	SessionSecret           string
+	BaseURL                 string
+	UseLocalAuth            bool
+	ReleaseCheckInterval    time.Duration
+	RecurrenceCheckInterval time.Duration
+	RiskDigestCheckInterval time.Duration
+	LinkPreviewTimeout      time.Duration
+	LinkPreviewMaxBytes     int64
+	LinkPreviewCacheTTL     time.Duration
+	LinkCheckInterval       time.Duration
+	LinkCheckTimeout        time.Duration
+	LinkCheckHostDelay      time.Duration
+	ArchiveDir              string
+	ArchiveTimeout          time.Duration
+	ArchiveMaxBytes         int64
+	ExtractionInterval      time.Duration
+	ExtractionTimeout       time.Duration
+	ExtractionMaxBytes      int64
+	StorageBackend          string
+	StorageDir              string
+	S3Endpoint              string
+	S3Bucket                string
+	S3Region                string
+	S3AccessKeyID           string
+	S3SecretAccessKey       string
+	UploadMaxBytes          int64
 }
 
 // Load reads configuration from environment variables with defaults
 func Load(useLocalAuth bool) *Config {
 	return &Config{
-		Port:               getEnv("PORT", "8080"),
-		Environment:        getEnv("ENVIRONMENT", "development"),
-		DatabaseURL:        getEnv("DATABASE_URL", "zipcodereader.db"),
-		LogLevel:           getEnv("LOG_LEVEL", "info"),
-		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
-		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
-		SessionSecret:      getEnv("SESSION_SECRET", "your-secret-key-change-in-production"),
-		BaseURL:            getEnv("BASE_URL", "http://localhost:8080"),
-		UseLocalAuth:       useLocalAuth,
+		Port:                    getEnv("PORT", "8080"),
+		Environment:             getEnv("ENVIRONMENT", "development"),
+		DatabaseURL:             getEnv("DATABASE_URL", "zipcodereader.db"),
+		LogLevel:                getEnv("LOG_LEVEL", "info"),
+		GitHubClientID:          getEnv("GITHUB_CLIENT_ID", ""),
+		GitHubClientSecret:      getEnv("GITHUB_CLIENT_SECRET", ""),
+		SessionSecret:           getEnv("SESSION_SECRET", "your-secret-key-change-in-production"),
+		BaseURL:                 getEnv("BASE_URL", "http://localhost:8080"),
+		UseLocalAuth:            useLocalAuth,
+		ReleaseCheckInterval:    getEnvDuration("RELEASE_CHECK_INTERVAL", time.Minute),
+		RecurrenceCheckInterval: getEnvDuration("RECURRENCE_CHECK_INTERVAL", time.Hour),
+		RiskDigestCheckInterval: getEnvDuration("RISK_DIGEST_CHECK_INTERVAL", time.Hour),
+		LinkPreviewTimeout:      getEnvDuration("LINK_PREVIEW_TIMEOUT", 5*time.Second),
+		LinkPreviewMaxBytes:     getEnvInt64("LINK_PREVIEW_MAX_BYTES", 1<<20),
+		LinkPreviewCacheTTL:     getEnvDuration("LINK_PREVIEW_CACHE_TTL", 24*time.Hour),
+		LinkCheckInterval:       getEnvDuration("LINK_CHECK_INTERVAL", 24*time.Hour),
+		LinkCheckTimeout:        getEnvDuration("LINK_CHECK_TIMEOUT", 10*time.Second),
+		LinkCheckHostDelay:      getEnvDuration("LINK_CHECK_HOST_DELAY", time.Second),
+		ArchiveDir:              getEnv("ARCHIVE_DIR", "data/archives"),
+		ArchiveTimeout:          getEnvDuration("ARCHIVE_TIMEOUT", 15*time.Second),
+		ArchiveMaxBytes:         getEnvInt64("ARCHIVE_MAX_BYTES", 10<<20),
+		ExtractionInterval:      getEnvDuration("EXTRACTION_INTERVAL", 10*time.Minute),
+		ExtractionTimeout:       getEnvDuration("EXTRACTION_TIMEOUT", 10*time.Second),
+		ExtractionMaxBytes:      getEnvInt64("EXTRACTION_MAX_BYTES", 5<<20),
+		StorageBackend:          getEnv("STORAGE_BACKEND", "local"),
+		StorageDir:              getEnv("STORAGE_DIR", "data/uploads"),
+		S3Endpoint:              getEnv("S3_ENDPOINT", ""),
+		S3Bucket:                getEnv("S3_BUCKET", ""),
+		S3Region:                getEnv("S3_REGION", "us-east-1"),
+		S3AccessKeyID:           getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:       getEnv("S3_SECRET_ACCESS_KEY", ""),
+		UploadMaxBytes:          getEnvInt64("UPLOAD_MAX_BYTES", 25<<20),
 	}
 }
 
@@ -39,3 +87,23 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration returns environment variable parsed as a duration or default if not set or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt64 returns environment variable parsed as an integer or default if not set or invalid
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -32,26 +32,26 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 
 // autoMigrate runs database migrations
 func autoMigrate(db *gorm.DB) error {
-	// Auto-migrate the User model
-	err := db.AutoMigrate(&models.User{})
+	// Auto-migrate every model
+	err := db.AutoMigrate(models.All()...)
 	if err != nil {
 		return err
 	}
 
-	// Auto-migrate the Assignment model
-	err = db.AutoMigrate(&models.Assignment{})
+	// Fill in normalized URLs for assignments created before duplicate detection
+	err = backfillNormalizedURLs(db)
 	if err != nil {
 		return err
 	}
 
-	// Auto-migrate the StudentAssignment model
-	err = db.AutoMigrate(&models.StudentAssignment{})
+	// Give student assignments created before click-through tracking their tracked links
+	err = backfillLinkTokens(db)
 	if err != nil {
 		return err
 	}
 
 	// Create indexes for better performance
-	err = createIndexes(db)
+	err = CreateIndexes(db)
 	if err != nil {
 		return err
 	}
@@ -59,8 +59,48 @@ func autoMigrate(db *gorm.DB) error {
 	return nil
 }
 
-// createIndexes creates database indexes for better performance
-func createIndexes(db *gorm.DB) error {
+// backfillNormalizedURLs stores the normalized URL of assignments that have none yet
+func backfillNormalizedURLs(db *gorm.DB) error {
+	var assignments []models.Assignment
+	if err := db.Unscoped().Where("normalized_url = '' OR normalized_url IS NULL").Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		err := db.Unscoped().Model(&models.Assignment{}).Where("id = ?", assignment.ID).
+			Update("normalized_url", models.NormalizeURL(assignment.URL)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillLinkTokens generates a tracked link token for student assignments that have none yet
+func backfillLinkTokens(db *gorm.DB) error {
+	var ids []uint
+	if err := db.Unscoped().Model(&models.StudentAssignment{}).Where("link_token IS NULL").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		token, err := models.NewLinkToken()
+		if err != nil {
+			return err
+		}
+		err = db.Unscoped().Model(&models.StudentAssignment{}).Where("id = ?", id).
+			Update("link_token", token).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateIndexes creates database indexes for better performance
+func CreateIndexes(db *gorm.DB) error {
 	// Index on assignments.created_by_id for instructor queries
 	err := db.Exec("CREATE INDEX IF NOT EXISTS idx_assignments_created_by ON assignments(created_by_id)").Error
 	if err != nil {
@@ -97,6 +137,12 @@ func createIndexes(db *gorm.DB) error {
 		return err
 	}
 
+	// Index on assignments.publish_at for scheduled release queries
+	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_assignments_publish_at ON assignments(publish_at)").Error
+	if err != nil {
+		return err
+	}
+
 	// Composite index for student assignment lookups
 	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_student_assignments_composite ON student_assignments(student_id, assignment_id)").Error
 	if err != nil {
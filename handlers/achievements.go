@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AchievementHandlers handles the badges students earn
+type AchievementHandlers struct {
+	achievementService *services.AchievementService
+}
+
+// NewAchievementHandlers creates new achievement handlers
+func NewAchievementHandlers(achievementService *services.AchievementService) *AchievementHandlers {
+	return &AchievementHandlers{achievementService: achievementService}
+}
+
+// GetAchievements handles GET /student/achievements
+func (h *AchievementHandlers) GetAchievements(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	achievements, err := h.achievementService.GetAchievements(student.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"achievements": achievements})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnnotationHandlers handles highlights and margin notes on archived readings
+type AnnotationHandlers struct {
+	annotationService *services.AnnotationService
+}
+
+// NewAnnotationHandlers creates new annotation handlers
+func NewAnnotationHandlers(annotationService *services.AnnotationService) *AnnotationHandlers {
+	return &AnnotationHandlers{annotationService: annotationService}
+}
+
+// CreateAnnotationRequest represents a highlighted passage and its selectors
+type CreateAnnotationRequest struct {
+	Exact      string `json:"exact" binding:"required"`
+	Prefix     string `json:"prefix"`
+	Suffix     string `json:"suffix"`
+	Start      int    `json:"start"`
+	End        int    `json:"end"`
+	Note       string `json:"note"`
+	Visibility string `json:"visibility"`
+}
+
+// UpdateAnnotationRequest represents a new note and visibility for an annotation
+type UpdateAnnotationRequest struct {
+	Note       string `json:"note"`
+	Visibility string `json:"visibility"`
+}
+
+// GetAnnotations handles GET /assignments/:id/annotations
+func (h *AnnotationHandlers) GetAnnotations(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	annotations, err := h.annotationService.GetAnnotations(uint(id), user)
+	if err != nil {
+		respondAnnotationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"annotations": annotations,
+		"count":       len(annotations),
+	})
+}
+
+// CreateAnnotation handles POST /assignments/:id/annotations
+func (h *AnnotationHandlers) CreateAnnotation(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	annotation, err := h.annotationService.CreateAnnotation(uint(id), user, services.AnnotationInput{
+		Exact:      req.Exact,
+		Prefix:     req.Prefix,
+		Suffix:     req.Suffix,
+		Start:      req.Start,
+		End:        req.End,
+		Note:       req.Note,
+		Visibility: req.Visibility,
+	})
+	if err != nil {
+		respondAnnotationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Annotation created successfully",
+		"annotation": annotation,
+	})
+}
+
+// UpdateAnnotation handles PUT /annotations/:id
+func (h *AnnotationHandlers) UpdateAnnotation(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid annotation ID"})
+		return
+	}
+
+	var req UpdateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	annotation, err := h.annotationService.UpdateAnnotation(uint(id), user, req.Note, req.Visibility)
+	if err != nil {
+		respondAnnotationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Annotation updated successfully",
+		"annotation": annotation,
+	})
+}
+
+// DeleteAnnotation handles DELETE /annotations/:id
+func (h *AnnotationHandlers) DeleteAnnotation(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid annotation ID"})
+		return
+	}
+
+	if err := h.annotationService.DeleteAnnotation(uint(id), user); err != nil {
+		respondAnnotationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Annotation deleted successfully"})
+}
+
+// GetMostHighlighted handles GET /instructor/assignments/:id/annotations/popular
+func (h *AnnotationHandlers) GetMostHighlighted(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	passages, err := h.annotationService.GetMostHighlighted(uint(id), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"passages": passages,
+		"count":    len(passages),
+	})
+}
+
+// respondAnnotationError maps annotation errors to HTTP responses
+func respondAnnotationError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found in the archived reading"):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": message})
+	case strings.Contains(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	case strings.Contains(message, "no archived copy"):
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+	case strings.Contains(message, "too long"), strings.Contains(message, "invalid"),
+		strings.Contains(message, "select a passage"), strings.Contains(message, "visibility"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+	default:
+		respondServiceError(c, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// archiveContentSecurityPolicy keeps archived pages from loading anything but their own images
+const archiveContentSecurityPolicy = "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; frame-ancestors 'none'"
+
+// archivePagePolicy extends archiveContentSecurityPolicy for the reading page so that the
+// application's own annotation script can run and save highlights. Archived HTML never
+// contains scripts, and inline scripts stay blocked.
+const archivePagePolicy = "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; script-src 'self'; connect-src 'self'; frame-ancestors 'none'"
+
+// ArchiveHandlers handles offline copies of assignment pages
+type ArchiveHandlers struct {
+	archiveService *services.ArchiveService
+}
+
+// NewArchiveHandlers creates new archive handlers
+func NewArchiveHandlers(archiveService *services.ArchiveService) *ArchiveHandlers {
+	return &ArchiveHandlers{
+		archiveService: archiveService,
+	}
+}
+
+// CreateArchive handles POST /instructor/assignments/:id/archive
+func (h *ArchiveHandlers) CreateArchive(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	archive, err := h.archiveService.ArchiveAssignment(uint(assignmentID), instructor.ID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to fetch") {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrArchiveTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Page archived successfully",
+		"archive": archive,
+	})
+}
+
+// DeleteArchive handles DELETE /instructor/assignments/:id/archive
+func (h *ArchiveHandlers) DeleteArchive(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	if err := h.archiveService.DeleteArchive(uint(assignmentID), instructor.ID); err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Archive deleted successfully"})
+}
+
+// ShowInstructorArchive handles GET /instructor/assignments/:id/archive
+func (h *ArchiveHandlers) ShowInstructorArchive(c *gin.Context) {
+	archive, ok := h.instructorArchive(c)
+	if !ok {
+		return
+	}
+
+	h.renderArchive(c, archive, fmt.Sprintf("/instructor/assignments/%d/detail", archive.AssignmentID), "")
+}
+
+// ServeInstructorAsset handles GET /instructor/assignments/:id/archive/assets/:name
+func (h *ArchiveHandlers) ServeInstructorAsset(c *gin.Context) {
+	archive, ok := h.instructorArchive(c)
+	if !ok {
+		return
+	}
+
+	h.serveAsset(c, archive)
+}
+
+// ShowStudentArchive handles GET /student/assignments/:id/archive
+func (h *ArchiveHandlers) ShowStudentArchive(c *gin.Context) {
+	archive, ok := h.studentArchive(c)
+	if !ok {
+		return
+	}
+
+	h.renderArchive(c, archive, fmt.Sprintf("/student/assignments/%s/detail", c.Param("id")), c.Param("id"))
+}
+
+// ServeStudentAsset handles GET /student/assignments/:id/archive/assets/:name
+func (h *ArchiveHandlers) ServeStudentAsset(c *gin.Context) {
+	archive, ok := h.studentArchive(c)
+	if !ok {
+		return
+	}
+
+	h.serveAsset(c, archive)
+}
+
+// instructorArchive loads the archive of the instructor's assignment in the URL
+func (h *ArchiveHandlers) instructorArchive(c *gin.Context) (*models.AssignmentArchive, bool) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return nil, false
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return nil, false
+	}
+
+	archive, err := h.archiveService.GetInstructorArchive(uint(assignmentID), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return archive, true
+}
+
+// studentArchive loads the archive of the student assignment in the URL
+func (h *ArchiveHandlers) studentArchive(c *gin.Context) (*models.AssignmentArchive, bool) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return nil, false
+	}
+
+	studentAssignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return nil, false
+	}
+
+	archive, err := h.archiveService.GetStudentArchive(uint(studentAssignmentID), student.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Archive not found"})
+		return nil, false
+	}
+
+	return archive, true
+}
+
+// renderArchive renders the stored page inside the archive frame; the student assignment
+// ID is empty when an instructor is reading
+func (h *ArchiveHandlers) renderArchive(c *gin.Context, archive *models.AssignmentArchive, backURL string, studentAssignmentID string) {
+	content, err := h.archiveService.ReadArchiveContent(archive)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Archive not found"})
+		return
+	}
+
+	var userID uint
+	if user, exists := c.Get("user"); exists {
+		userID = user.(*models.User).ID
+	}
+
+	c.Header("Content-Security-Policy", archivePagePolicy)
+	c.HTML(http.StatusOK, "assignment_archive.html", gin.H{
+		"archive": archive,
+		"user_id": userID,
+		// The stored page was sanitized when it was archived
+		"content":  template.HTML(content),
+		"back_url": backURL,
+		// Students reading the archive record time on task against their assignment
+		"student_assignment_id": studentAssignmentID,
+	})
+}
+
+// serveAsset sends an archived image
+func (h *ArchiveHandlers) serveAsset(c *gin.Context, archive *models.AssignmentArchive) {
+	path, err := h.archiveService.ArchiveAssetPath(archive, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Security-Policy", archiveContentSecurityPolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.File(path)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RiskHandlers handles the at-risk student report and its settings
+type RiskHandlers struct {
+	riskService *services.RiskService
+}
+
+// NewRiskHandlers creates new risk handlers
+func NewRiskHandlers(riskService *services.RiskService) *RiskHandlers {
+	return &RiskHandlers{riskService: riskService}
+}
+
+// GetAtRiskReport handles GET /instructor/progress/at-risk
+func (h *RiskHandlers) GetAtRiskReport(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.riskService.GetAtRiskReport(instructor.ID, time.Now())
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// GetRiskSettings handles GET /instructor/progress/at-risk/settings
+func (h *RiskHandlers) GetRiskSettings(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.riskService.GetSettings(instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateRiskSettings handles PUT /instructor/progress/at-risk/settings
+func (h *RiskHandlers) UpdateRiskSettings(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input services.RiskSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.riskService.SaveSettings(instructor.ID, input)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
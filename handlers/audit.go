@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandlers handles the audit log viewer and export
+type AuditHandlers struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandlers creates new audit handlers
+func NewAuditHandlers(auditService *services.AuditService) *AuditHandlers {
+	return &AuditHandlers{auditService: auditService}
+}
+
+// GetAuditLog handles GET /instructor/audit. Events can be filtered by action, actor_id,
+// target_type, target_id and assignment_id, and from/to (YYYY-MM-DD, inclusive); limit
+// caps the number returned, 100 by default.
+func (h *AuditHandlers) GetAuditLog(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	filter, ok := auditFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.auditService.GetEvents(instructor.ID, filter)
+	if err != nil {
+		respondAuditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events, "total": len(events)})
+}
+
+// ExportAuditLog handles GET /instructor/audit/export as CSV, with the same filters as GetAuditLog
+func (h *AuditHandlers) ExportAuditLog(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	filter, ok := auditFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.auditService.ExportEvents(instructor.ID, filter)
+	if err != nil {
+		respondAuditError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"time", "action", "actor_id", "actor", "target_type", "target_id", "assignment_id", "changes", "ip"})
+	for _, event := range events {
+		changes := ""
+		if len(event.Changes) > 0 {
+			encoded, _ := json.Marshal(event.Changes)
+			changes = string(encoded)
+		}
+		writer.Write([]string{
+			event.CreatedAt.Format(time.RFC3339),
+			event.Action,
+			optionalID(event.ActorID),
+			csvSafe(event.ActorName),
+			event.TargetType,
+			strconv.FormatUint(uint64(event.TargetID), 10),
+			optionalID(event.AssignmentID),
+			csvSafe(changes),
+			event.IP,
+		})
+	}
+	writer.Flush()
+}
+
+// auditFilterFromQuery reads the audit log filters from the query string, responding with 400 if one is malformed
+func auditFilterFromQuery(c *gin.Context) (models.AuditFilter, bool) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+	}
+
+	ids := map[string]**uint{
+		"actor_id":      &filter.ActorID,
+		"target_id":     &filter.TargetID,
+		"assignment_id": &filter.AssignmentID,
+	}
+	for param, field := range ids {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return filter, false
+		}
+		parsed := uint(id)
+		*field = &parsed
+	}
+
+	if from := c.Query("from"); from != "" {
+		date, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return filter, false
+		}
+		filter.From = &date
+	}
+	if to := c.Query("to"); to != "" {
+		date, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return filter, false
+		}
+		end := date.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return filter, false
+		}
+		filter.Limit = n
+	}
+
+	return filter, true
+}
+
+// respondAuditError maps audit log errors to HTTP responses
+func respondAuditError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "invalid") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondServiceError(c, err, http.StatusInternalServerError)
+}
+
+// optionalID formats an optional ID for CSV, leaving it blank when unset
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
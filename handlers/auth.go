@@ -11,13 +11,15 @@ import (
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	authService *services.AuthService
+	authService  *services.AuthService
+	auditService *services.AuditService
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, auditService *services.AuditService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:  authService,
+		auditService: auditService,
 	}
 }
 
@@ -87,6 +89,8 @@ func (h *AuthHandler) Callback(c *gin.Context) {
 	session.Set("user_role", user.Role)
 	session.Save()
 
+	h.auditService.RecordLogin(user, c.ClientIP())
+
 	// Redirect to dashboard
 	c.Redirect(http.StatusTemporaryRedirect, "/dashboard")
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"zipcodereader/models"
@@ -13,14 +14,16 @@ import (
 type DashboardHandlers struct {
 	assignmentService        *services.AssignmentService
 	studentAssignmentService *services.StudentAssignmentService
+	archiveService           *services.ArchiveService
 	useLocalAuth             bool
 }
 
 // NewDashboardHandlers creates new dashboard handlers
-func NewDashboardHandlers(assignmentService *services.AssignmentService, studentAssignmentService *services.StudentAssignmentService, useLocalAuth bool) *DashboardHandlers {
+func NewDashboardHandlers(assignmentService *services.AssignmentService, studentAssignmentService *services.StudentAssignmentService, archiveService *services.ArchiveService, useLocalAuth bool) *DashboardHandlers {
 	return &DashboardHandlers{
 		assignmentService:        assignmentService,
 		studentAssignmentService: studentAssignmentService,
+		archiveService:           archiveService,
 		useLocalAuth:             useLocalAuth,
 	}
 }
@@ -96,10 +99,15 @@ func (h *DashboardHandlers) ShowAssignmentDetail(c *gin.Context) {
 			return
 		}
 
+		// A missing archive just hides the offline copy link
+		archive, _ := h.archiveService.GetArchive(assignment.ID)
+
 		c.HTML(http.StatusOK, "assignment_detail.html", gin.H{
 			"title":          assignment.Title,
 			"user":           userObj,
 			"assignment":     assignment,
+			"archive":        archive,
+			"archive_url":    fmt.Sprintf("/instructor/assignments/%d/archive", assignment.ID),
 			"use_local_auth": h.useLocalAuth,
 		})
 	} else {
@@ -110,11 +118,22 @@ func (h *DashboardHandlers) ShowAssignmentDetail(c *gin.Context) {
 			return
 		}
 
+		resources, err := h.studentAssignmentService.GetResourceProgress(studentAssignment.ID, userObj.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		archive, _ := h.archiveService.GetArchive(studentAssignment.AssignmentID)
+
 		c.HTML(http.StatusOK, "assignment_detail.html", gin.H{
 			"title":             studentAssignment.Assignment.Title,
 			"user":              userObj,
 			"assignment":        studentAssignment.Assignment,
 			"studentAssignment": studentAssignment,
+			"resources":         resources,
+			"archive":           archive,
+			"archive_url":       fmt.Sprintf("/student/assignments/%d/archive", studentAssignment.ID),
 			"use_local_auth":    h.useLocalAuth,
 		})
 	}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiscussionHandlers handles the discussion threads on assignments
+type DiscussionHandlers struct {
+	discussionService *services.DiscussionService
+}
+
+// NewDiscussionHandlers creates new discussion handlers
+func NewDiscussionHandlers(discussionService *services.DiscussionService) *DiscussionHandlers {
+	return &DiscussionHandlers{discussionService: discussionService}
+}
+
+// CreatePostRequest represents a new thread, or a reply when ParentID is set
+type CreatePostRequest struct {
+	ParentID *uint  `json:"parent_id"`
+	Content  string `json:"content" binding:"required"`
+}
+
+// EditPostRequest represents the new content of a post
+type EditPostRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// PinPostRequest represents pinning or unpinning a post
+type PinPostRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+// HidePostRequest represents hiding a post from students or showing it again
+type HidePostRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// GetThreads handles GET /assignments/:id/discussion
+func (h *DiscussionHandlers) GetThreads(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	threads, err := h.discussionService.GetThreads(uint(id), user)
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"threads":   threads,
+		"count":     len(threads),
+		"moderator": user.IsInstructor(),
+	})
+}
+
+// CreatePost handles POST /assignments/:id/discussion
+func (h *DiscussionHandlers) CreatePost(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionService.CreatePost(uint(id), user, services.CreatePostInput{
+		ParentID: req.ParentID,
+		Content:  req.Content,
+	})
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Post created successfully",
+		"post":    post,
+	})
+}
+
+// EditPost handles PUT /discussion/posts/:id
+func (h *DiscussionHandlers) EditPost(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req EditPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionService.EditPost(uint(id), user, req.Content)
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post updated successfully",
+		"post":    post,
+	})
+}
+
+// DeletePost handles DELETE /discussion/posts/:id
+func (h *DiscussionHandlers) DeletePost(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	if err := h.discussionService.DeletePost(uint(id), user); err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
+}
+
+// GetPostHistory handles GET /discussion/posts/:id/history
+func (h *DiscussionHandlers) GetPostHistory(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	revisions, err := h.discussionService.GetPostHistory(uint(id), user)
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+// PinPost handles PUT /discussion/posts/:id/pin
+func (h *DiscussionHandlers) PinPost(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req PinPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionService.PinPost(uint(id), user, req.Pinned)
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"post": post})
+}
+
+// HidePost handles PUT /discussion/posts/:id/hide
+func (h *DiscussionHandlers) HidePost(c *gin.Context) {
+	user, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req HidePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := h.discussionService.HidePost(uint(id), user, req.Hidden)
+	if err != nil {
+		respondDiscussionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"post": post})
+}
+
+// respondDiscussionError maps discussion errors to HTTP responses
+func respondDiscussionError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "too long"):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "cannot be empty"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		respondServiceError(c, err, http.StatusInternalServerError)
+	}
+}
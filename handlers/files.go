@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"zipcodereader/models"
+	"zipcodereader/services"
+	"zipcodereader/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandlers handles uploaded assignment documents
+type FileHandlers struct {
+	fileService *services.FileService
+	maxBytes    int64
+}
+
+// NewFileHandlers creates new file handlers
+func NewFileHandlers(fileService *services.FileService, maxBytes int64) *FileHandlers {
+	return &FileHandlers{
+		fileService: fileService,
+		maxBytes:    maxBytes,
+	}
+}
+
+// AttachFileRequest represents a request to assign an uploaded document
+type AttachFileRequest struct {
+	FileID uint `json:"file_id" binding:"required"`
+}
+
+// UploadFile handles POST /instructor/files with a multipart "file" field
+func (h *FileHandlers) UploadFile(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrFileTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	if header.Size > h.maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrFileTooLarge.Error()})
+		return
+	}
+
+	content, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read upload"})
+		return
+	}
+	defer content.Close()
+
+	file, err := h.fileService.UploadFile(instructor.ID, header.Filename, content)
+	if err != nil {
+		if errors.Is(err, services.ErrFileTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "File uploaded successfully",
+		"file":    file,
+		"url":     models.UploadedFilePath(file.ID),
+	})
+}
+
+// GetFiles handles GET /instructor/files
+func (h *FileHandlers) GetFiles(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	files, err := h.fileService.GetFiles(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"files": files,
+		"count": len(files),
+	})
+}
+
+// DeleteFile handles DELETE /instructor/files/:id
+func (h *FileHandlers) DeleteFile(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	fileID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+		return
+	}
+
+	if err := h.fileService.DeleteFile(uint(fileID), instructor.ID); err != nil {
+		respondServiceError(c, err, http.StatusConflict)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "File deleted successfully"})
+}
+
+// AttachFile handles PUT /instructor/assignments/:id/file
+func (h *FileHandlers) AttachFile(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req AttachFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.fileService.AttachFile(uint(assignmentID), instructor.ID, req.FileID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "File attached successfully",
+		"assignment": assignment,
+	})
+}
+
+// DownloadFile handles GET /files/:id for the owner and assigned students
+func (h *FileHandlers) DownloadFile(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+
+	fileID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+		return
+	}
+
+	file, content, err := h.fileService.OpenFile(uint(fileID), userObj)
+	if err != nil {
+		// Do not reveal whether a file the user may not see exists
+		if err.Error() == "access denied" || errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return
+		}
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	// PDFs and text open in the browser; other documents download
+	disposition := "attachment"
+	if file.ContentType == "application/pdf" || file.ContentType == "text/plain" {
+		disposition = "inline"
+	}
+
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Content-Length", fmt.Sprint(file.SizeBytes))
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+	c.Header("ETag", `"`+file.SHA256+`"`)
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		log.Printf("failed to send file %d: %v", file.ID, err)
+	}
+}
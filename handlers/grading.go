@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GradingHandlers handles points, rubrics, grades and the gradebook
+type GradingHandlers struct {
+	gradingService *services.GradingService
+	useLocalAuth   bool
+}
+
+// NewGradingHandlers creates new grading handlers
+func NewGradingHandlers(gradingService *services.GradingService, useLocalAuth bool) *GradingHandlers {
+	return &GradingHandlers{gradingService: gradingService, useLocalAuth: useLocalAuth}
+}
+
+// CategoryWeightsRequest represents gradebook weights keyed by category
+type CategoryWeightsRequest struct {
+	Weights map[string]float64 `json:"weights" binding:"required"`
+}
+
+// GetRubrics handles GET /instructor/rubrics
+func (h *GradingHandlers) GetRubrics(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	rubrics, err := h.gradingService.GetRubrics(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load rubrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rubrics": rubrics,
+		"count":   len(rubrics),
+	})
+}
+
+// CreateRubric handles POST /instructor/rubrics
+func (h *GradingHandlers) CreateRubric(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req services.RubricInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rubric, err := h.gradingService.CreateRubric(instructor.ID, req)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Rubric created successfully",
+		"rubric":  rubric,
+	})
+}
+
+// UpdateRubric handles PUT /instructor/rubrics/:id
+func (h *GradingHandlers) UpdateRubric(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rubric ID"})
+		return
+	}
+
+	var req services.RubricInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rubric, err := h.gradingService.UpdateRubric(uint(id), instructor.ID, req)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Rubric updated successfully",
+		"rubric":  rubric,
+	})
+}
+
+// DeleteRubric handles DELETE /instructor/rubrics/:id
+func (h *GradingHandlers) DeleteRubric(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rubric ID"})
+		return
+	}
+
+	if err := h.gradingService.DeleteRubric(uint(id), instructor.ID); err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rubric deleted successfully"})
+}
+
+// SetGradingPolicy handles PUT /instructor/assignments/:id/grading
+func (h *GradingHandlers) SetGradingPolicy(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req services.GradingPolicyInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.gradingService.SetGradingPolicy(uint(id), instructor.ID, req)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Grading policy updated successfully",
+		"assignment": assignment,
+	})
+}
+
+// GetAssignmentGrades handles GET /instructor/assignments/:id/grades
+func (h *GradingHandlers) GetAssignmentGrades(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	grades, err := h.gradingService.GetAssignmentGrades(uint(id), instructor.ID)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"grades": grades,
+		"count":  len(grades),
+	})
+}
+
+// GradeStudent handles PUT /instructor/assignments/:id/students/:student_id/grade
+func (h *GradingHandlers) GradeStudent(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	var req services.GradeInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grade, err := h.gradingService.GradeStudent(uint(id), uint(studentID), instructor.ID, req)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Grade saved successfully",
+		"grade":   grade,
+	})
+}
+
+// DeleteGrade handles DELETE /instructor/assignments/:id/students/:student_id/grade
+func (h *GradingHandlers) DeleteGrade(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	if err := h.gradingService.DeleteGrade(uint(id), uint(studentID), instructor.ID); err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Grade removed successfully"})
+}
+
+// GetGradebook handles GET /instructor/gradebook
+func (h *GradingHandlers) GetGradebook(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	gradebook, err := h.gradingService.GetGradebook(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load gradebook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gradebook)
+}
+
+// ShowGradebook renders the gradebook page at GET /instructor/gradebook-view
+func (h *GradingHandlers) ShowGradebook(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	gradebook, err := h.gradingService.GetGradebook(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load gradebook"})
+		return
+	}
+
+	c.HTML(http.StatusOK, "gradebook.html", gin.H{
+		"title":          "Gradebook",
+		"user":           instructor,
+		"gradebook":      gradebook,
+		"use_local_auth": h.useLocalAuth,
+		"template_type":  "instructor",
+	})
+}
+
+// ExportGradebook handles GET /instructor/gradebook/export as CSV
+func (h *GradingHandlers) ExportGradebook(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	gradebook, err := h.gradingService.GetGradebook(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load gradebook"})
+		return
+	}
+
+	filename := fmt.Sprintf("gradebook-%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	header := []string{"student"}
+	for _, assignment := range gradebook.Assignments {
+		header = append(header, csvSafe(fmt.Sprintf("%s (%d)", assignment.Title, assignment.Points)))
+	}
+	for _, category := range gradebook.Categories {
+		header = append(header, csvSafe(category.Name+" %"))
+	}
+	header = append(header, "earned", "possible", "percent", "weighted_percent")
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write(header)
+	for _, student := range gradebook.Students {
+		row := []string{csvSafe(student.StudentName)}
+		for _, assignment := range gradebook.Assignments {
+			entry, ok := student.Entries[assignment.ID]
+			switch {
+			case !ok:
+				row = append(row, "")
+			case entry.Score != nil:
+				row = append(row, formatScore(*entry.Score))
+			case entry.Excused:
+				row = append(row, "excused")
+			default:
+				row = append(row, "")
+			}
+		}
+		for _, category := range gradebook.Categories {
+			row = append(row, formatOptionalScore(student.CategoryPercents[category.Name]))
+		}
+		row = append(row, formatScore(student.Earned), strconv.Itoa(student.Possible), formatOptionalScore(student.Percent), formatOptionalScore(student.WeightedPercent))
+		writer.Write(row)
+	}
+	writer.Flush()
+}
+
+// SetCategoryWeights handles PUT /instructor/gradebook/weights
+func (h *GradingHandlers) SetCategoryWeights(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req CategoryWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	weights, err := h.gradingService.SetCategoryWeights(instructor.ID, req.Weights)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Category weights saved successfully",
+		"weights": weights,
+	})
+}
+
+// GetStudentGrade handles GET /student/assignments/:id/grade
+func (h *GradingHandlers) GetStudentGrade(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	grade, err := h.gradingService.GetStudentGrade(uint(id), student.ID)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, grade)
+}
+
+// formatScore formats a score or percentage without trailing zeros
+func formatScore(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatOptionalScore formats a score that may not exist yet
+func formatOptionalScore(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatScore(*value)
+}
+
+// respondGradingError maps grading errors to HTTP responses
+func respondGradingError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"), strings.Contains(message, "not graded"):
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	case strings.Contains(message, "cannot"):
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+	case strings.Contains(message, "must"), strings.Contains(message, "required"),
+		strings.Contains(message, "expected"), strings.Contains(message, "too long"),
+		strings.Contains(message, "no point value"), strings.Contains(message, "at most"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+	default:
+		respondServiceError(c, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupHandlers handles student group operations
+type GroupHandlers struct {
+	groupService *services.GroupService
+}
+
+// NewGroupHandlers creates new group handlers
+func NewGroupHandlers(groupService *services.GroupService) *GroupHandlers {
+	return &GroupHandlers{
+		groupService: groupService,
+	}
+}
+
+// GroupRequest represents the request body for creating or updating a group
+type GroupRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Course      string `json:"course"`
+	StudentIDs  []uint `json:"student_ids"`
+}
+
+// GetGroups handles GET /instructor/groups
+func (h *GroupHandlers) GetGroups(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.groupService.GetGroups(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"groups": groups,
+		"total":  len(groups),
+	})
+}
+
+// CreateGroup handles POST /instructor/groups
+func (h *GroupHandlers) CreateGroup(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(userObj.ID, services.GroupInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Course:      req.Course,
+		StudentIDs:  req.StudentIDs,
+	})
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Group created successfully",
+		"group":   group,
+	})
+}
+
+// GetGroup handles GET /instructor/groups/:id
+func (h *GroupHandlers) GetGroup(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	group, err := h.groupService.GetGroup(uint(id), userObj.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group": group,
+	})
+}
+
+// UpdateGroup handles PUT /instructor/groups/:id
+func (h *GroupHandlers) UpdateGroup(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var req GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(uint(id), userObj.ID, services.GroupInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Course:      req.Course,
+		StudentIDs:  req.StudentIDs,
+	})
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group updated successfully",
+		"group":   group,
+	})
+}
+
+// DeleteGroup handles DELETE /instructor/groups/:id
+func (h *GroupHandlers) DeleteGroup(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if err := h.groupService.DeleteGroup(uint(id), userObj.ID); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group deleted successfully",
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InsightsHandlers handles a student's personal progress analytics
+type InsightsHandlers struct {
+	insightsService *services.InsightsService
+}
+
+// NewInsightsHandlers creates new insights handlers
+func NewInsightsHandlers(insightsService *services.InsightsService) *InsightsHandlers {
+	return &InsightsHandlers{insightsService: insightsService}
+}
+
+// UpdateGoalRequest sets the number of readings a student aims to complete each week
+type UpdateGoalRequest struct {
+	WeeklyCompletions int `json:"weekly_completions"`
+}
+
+// GetInsights handles GET /student/progress/insights. Days are counted in the IANA time zone
+// given by the tz query parameter, or the server's time zone without one.
+func (h *InsightsHandlers) GetInsights(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	loc := time.Local
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+			return
+		}
+	}
+
+	insights, err := h.insightsService.GetInsights(student.ID, time.Now(), loc)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"insights": insights})
+}
+
+// UpdateGoal handles PUT /student/progress/goal
+func (h *InsightsHandlers) UpdateGoal(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, err := h.insightsService.SetGoal(student.ID, req.WeeklyCompletions)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"goal": goal})
+}
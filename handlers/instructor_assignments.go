@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -66,11 +67,48 @@ func (h *InstructorAssignmentHandlers) GetAssignments(c *gin.Context) {
 
 // CreateAssignmentRequest represents the request body for creating an assignment
 type CreateAssignmentRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	URL         string `json:"url" binding:"required"`
-	Category    string `json:"category"`
-	DueDate     string `json:"due_date"` // ISO 8601 format
+	Title              string            `json:"title" binding:"required"`
+	Description        string            `json:"description"`
+	URL                string            `json:"url"` // defaults to the uploaded file or the first resource's URL
+	Category           string            `json:"category"`
+	DueDate            string            `json:"due_date"`     // ISO 8601 format
+	PublishAt          string            `json:"publish_at"`   // ISO 8601 format, empty publishes immediately
+	UnpublishAt        string            `json:"unpublish_at"` // ISO 8601 format, empty never hides
+	GracePeriodMinutes int               `json:"grace_period_minutes"`
+	MinReflectionWords int               `json:"min_reflection_words"` // 0 makes the reflection optional
+	Resources          []ResourceRequest `json:"resources"`
+	FileID             *uint             `json:"file_id"` // uploaded document to assign instead of a URL
+}
+
+// ResourceRequest represents one typed resource of an assignment
+type ResourceRequest struct {
+	ID               uint   `json:"id"` // omit to create a new resource
+	Type             string `json:"type" binding:"required"`
+	Title            string `json:"title" binding:"required"`
+	URL              string `json:"url" binding:"required"`
+	EstimatedMinutes int    `json:"estimated_minutes"`
+	Optional         bool   `json:"optional"`
+}
+
+// SetResourcesRequest represents the request body for replacing an assignment's resources
+type SetResourcesRequest struct {
+	Resources []ResourceRequest `json:"resources"`
+}
+
+// toResourceInputs converts resource requests into service input
+func toResourceInputs(requests []ResourceRequest) []services.ResourceInput {
+	inputs := make([]services.ResourceInput, 0, len(requests))
+	for _, req := range requests {
+		inputs = append(inputs, services.ResourceInput{
+			ID:               req.ID,
+			Type:             req.Type,
+			Title:            req.Title,
+			URL:              req.URL,
+			EstimatedMinutes: req.EstimatedMinutes,
+			Optional:         req.Optional,
+		})
+	}
+	return inputs
 }
 
 // CreateAssignment handles POST /instructor/assignments
@@ -126,27 +164,147 @@ func (h *InstructorAssignmentHandlers) CreateAssignment(c *gin.Context) {
 		dueDate = &parsedDate
 	}
 
-	// Create assignment
-	input := services.CreateAssignmentInput{
-		Title:       req.Title,
-		Description: req.Description,
-		URL:         req.URL,
-		Category:    req.Category,
-		DueDate:     dueDate,
+	// Parse release window if provided
+	publishAt, unpublishAt, err := parseReleaseWindow(req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	assignment, err := h.assignmentService.CreateAssignment(userObj.ID, input)
+	// Create assignment
+	input := services.CreateAssignmentInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		URL:                req.URL,
+		Category:           req.Category,
+		DueDate:            dueDate,
+		PublishAt:          publishAt,
+		UnpublishAt:        unpublishAt,
+		GracePeriodMinutes: req.GracePeriodMinutes,
+		MinReflectionWords: req.MinReflectionWords,
+		Resources:          toResourceInputs(req.Resources),
+		FileID:             req.FileID,
+	}
+
+	assignment, err := h.assignmentService.WithClientIP(c.ClientIP()).CreateAssignment(userObj.ID, input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message":    "Assignment created successfully",
 		"assignment": assignment,
+	}
+
+	// Duplicates are a warning; the assignment is created either way
+	duplicates, err := h.assignmentService.FindDuplicates(assignment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(duplicates) > 0 {
+		response["duplicates"] = duplicates
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CheckDuplicatesRequest lists reading URLs to check before creating assignments
+type CheckDuplicatesRequest struct {
+	URLs []string `json:"urls" binding:"required"`
+}
+
+// GetDuplicates handles GET /instructor/assignments/:id/duplicates
+func (h *InstructorAssignmentHandlers) GetDuplicates(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	report, err := h.assignmentService.GetDuplicateReport(uint(assignmentID), userObj.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CheckDuplicates handles POST /instructor/duplicate-check for imports of many readings
+func (h *InstructorAssignmentHandlers) CheckDuplicates(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req CheckDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	duplicates, err := h.assignmentService.CheckDuplicateURLs(userObj.ID, req.URLs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"duplicates": duplicates,
+		"count":      len(duplicates),
 	})
 }
 
+// parseReleaseWindow parses optional publish and unpublish times.
+// Date-only values start at the beginning of the day in local time.
+func parseReleaseWindow(publishAtStr, unpublishAtStr string) (*time.Time, *time.Time, error) {
+	publishAt, err := parseScheduleTime(publishAtStr)
+	if err != nil {
+		return nil, nil, errors.New("Invalid publish date format. Expected YYYY-MM-DD or YYYY-MM-DDTHH:MM")
+	}
+
+	unpublishAt, err := parseScheduleTime(unpublishAtStr)
+	if err != nil {
+		return nil, nil, errors.New("Invalid unpublish date format. Expected YYYY-MM-DD or YYYY-MM-DDTHH:MM")
+	}
+
+	return publishAt, unpublishAt, nil
+}
+
+// stringValue returns the string a pointer refers to, or empty for nil
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// parseScheduleTime parses an RFC3339, datetime-local or date-only value; empty input yields nil
+func parseScheduleTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &parsed, nil
+		}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
 // GetAssignment handles GET /instructor/assignments/:id
 func (h *InstructorAssignmentHandlers) GetAssignment(c *gin.Context) {
 	// Get user from context
@@ -184,11 +342,15 @@ func (h *InstructorAssignmentHandlers) GetAssignment(c *gin.Context) {
 
 // UpdateAssignmentRequest represents the request body for updating an assignment
 type UpdateAssignmentRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	URL         string `json:"url" binding:"required"`
-	Category    string `json:"category"`
-	DueDate     string `json:"due_date"` // ISO 8601 format
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	URL                string  `json:"url" binding:"required"`
+	Category           string  `json:"category"`
+	DueDate            string  `json:"due_date"`     // ISO 8601 format
+	PublishAt          *string `json:"publish_at"`   // ISO 8601 format, empty publishes immediately, omitted keeps the current date
+	UnpublishAt        *string `json:"unpublish_at"` // ISO 8601 format, empty never hides, omitted keeps the current date
+	GracePeriodMinutes *int    `json:"grace_period_minutes"`
+	MinReflectionWords *int    `json:"min_reflection_words"`
 }
 
 // UpdateAssignment handles PUT /instructor/assignments/:id
@@ -248,16 +410,31 @@ func (h *InstructorAssignmentHandlers) UpdateAssignment(c *gin.Context) {
 		dueDate = &parsedDate
 	}
 
-	// Update assignment
+	// Parse release window changes if provided
 	input := services.UpdateAssignmentInput{
-		Title:       req.Title,
-		Description: req.Description,
-		URL:         req.URL,
-		Category:    req.Category,
-		DueDate:     dueDate,
+		Title:              req.Title,
+		Description:        req.Description,
+		URL:                req.URL,
+		Category:           req.Category,
+		DueDate:            dueDate,
+		GracePeriodMinutes: req.GracePeriodMinutes,
+		MinReflectionWords: req.MinReflectionWords,
+	}
+	if req.PublishAt != nil || req.UnpublishAt != nil {
+		publishAt, unpublishAt, err := parseReleaseWindow(stringValue(req.PublishAt), stringValue(req.UnpublishAt))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.PublishAt != nil {
+			input.PublishAt = &publishAt
+		}
+		if req.UnpublishAt != nil {
+			input.UnpublishAt = &unpublishAt
+		}
 	}
 
-	err = h.assignmentService.UpdateAssignment(uint(id), userObj.ID, input)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).UpdateAssignment(uint(id), userObj.ID, input)
 	if err != nil {
 		if strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
@@ -296,7 +473,7 @@ func (h *InstructorAssignmentHandlers) DeleteAssignment(c *gin.Context) {
 	}
 
 	// Delete assignment
-	err = h.assignmentService.DeleteAssignment(uint(id), userObj.ID)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).DeleteAssignment(uint(id), userObj.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
@@ -311,6 +488,51 @@ func (h *InstructorAssignmentHandlers) DeleteAssignment(c *gin.Context) {
 	})
 }
 
+// SetResources handles PUT /instructor/assignments/:id/resources
+func (h *InstructorAssignmentHandlers) SetResources(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsInstructor() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Get assignment ID from URL
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req SetResourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resources, err := h.assignmentService.SetAssignmentResources(uint(id), userObj.ID, toResourceInputs(req.Resources))
+	if err != nil {
+		if strings.Contains(err.Error(), "access denied") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Resources updated successfully",
+		"resources": resources,
+	})
+}
+
 // AssignStudentsRequest represents the request body for assigning students
 type AssignStudentsRequest struct {
 	StudentIDs []uint `json:"student_ids" binding:"required"`
@@ -352,7 +574,7 @@ func (h *InstructorAssignmentHandlers) AssignStudents(c *gin.Context) {
 	}
 
 	// Assign students to assignment
-	err = h.assignmentService.AssignToMultipleStudents(uint(id), req.StudentIDs, userObj.ID)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).AssignToMultipleStudents(uint(id), req.StudentIDs, userObj.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
@@ -501,7 +723,7 @@ func (h *InstructorAssignmentHandlers) RemoveStudent(c *gin.Context) {
 	}
 
 	// Remove student from assignment
-	err = h.assignmentService.RemoveStudentAssignment(uint(id), req.StudentID, userObj.ID)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).RemoveStudentAssignment(uint(id), req.StudentID, userObj.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "access denied") {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
@@ -516,6 +738,124 @@ func (h *InstructorAssignmentHandlers) RemoveStudent(c *gin.Context) {
 	})
 }
 
+// ExtensionRequest represents the request body for granting a due date extension
+type ExtensionRequest struct {
+	DueDate string `json:"due_date"` // ISO 8601 format, empty removes the extension
+}
+
+// GrantExtension handles POST /instructor/assignments/:id/students/:student_id/extension
+func (h *InstructorAssignmentHandlers) GrantExtension(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsInstructor() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Get assignment and student IDs from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	// Parse request body
+	var req ExtensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dueDate, err := parseScheduleTime(req.DueDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid due date format. Expected YYYY-MM-DD or YYYY-MM-DDTHH:MM"})
+		return
+	}
+
+	studentAssignment, err := h.assignmentService.WithClientIP(c.ClientIP()).GrantExtension(uint(id), uint(studentID), userObj.ID, dueDate)
+	if err != nil {
+		if strings.Contains(err.Error(), "access denied") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Extension updated successfully",
+		"student_assignment": studentAssignment,
+	})
+}
+
+// ExcuseRequest represents the request body for excusing a student from a due date
+type ExcuseRequest struct {
+	Excused bool `json:"excused"`
+}
+
+// SetExcused handles POST /instructor/assignments/:id/students/:student_id/excuse
+func (h *InstructorAssignmentHandlers) SetExcused(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsInstructor() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Get assignment and student IDs from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	// Parse request body
+	var req ExcuseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studentAssignment, err := h.assignmentService.WithClientIP(c.ClientIP()).SetExcused(uint(id), uint(studentID), userObj.ID, req.Excused)
+	if err != nil {
+		if strings.Contains(err.Error(), "access denied") {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Excused status updated successfully",
+		"student_assignment": studentAssignment,
+	})
+}
+
 // GetAllStudents handles GET /instructor/students
 func (h *InstructorAssignmentHandlers) GetAllStudents(c *gin.Context) {
 	// Get user from context
@@ -573,28 +913,24 @@ func (h *InstructorAssignmentHandlers) GetDashboardStats(c *gin.Context) {
 		return
 	}
 
+	// Count statuses across all assignments with one grouped query
+	progress, err := h.assignmentService.GetInstructorProgress(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Calculate overall statistics
 	totalAssignments := len(assignments)
-	totalStudentAssignments := 0
-	totalCompleted := 0
-	totalInProgress := 0
-	totalAssigned := 0
+	totalAssigned := progress[models.StatusAssigned]
+	totalInProgress := progress[models.StatusInProgress]
+	totalCompleted := progress[models.StatusCompleted]
+	totalStudentAssignments := totalAssigned + totalInProgress + totalCompleted
 	overdueCount := 0
 
 	for _, assignment := range assignments {
-		progress, err := h.assignmentService.GetAssignmentProgress(assignment.ID, userObj.ID)
-		if err != nil {
-			continue
-		}
-
-		assignmentTotal := progress[models.StatusAssigned] + progress[models.StatusInProgress] + progress[models.StatusCompleted]
-		totalStudentAssignments += assignmentTotal
-		totalAssigned += progress[models.StatusAssigned]
-		totalInProgress += progress[models.StatusInProgress]
-		totalCompleted += progress[models.StatusCompleted]
-
 		// Check for overdue assignments
-		if assignment.DueDate != nil && assignment.DueDate.Before(time.Now()) {
+		if assignment.IsOverdue() {
 			overdueCount++
 		}
 	}
@@ -654,7 +990,7 @@ func (h *InstructorAssignmentHandlers) GetStudentProgress(c *gin.Context) {
 	}
 
 	// Get all student assignments for this student
-	studentAssignments, err := models.GetStudentAssignmentsByStudent(h.assignmentService.GetDB(), student.ID)
+	studentAssignments, err := models.GetAllStudentAssignmentsByStudent(h.assignmentService.GetDB(), student.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -678,7 +1014,7 @@ func (h *InstructorAssignmentHandlers) GetStudentProgress(c *gin.Context) {
 		}
 
 		// Check if overdue
-		if sa.Assignment.DueDate != nil && time.Now().After(*sa.Assignment.DueDate) && sa.Status != "completed" {
+		if sa.IsOverdue() {
 			overdueCount++
 		}
 	}
@@ -777,7 +1113,7 @@ func (h *InstructorAssignmentHandlers) ShowStudentAssignments(c *gin.Context) {
 	}
 
 	// Get student's current assignments to show which ones are already assigned
-	studentAssignments, err := models.GetStudentAssignmentsByStudent(h.assignmentService.GetDB(), student.ID)
+	studentAssignments, err := models.GetAllStudentAssignmentsByStudent(h.assignmentService.GetDB(), student.ID)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "base.html", gin.H{
 			"title": "Error",
@@ -860,7 +1196,13 @@ func (h *InstructorAssignmentHandlers) AssignToStudent(c *gin.Context) {
 	}
 
 	// Create the student assignment
-	studentAssignment, err := models.CreateStudentAssignment(h.assignmentService.GetDB(), uint(assignmentID), student.ID)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).AssignToStudent(uint(assignmentID), student.ID, userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign reading"})
+		return
+	}
+
+	studentAssignment, err := models.GetStudentAssignment(h.assignmentService.GetDB(), uint(assignmentID), student.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign reading"})
 		return
@@ -928,7 +1270,7 @@ func (h *InstructorAssignmentHandlers) RemoveFromStudent(c *gin.Context) {
 	}
 
 	// Remove the student assignment
-	err = models.RemoveStudentAssignment(h.assignmentService.GetDB(), uint(assignmentID), student.ID)
+	err = h.assignmentService.WithClientIP(c.ClientIP()).RemoveStudentAssignment(uint(assignmentID), student.ID, userObj.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove assignment"})
 		return
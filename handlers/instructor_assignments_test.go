@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"testing"
 	"time"
+	"zipcodereader/database"
 	"zipcodereader/models"
 	"zipcodereader/services"
 
@@ -17,14 +18,14 @@ import (
 )
 
 // setupTestDB creates a test database for testing
-func setupTestDB(t *testing.T) *gorm.DB {
+func setupTestDB(t testing.TB) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 
 	// Auto-migrate models
-	err = db.AutoMigrate(&models.User{}, &models.Assignment{}, &models.StudentAssignment{})
+	err = db.AutoMigrate(models.All()...)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -33,7 +34,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 }
 
 // createTestUser creates a test user
-func createTestUser(t *testing.T, db *gorm.DB, username, role string) *models.User {
+func createTestUser(t testing.TB, db *gorm.DB, username, role string) *models.User {
 	user := &models.User{
 		Username: username,
 		Email:    username + "@example.com",
@@ -312,6 +313,54 @@ func TestUpdateAssignment(t *testing.T) {
 	}
 }
 
+func TestUpdateAssignmentKeepsOmittedSettings(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := services.NewAssignmentService(db)
+	handlers := NewInstructorAssignmentHandlers(assignmentService)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	router := setupTestRouter(handlers, instructor)
+
+	publishAt := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, services.CreateAssignmentInput{
+		Title:              "Essay",
+		URL:                "https://example.com/essay",
+		PublishAt:          &publishAt,
+		GracePeriodMinutes: 30,
+		MinReflectionWords: 50,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	put := func(body string) {
+		req, _ := http.NewRequest("PUT", "/instructor/assignments/"+strconv.Itoa(int(assignment.ID)), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	put(`{"title": "Essay, revised", "url": "https://example.com/essay"}`)
+	updated, err := models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get assignment: %v", err)
+	}
+	if updated.GracePeriodMinutes != 30 || updated.MinReflectionWords != 50 || updated.PublishAt == nil || !updated.PublishAt.Equal(publishAt) {
+		t.Errorf("Expected a PUT without settings to keep them, got %+v", updated)
+	}
+
+	put(`{"title": "Essay", "url": "https://example.com/essay", "publish_at": "", "min_reflection_words": 0}`)
+	updated, err = models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get assignment: %v", err)
+	}
+	if updated.PublishAt != nil || updated.MinReflectionWords != 0 || updated.GracePeriodMinutes != 30 {
+		t.Errorf("Expected only the sent settings to change, got %+v", updated)
+	}
+}
+
 func TestDeleteAssignment(t *testing.T) {
 	db := setupTestDB(t)
 	assignmentService := services.NewAssignmentService(db)
@@ -592,3 +641,159 @@ func TestCreateAssignmentWithDueDate(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", w.Code)
 	}
 }
+
+// seedDashboard creates an instructor whose assignments are each assigned to every student,
+// with the statuses cycling through assigned, in progress and completed
+func seedDashboard(tb testing.TB, db *gorm.DB, assignmentCount, studentCount int) *models.User {
+	instructor := createTestUser(tb, db, "instructor1", "instructor")
+	students := make([]*models.User, studentCount)
+	for j := range students {
+		students[j] = createTestUser(tb, db, "student"+strconv.Itoa(j+1), "student")
+	}
+
+	// The indexes database.Initialize creates
+	if err := database.CreateIndexes(db); err != nil {
+		tb.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	statuses := []string{models.StatusAssigned, models.StatusInProgress, models.StatusCompleted}
+	for i := 0; i < assignmentCount; i++ {
+		assignment := &models.Assignment{Title: "Reading " + strconv.Itoa(i+1), URL: "https://example.com/" + strconv.Itoa(i+1), CreatedByID: instructor.ID}
+		if i%2 == 1 {
+			dueDate := time.Now().AddDate(0, 0, -1)
+			assignment.DueDate = &dueDate
+		}
+		if err := db.Create(assignment).Error; err != nil {
+			tb.Fatalf("Failed to create assignment: %v", err)
+		}
+		for j, student := range students {
+			sa := &models.StudentAssignment{AssignmentID: assignment.ID, StudentID: student.ID, Status: statuses[(i+j)%len(statuses)]}
+			if err := db.Create(sa).Error; err != nil {
+				tb.Fatalf("Failed to create student assignment: %v", err)
+			}
+		}
+	}
+	return instructor
+}
+
+func TestGetDashboardStatsTotals(t *testing.T) {
+	db := setupTestDB(t)
+	handlers := NewInstructorAssignmentHandlers(services.NewAssignmentService(db))
+	instructor := seedDashboard(t, db, 4, 3)
+
+	// Another instructor's readings stay out of the totals
+	other := createTestUser(t, db, "instructor2", "instructor")
+	otherStudent := createTestUser(t, db, "other-student", "student")
+	otherAssignment := &models.Assignment{Title: "Other", URL: "https://example.com/other", CreatedByID: other.ID}
+	if err := db.Create(otherAssignment).Error; err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := db.Create(&models.StudentAssignment{AssignmentID: otherAssignment.ID, StudentID: otherStudent.ID, Status: models.StatusCompleted}).Error; err != nil {
+		t.Fatalf("Failed to create student assignment: %v", err)
+	}
+
+	router := setupTestRouter(handlers, instructor)
+	req, _ := http.NewRequest("GET", "/instructor/dashboard/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	expected := map[string]float64{
+		"total_assignments":         4,
+		"total_student_assignments": 12,
+		"total_assigned":            4,
+		"total_in_progress":         4,
+		"total_completed":           4,
+		"overdue_count":             2,
+	}
+	for key, value := range expected {
+		if response[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, response[key])
+		}
+	}
+
+	// The grouped query agrees with summing each assignment's progress
+	service := services.NewAssignmentService(db)
+	grouped, err := service.GetInstructorProgress(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	perAssignment, err := dashboardProgressPerAssignment(service, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress per assignment: %v", err)
+	}
+	for _, status := range []string{models.StatusAssigned, models.StatusInProgress, models.StatusCompleted} {
+		if grouped[status] != perAssignment[status] {
+			t.Errorf("Expected %d %s readings, got %d", perAssignment[status], status, grouped[status])
+		}
+	}
+}
+
+// dashboardProgressPerAssignment counts statuses the way GetDashboardStats did before the grouped
+// query, with one progress query per assignment. It is the baseline for BenchmarkDashboardProgressGrouped.
+func dashboardProgressPerAssignment(service *services.AssignmentService, instructorID uint) (map[string]int, error) {
+	assignments, err := service.GetAssignmentsByInstructor(instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int)
+	for _, assignment := range assignments {
+		progress, err := service.GetAssignmentProgress(assignment.ID, instructorID)
+		if err != nil {
+			return nil, err
+		}
+		for status, count := range progress {
+			totals[status] += count
+		}
+	}
+	return totals, nil
+}
+
+func BenchmarkDashboardProgressPerAssignment(b *testing.B) {
+	db := setupTestDB(b)
+	service := services.NewAssignmentService(db)
+	instructor := seedDashboard(b, db, 200, 30)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dashboardProgressPerAssignment(service, instructor.ID); err != nil {
+			b.Fatalf("Failed to get progress: %v", err)
+		}
+	}
+}
+
+func BenchmarkDashboardProgressGrouped(b *testing.B) {
+	db := setupTestDB(b)
+	service := services.NewAssignmentService(db)
+	instructor := seedDashboard(b, db, 200, 30)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetInstructorProgress(instructor.ID); err != nil {
+			b.Fatalf("Failed to get progress: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetDashboardStats(b *testing.B) {
+	db := setupTestDB(b)
+	handlers := NewInstructorAssignmentHandlers(services.NewAssignmentService(db))
+	instructor := seedDashboard(b, db, 200, 30)
+	router := setupTestRouter(handlers, instructor)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest("GET", "/instructor/dashboard/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			b.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+}
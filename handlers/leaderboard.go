@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaderboardHandlers handles opt-in class leaderboards
+type LeaderboardHandlers struct {
+	leaderboardService *services.LeaderboardService
+}
+
+// NewLeaderboardHandlers creates new leaderboard handlers
+func NewLeaderboardHandlers(leaderboardService *services.LeaderboardService) *LeaderboardHandlers {
+	return &LeaderboardHandlers{leaderboardService: leaderboardService}
+}
+
+// LeaderboardSettingsRequest turns an instructor's leaderboard on or off
+type LeaderboardSettingsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// GetInstructorLeaderboard handles GET /instructor/leaderboard
+func (h *LeaderboardHandlers) GetInstructorLeaderboard(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := h.leaderboardService.GetInstructorLeaderboard(instructor)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leaderboard": leaderboard})
+}
+
+// UpdateLeaderboardSettings handles PUT /instructor/leaderboard/settings
+func (h *LeaderboardHandlers) UpdateLeaderboardSettings(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req LeaderboardSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.leaderboardService.SetEnabled(instructor.ID, req.Enabled)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// GetStudentLeaderboards handles GET /student/leaderboards
+func (h *LeaderboardHandlers) GetStudentLeaderboards(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	leaderboards, err := h.leaderboardService.GetStudentLeaderboards(student.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leaderboards": leaderboards})
+}
+
+// GetLeaderboardProfile handles GET /student/leaderboard/profile
+func (h *LeaderboardHandlers) GetLeaderboardProfile(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.leaderboardService.GetProfile(student.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profile": profile})
+}
+
+// UpdateLeaderboardProfile handles PUT /student/leaderboard/profile
+func (h *LeaderboardHandlers) UpdateLeaderboardProfile(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input services.LeaderboardProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.leaderboardService.SaveProfile(student.ID, input)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profile": profile})
+}
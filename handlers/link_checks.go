@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkCheckHandlers handles link-rot reports for instructors
+type LinkCheckHandlers struct {
+	linkCheckerService *services.LinkCheckerService
+}
+
+// NewLinkCheckHandlers creates new link check handlers
+func NewLinkCheckHandlers(linkCheckerService *services.LinkCheckerService) *LinkCheckHandlers {
+	return &LinkCheckHandlers{
+		linkCheckerService: linkCheckerService,
+	}
+}
+
+// GetLinkProblems handles GET /instructor/link-checks
+func (h *LinkCheckHandlers) GetLinkProblems(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	checks, err := h.linkCheckerService.GetLinkProblems(instructor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"link_checks": checks,
+		"count":       len(checks),
+	})
+}
+
+// CheckAssignment handles POST /instructor/assignments/:id/check-link
+func (h *LinkCheckHandlers) CheckAssignment(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	check, err := h.linkCheckerService.CheckAssignment(uint(assignmentID), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"link_check": check,
+	})
+}
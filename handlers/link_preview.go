@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkPreviewHandlers handles link metadata lookups
+type LinkPreviewHandlers struct {
+	linkPreviewService *services.LinkPreviewService
+}
+
+// NewLinkPreviewHandlers creates new link preview handlers
+func NewLinkPreviewHandlers(linkPreviewService *services.LinkPreviewService) *LinkPreviewHandlers {
+	return &LinkPreviewHandlers{
+		linkPreviewService: linkPreviewService,
+	}
+}
+
+// GetPreview handles GET /instructor/link-preview?url=
+func (h *LinkPreviewHandlers) GetPreview(c *gin.Context) {
+	if _, ok := instructorFromContext(c); !ok {
+		return
+	}
+
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	preview, err := h.linkPreviewService.GetPreview(rawURL)
+	if err != nil {
+		if errors.Is(err, services.ErrBlockedAddress) || !strings.HasPrefix(err.Error(), "failed to fetch") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preview": preview,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"zipcodereader/models"
+	"zipcodereader/services"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -12,13 +13,15 @@ import (
 
 // LocalAuthHandler handles local authentication requests
 type LocalAuthHandler struct {
-	db *gorm.DB
+	db           *gorm.DB
+	auditService *services.AuditService
 }
 
 // NewLocalAuthHandler creates a new local authentication handler
-func NewLocalAuthHandler(db *gorm.DB) *LocalAuthHandler {
+func NewLocalAuthHandler(db *gorm.DB, auditService *services.AuditService) *LocalAuthHandler {
 	return &LocalAuthHandler{
-		db: db,
+		db:           db,
+		auditService: auditService,
 	}
 }
 
@@ -47,6 +50,7 @@ func (h *LocalAuthHandler) Login(c *gin.Context) {
 	// Authenticate user
 	user, err := models.AuthenticateLocalUser(h.db, username, password)
 	if err != nil {
+		h.auditService.RecordFailedLogin(username, c.ClientIP())
 		c.HTML(http.StatusUnauthorized, "local_login.html", gin.H{
 			"title":          "Login",
 			"error":          "Invalid credentials",
@@ -61,6 +65,8 @@ func (h *LocalAuthHandler) Login(c *gin.Context) {
 	session.Set("user_role", user.Role)
 	session.Save()
 
+	h.auditService.RecordLogin(user, c.ClientIP())
+
 	c.Redirect(http.StatusSeeOther, "/dashboard")
 }
 
@@ -125,6 +131,8 @@ func (h *LocalAuthHandler) Register(c *gin.Context) {
 	session.Set("user_role", user.Role)
 	session.Save()
 
+	h.auditService.RecordRegistration(user, c.ClientIP())
+
 	c.Redirect(http.StatusSeeOther, "/dashboard")
 }
 
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandlers handles in-app notification operations
+type NotificationHandlers struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandlers creates new notification handlers
+func NewNotificationHandlers(notificationService *services.NotificationService) *NotificationHandlers {
+	return &NotificationHandlers{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications handles GET /notifications
+func (h *NotificationHandlers) GetNotifications(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, err := h.notificationService.GetNotifications(userObj.ID, unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"total":         len(notifications),
+	})
+}
+
+// MarkAsRead handles POST /notifications/:id/read
+func (h *NotificationHandlers) MarkAsRead(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+
+	// Get notification ID from URL
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = h.notificationService.MarkAsRead(uint(id), userObj.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification marked as read",
+	})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PeerReviewHandlers handles peer review of student reflections
+type PeerReviewHandlers struct {
+	peerReviewService *services.PeerReviewService
+}
+
+// NewPeerReviewHandlers creates new peer review handlers
+func NewPeerReviewHandlers(peerReviewService *services.PeerReviewService) *PeerReviewHandlers {
+	return &PeerReviewHandlers{peerReviewService: peerReviewService}
+}
+
+// SubmitPeerReviewRequest represents a reviewer's rubric scores, in rubric order, and comments
+type SubmitPeerReviewRequest struct {
+	Scores   []int  `json:"scores"`
+	Comments string `json:"comments"`
+}
+
+// GetPeerReviews handles GET /instructor/assignments/:id/peer-reviews
+func (h *PeerReviewHandlers) GetPeerReviews(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	report, err := h.peerReviewService.GetReport(uint(id), instructor.ID)
+	if err != nil {
+		respondPeerReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// SavePeerReviewSettings handles PUT /instructor/assignments/:id/peer-reviews
+func (h *PeerReviewHandlers) SavePeerReviewSettings(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req services.PeerReviewSettingsInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setup, err := h.peerReviewService.SaveSettings(uint(id), instructor.ID, req)
+	if err != nil {
+		respondPeerReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Peer review settings saved successfully",
+		"setup":   setup,
+	})
+}
+
+// AllocatePeerReviews handles POST /instructor/assignments/:id/peer-reviews/allocate
+func (h *PeerReviewHandlers) AllocatePeerReviews(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	report, err := h.peerReviewService.Allocate(uint(id), instructor.ID)
+	if err != nil {
+		respondPeerReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetStudentPeerReviews handles GET /student/assignments/:id/peer-reviews
+func (h *PeerReviewHandlers) GetStudentPeerReviews(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	reviews, err := h.peerReviewService.GetStudentPeerReviews(uint(id), student.ID)
+	if err != nil {
+		respondPeerReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// SubmitPeerReview handles PUT /student/peer-reviews/:id
+func (h *PeerReviewHandlers) SubmitPeerReview(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req SubmitPeerReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.peerReviewService.SubmitReview(uint(id), student.ID, req.Scores, req.Comments)
+	if err != nil {
+		respondPeerReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review submitted successfully",
+		"review":  review,
+	})
+}
+
+// respondPeerReviewError maps peer review errors to HTTP responses
+func respondPeerReviewError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not set up"), strings.Contains(message, "assignment not found"),
+		strings.Contains(message, "review not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	case strings.Contains(message, "closed"), strings.Contains(message, "already been submitted"),
+		strings.Contains(message, "at least two students"):
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+	case strings.Contains(message, "must"), strings.Contains(message, "expected"),
+		strings.Contains(message, "too long"), strings.Contains(message, "write feedback"),
+		strings.Contains(message, "cannot change"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+	default:
+		respondServiceError(c, err, http.StatusInternalServerError)
+	}
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"zipcodereader/models"
 	"zipcodereader/services"
 
@@ -56,6 +62,73 @@ func (h *ProgressTrackingHandlers) GetDetailedProgressReport(c *gin.Context) {
 	})
 }
 
+// ExportDetailedProgressReport handles GET /instructor/assignments/:id/detailed-progress/export as CSV
+func (h *ProgressTrackingHandlers) ExportDetailedProgressReport(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	report, err := h.progressService.GetDetailedProgressReport(uint(id), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusNotFound)
+		return
+	}
+
+	filename := fmt.Sprintf("progress-assignment-%d.csv", report.AssignmentID)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"student", "email", "status", "assigned_at", "completed_at", "completion_timing", "time_to_complete_hours", "active_reading_minutes", "link_opens", "first_opened_at", "completed_without_opening", "reflection_words", "quiz_attempts", "quiz_best_percent", "quiz_passed"})
+	for _, detail := range report.StudentDetails {
+		completedAt := ""
+		if detail.CompletedAt != nil {
+			completedAt = detail.CompletedAt.Format(time.RFC3339)
+		}
+		timeToComplete := ""
+		if detail.TimeToComplete != nil {
+			timeToComplete = strconv.Itoa(*detail.TimeToComplete)
+		}
+		firstOpenedAt := ""
+		if detail.FirstOpenedAt != nil {
+			firstOpenedAt = detail.FirstOpenedAt.Format(time.RFC3339)
+		}
+		quizBest := ""
+		if detail.QuizBestPercent != nil {
+			quizBest = strconv.Itoa(*detail.QuizBestPercent)
+		}
+		writer.Write([]string{
+			csvSafe(detail.StudentName),
+			csvSafe(detail.StudentEmail),
+			detail.Status,
+			detail.AssignedAt.Format(time.RFC3339),
+			completedAt,
+			detail.CompletionTiming,
+			timeToComplete,
+			strconv.Itoa(detail.ActiveMinutes),
+			strconv.Itoa(detail.LinkOpenCount),
+			firstOpenedAt,
+			strconv.FormatBool(detail.Unopened),
+			strconv.Itoa(detail.ReflectionWords),
+			strconv.Itoa(detail.QuizAttempts),
+			quizBest,
+			strconv.FormatBool(detail.QuizPassed),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("failed to export progress for assignment %d: %v", report.AssignmentID, err)
+	}
+}
+
 // GetInstructorProgressSummary handles GET /instructor/progress/summary
 func (h *ProgressTrackingHandlers) GetInstructorProgressSummary(c *gin.Context) {
 	// Get user from context
@@ -139,6 +212,7 @@ func (h *ProgressTrackingHandlers) GetCompletionAnalytics(c *gin.Context) {
 	analytics := gin.H{
 		"overall_completion_rate":   summary.OverallCompletionRate,
 		"average_completion_time":   summary.AverageCompletionTime,
+		"average_active_minutes":    summary.AverageActiveMinutes,
 		"total_assignments":         summary.TotalAssignments,
 		"total_student_assignments": summary.TotalStudentAssignments,
 		"overdue_assignments":       summary.OverdueAssignments,
@@ -151,3 +225,47 @@ func (h *ProgressTrackingHandlers) GetCompletionAnalytics(c *gin.Context) {
 		"analytics": analytics,
 	})
 }
+
+// GetCohortAnalytics handles GET /instructor/progress/cohorts. The dimension query parameter
+// picks the grouping (category, assignment, week, month, group or course); category narrows to one category and from/to (YYYY-MM-DD, inclusive)
+// limit the readings to those assigned in that range.
+func (h *ProgressTrackingHandlers) GetCohortAnalytics(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	query := services.CohortQuery{
+		Dimension: c.Query("dimension"),
+		Category:  c.Query("category"),
+	}
+	if from := c.Query("from"); from != "" {
+		date, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return
+		}
+		query.From = &date
+	}
+	if to := c.Query("to"); to != "" {
+		date, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		end := date.AddDate(0, 0, 1)
+		query.To = &end
+	}
+
+	analytics, err := h.progressService.GetCohortAnalytics(instructor.ID, query)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"analytics": analytics})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuizHandlers handles comprehension quizzes for instructors and students
+type QuizHandlers struct {
+	quizService *services.QuizService
+}
+
+// NewQuizHandlers creates new quiz handlers
+func NewQuizHandlers(quizService *services.QuizService) *QuizHandlers {
+	return &QuizHandlers{quizService: quizService}
+}
+
+// SubmitQuizRequest represents a student's answers keyed by question ID
+type SubmitQuizRequest struct {
+	Answers map[uint]string `json:"answers" binding:"required"`
+}
+
+// GetQuiz handles GET /instructor/assignments/:id/quiz
+func (h *QuizHandlers) GetQuiz(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	quiz, err := h.quizService.GetQuiz(uint(id), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quiz": quiz})
+}
+
+// SaveQuiz handles PUT /instructor/assignments/:id/quiz
+func (h *QuizHandlers) SaveQuiz(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req services.QuizInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quiz, err := h.quizService.SaveQuiz(uint(id), instructor.ID, req)
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Quiz saved successfully",
+		"quiz":    quiz,
+	})
+}
+
+// DeleteQuiz handles DELETE /instructor/assignments/:id/quiz
+func (h *QuizHandlers) DeleteQuiz(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	if err := h.quizService.DeleteQuiz(uint(id), instructor.ID); err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Quiz deleted successfully"})
+}
+
+// GetStudentQuiz handles GET /student/assignments/:id/quiz
+func (h *QuizHandlers) GetStudentQuiz(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	quiz, err := h.quizService.GetStudentQuiz(uint(id), student.ID)
+	if err != nil {
+		respondQuizError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+// SubmitQuiz handles POST /student/assignments/:id/quiz/attempts
+func (h *QuizHandlers) SubmitQuiz(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req SubmitQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := h.quizService.SubmitAttempt(uint(id), student.ID, req.Answers)
+	if err != nil {
+		respondQuizError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Quiz submitted",
+		"attempt": attempt,
+	})
+}
+
+// respondQuizError maps student quiz errors to HTTP responses
+func respondQuizError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "no quiz attempts remaining"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
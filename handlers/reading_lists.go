@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingListHandlers handles reading list operations
+type ReadingListHandlers struct {
+	readingListService *services.ReadingListService
+}
+
+// NewReadingListHandlers creates new reading list handlers
+func NewReadingListHandlers(readingListService *services.ReadingListService) *ReadingListHandlers {
+	return &ReadingListHandlers{
+		readingListService: readingListService,
+	}
+}
+
+// ReadingListItemRequest describes one item of a reading list
+type ReadingListItemRequest struct {
+	AssignmentID  uint  `json:"assignment_id" binding:"required"`
+	Prerequisites []int `json:"prerequisites"` // zero-based positions of earlier items
+}
+
+// CreateReadingListRequest represents the request body for creating a reading list
+type CreateReadingListRequest struct {
+	Title       string                   `json:"title" binding:"required"`
+	Description string                   `json:"description"`
+	Sequential  *bool                    `json:"sequential"` // defaults to true
+	Items       []ReadingListItemRequest `json:"items" binding:"required"`
+}
+
+// AssignReadingListRequest represents the request body for assigning a reading list
+type AssignReadingListRequest struct {
+	StudentIDs []uint `json:"student_ids"`
+	GroupIDs   []uint `json:"group_ids"`
+}
+
+// GetReadingLists handles GET /instructor/reading-lists
+func (h *ReadingListHandlers) GetReadingLists(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	lists, err := h.readingListService.GetReadingLists(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reading_lists": lists,
+		"total":         len(lists),
+	})
+}
+
+// CreateReadingList handles POST /instructor/reading-lists
+func (h *ReadingListHandlers) CreateReadingList(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req CreateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input := services.CreateReadingListInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Sequential:  req.Sequential == nil || *req.Sequential,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, services.ReadingListItemInput{
+			AssignmentID:  item.AssignmentID,
+			Prerequisites: item.Prerequisites,
+		})
+	}
+
+	list, err := h.readingListService.CreateReadingList(userObj.ID, input)
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Reading list created successfully",
+		"reading_list": list,
+	})
+}
+
+// GetReadingList handles GET /instructor/reading-lists/:id
+func (h *ReadingListHandlers) GetReadingList(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading list ID"})
+		return
+	}
+
+	list, err := h.readingListService.GetReadingList(uint(id), userObj.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reading_list": list,
+	})
+}
+
+// DeleteReadingList handles DELETE /instructor/reading-lists/:id
+func (h *ReadingListHandlers) DeleteReadingList(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading list ID"})
+		return
+	}
+
+	if err := h.readingListService.DeleteReadingList(uint(id), userObj.ID); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reading list deleted successfully",
+	})
+}
+
+// AssignReadingList handles POST /instructor/reading-lists/:id/assign
+func (h *ReadingListHandlers) AssignReadingList(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading list ID"})
+		return
+	}
+
+	var req AssignReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.readingListService.WithClientIP(c.ClientIP()).AssignReadingList(uint(id), req.StudentIDs, req.GroupIDs, userObj.ID); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reading list assigned successfully",
+	})
+}
+
+// GetStudentReadingLists handles GET /student/reading-lists
+func (h *ReadingListHandlers) GetStudentReadingLists(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsStudent() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	lists, err := h.readingListService.GetStudentReadingLists(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reading_lists": lists,
+		"total":         len(lists),
+	})
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingNoteHandlers handles student reading notes and instructor access to reflections
+type ReadingNoteHandlers struct {
+	readingNoteService *services.ReadingNoteService
+}
+
+// NewReadingNoteHandlers creates new reading note handlers
+func NewReadingNoteHandlers(readingNoteService *services.ReadingNoteService) *ReadingNoteHandlers {
+	return &ReadingNoteHandlers{readingNoteService: readingNoteService}
+}
+
+// SaveNoteRequest represents the Markdown body of a reading note
+type SaveNoteRequest struct {
+	Content string `json:"content"`
+}
+
+// GetNote handles GET /student/assignments/:id/note
+func (h *ReadingNoteHandlers) GetNote(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	note, err := h.readingNoteService.GetNote(uint(id), student.ID)
+	if err != nil {
+		respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"note": note})
+}
+
+// SaveNote handles PUT /student/assignments/:id/note
+func (h *ReadingNoteHandlers) SaveNote(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req SaveNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.readingNoteService.SaveNote(uint(id), student.ID, req.Content)
+	if err != nil {
+		respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Note saved successfully",
+		"note":    note,
+	})
+}
+
+// GetReflections handles GET /instructor/assignments/:id/reflections
+func (h *ReadingNoteHandlers) GetReflections(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	assignment, reflections, err := h.readingNoteService.GetReflections(uint(id), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignment_id":        assignment.ID,
+		"min_reflection_words": assignment.MinReflectionWords,
+		"reflections":          reflections,
+		"count":                len(reflections),
+	})
+}
+
+// ExportReflections handles GET /instructor/assignments/:id/reflections/export as CSV
+func (h *ReadingNoteHandlers) ExportReflections(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	assignment, reflections, err := h.readingNoteService.GetReflections(uint(id), instructor.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("reflections-assignment-%d.csv", assignment.ID)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"student", "status", "completed_at", "word_count", "updated_at", "reflection"})
+	for _, reflection := range reflections {
+		completedAt := ""
+		if reflection.CompletedAt != nil {
+			completedAt = reflection.CompletedAt.Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			csvSafe(reflection.StudentName),
+			reflection.Status,
+			completedAt,
+			strconv.Itoa(reflection.WordCount),
+			reflection.UpdatedAt.Format(time.RFC3339),
+			csvSafe(reflection.Content),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("failed to export reflections for assignment %d: %v", assignment.ID, err)
+	}
+}
+
+// csvSafe keeps spreadsheet applications from evaluating student text as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// respondNoteError maps reading note errors to HTTP responses
+func respondNoteError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return
+	}
+	if strings.Contains(err.Error(), "too long") {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingSessionHandlers handles the reading timer and time-on-task heartbeats
+type ReadingSessionHandlers struct {
+	readingSessionService *services.ReadingSessionService
+}
+
+// NewReadingSessionHandlers creates new reading session handlers
+func NewReadingSessionHandlers(readingSessionService *services.ReadingSessionService) *ReadingSessionHandlers {
+	return &ReadingSessionHandlers{readingSessionService: readingSessionService}
+}
+
+// StartSessionRequest names what started a reading session
+type StartSessionRequest struct {
+	Source string `json:"source" binding:"required"`
+}
+
+// StartSession handles POST /student/assignments/:id/reading-sessions
+func (h *ReadingSessionHandlers) StartSession(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.readingSessionService.StartSession(uint(id), student.ID, req.Source)
+	if err != nil {
+		respondReadingSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"session": session})
+}
+
+// GetReadingTime handles GET /student/assignments/:id/reading-time
+func (h *ReadingSessionHandlers) GetReadingTime(c *gin.Context) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	readingTime, err := h.readingSessionService.GetReadingTime(uint(id), student.ID)
+	if err != nil {
+		respondReadingSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active_seconds": readingTime.ActiveSeconds,
+		"active_minutes": readingTime.ActiveMinutes(),
+		"sessions":       readingTime.Sessions,
+	})
+}
+
+// Heartbeat handles POST /student/reading-sessions/:id/heartbeat
+func (h *ReadingSessionHandlers) Heartbeat(c *gin.Context) {
+	h.record(c, h.readingSessionService.Heartbeat)
+}
+
+// StopSession handles POST /student/reading-sessions/:id/stop, which pages also send as a beacon when they close
+func (h *ReadingSessionHandlers) StopSession(c *gin.Context) {
+	h.record(c, h.readingSessionService.StopSession)
+}
+
+// record passes a heartbeat or stop for the session in the URL to the service
+func (h *ReadingSessionHandlers) record(c *gin.Context, record func(sessionID uint, studentID uint) (*models.ReadingSession, error)) {
+	student, ok := studentFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, err := record(uint(id), student.ID)
+	if err != nil {
+		respondReadingSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+// respondReadingSessionError maps reading session errors to HTTP responses
+func respondReadingSessionError(c *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": message})
+	case strings.Contains(message, "has ended"):
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+	case strings.Contains(message, "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+	default:
+		respondServiceError(c, err, http.StatusInternalServerError)
+	}
+}
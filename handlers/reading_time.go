@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadingTimeHandlers handles word counts and reading time estimates
+type ReadingTimeHandlers struct {
+	contentExtractionService *services.ContentExtractionService
+}
+
+// NewReadingTimeHandlers creates new reading time handlers
+func NewReadingTimeHandlers(contentExtractionService *services.ContentExtractionService) *ReadingTimeHandlers {
+	return &ReadingTimeHandlers{
+		contentExtractionService: contentExtractionService,
+	}
+}
+
+// SetEstimateRequest represents a request to override an assignment's reading time;
+// a null estimate restores the computed one
+type SetEstimateRequest struct {
+	EstimatedMinutes *int `json:"estimated_minutes"`
+}
+
+// ExtractAssignment handles POST /instructor/assignments/:id/extract
+func (h *ReadingTimeHandlers) ExtractAssignment(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	assignment, err := h.contentExtractionService.ExtractAssignment(uint(assignmentID), instructor.ID)
+	if err != nil {
+		if !errors.Is(err, services.ErrBlockedAddress) && strings.HasPrefix(err.Error(), "failed to fetch") {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignment": assignment,
+	})
+}
+
+// SetEstimate handles PUT /instructor/assignments/:id/estimate
+func (h *ReadingTimeHandlers) SetEstimate(c *gin.Context) {
+	instructor, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req SetEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.contentExtractionService.SetEstimateOverride(uint(assignmentID), instructor.ID, req.EstimatedMinutes)
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reading time updated successfully",
+		"assignment": assignment,
+	})
+}
@@ -147,6 +147,10 @@ func (h *StudentAssignmentHandlers) UpdateStatus(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
 			return
 		}
+		if strings.Contains(err.Error(), "locked") || models.IsCompletionRequirementError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "invalid status") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
 			return
@@ -190,6 +194,10 @@ func (h *StudentAssignmentHandlers) MarkAsCompleted(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
 			return
 		}
+		if strings.Contains(err.Error(), "locked") || models.IsCompletionRequirementError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -229,6 +237,10 @@ func (h *StudentAssignmentHandlers) MarkAsInProgress(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
 			return
 		}
+		if strings.Contains(err.Error(), "locked") || models.IsCompletionRequirementError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -494,3 +506,101 @@ func (h *StudentAssignmentHandlers) SearchAssignments(c *gin.Context) {
 		"query":       query,
 	})
 }
+
+// SetResourceCompletedRequest represents the request body for marking a resource
+type SetResourceCompletedRequest struct {
+	Completed bool `json:"completed"`
+}
+
+// GetResources handles GET /student/assignments/:id/resources
+func (h *StudentAssignmentHandlers) GetResources(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsStudent() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Get student assignment ID from URL
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	resources, err := h.studentService.GetResourceProgress(uint(id), userObj.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resources": resources,
+		"total":     len(resources),
+	})
+}
+
+// SetResourceCompleted handles POST /student/assignments/:id/resources/:resource_id
+func (h *StudentAssignmentHandlers) SetResourceCompleted(c *gin.Context) {
+	// Get user from context
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsStudent() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Get student assignment and resource IDs from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	resourceID, err := strconv.ParseUint(c.Param("resource_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	var req SetResourceCompletedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studentAssignment, err := h.studentService.SetResourceCompleted(uint(id), uint(resourceID), userObj.ID, req.Completed)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "locked") || models.IsCompletionRequirementError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Resource progress updated successfully",
+		"status":  studentAssignment.Status,
+	})
+}
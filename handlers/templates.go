@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zipcodereader/models"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandlers handles assignment template and recurrence operations
+type TemplateHandlers struct {
+	templateService   *services.TemplateService
+	recurrenceService *services.RecurrenceService
+}
+
+// NewTemplateHandlers creates new template handlers
+func NewTemplateHandlers(templateService *services.TemplateService, recurrenceService *services.RecurrenceService) *TemplateHandlers {
+	return &TemplateHandlers{
+		templateService:   templateService,
+		recurrenceService: recurrenceService,
+	}
+}
+
+// TemplateRequest represents the request body for creating or updating a template
+type TemplateRequest struct {
+	Name               string `json:"name" binding:"required"`
+	Title              string `json:"title" binding:"required"`
+	Description        string `json:"description"`
+	URL                string `json:"url" binding:"required"`
+	Category           string `json:"category"`
+	GracePeriodMinutes int    `json:"grace_period_minutes"`
+}
+
+// FromTemplateRequest represents the request body for creating an assignment from a template
+type FromTemplateRequest struct {
+	Title       string `json:"title"` // empty fields keep the template's values
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Category    string `json:"category"`
+	DueDate     string `json:"due_date"`     // ISO 8601 format
+	PublishAt   string `json:"publish_at"`   // ISO 8601 format, empty publishes immediately
+	UnpublishAt string `json:"unpublish_at"` // ISO 8601 format, empty never hides
+}
+
+// RecurrenceRequest represents the request body for scheduling a recurring assignment
+type RecurrenceRequest struct {
+	RRule          string `json:"rrule" binding:"required"`     // e.g. FREQ=WEEKLY;BYDAY=MO
+	StartsAt       string `json:"starts_at" binding:"required"` // ISO 8601 format
+	DueOffsetHours int    `json:"due_offset_hours"`
+	StudentIDs     []uint `json:"student_ids"`
+	GroupIDs       []uint `json:"group_ids"`
+}
+
+// GetTemplates handles GET /instructor/templates
+func (h *TemplateHandlers) GetTemplates(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	templates, err := h.templateService.GetTemplates(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"total":     len(templates),
+	})
+}
+
+// CreateTemplate handles POST /instructor/templates
+func (h *TemplateHandlers) CreateTemplate(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(userObj.ID, req.toInput())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Template created successfully",
+		"template": template,
+	})
+}
+
+// GetTemplate handles GET /instructor/templates/:id
+func (h *TemplateHandlers) GetTemplate(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(uint(id), userObj.ID)
+	if err != nil {
+		respondServiceError(c, err, http.StatusNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": template,
+	})
+}
+
+// UpdateTemplate handles PUT /instructor/templates/:id
+func (h *TemplateHandlers) UpdateTemplate(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.templateService.UpdateTemplate(uint(id), userObj.ID, req.toInput()); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template updated successfully",
+	})
+}
+
+// DeleteTemplate handles DELETE /instructor/templates/:id
+func (h *TemplateHandlers) DeleteTemplate(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(uint(id), userObj.ID); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+	})
+}
+
+// CreateAssignmentFromTemplate handles POST /instructor/templates/:id/assignments
+func (h *TemplateHandlers) CreateAssignmentFromTemplate(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req FromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dueDate, err := parseScheduleTime(req.DueDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid due date format. Expected YYYY-MM-DD or YYYY-MM-DDTHH:MM"})
+		return
+	}
+
+	publishAt, unpublishAt, err := parseReleaseWindow(req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.templateService.CreateAssignmentFromTemplate(uint(id), userObj.ID, services.FromTemplateInput{
+		Title:       req.Title,
+		Description: req.Description,
+		URL:         req.URL,
+		Category:    req.Category,
+		DueDate:     dueDate,
+		PublishAt:   publishAt,
+		UnpublishAt: unpublishAt,
+	})
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	response := gin.H{
+		"message":    "Assignment created successfully",
+		"assignment": assignment,
+	}
+
+	duplicates, err := h.templateService.FindDuplicates(assignment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(duplicates) > 0 {
+		response["duplicates"] = duplicates
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CreateRecurrence handles POST /instructor/templates/:id/recurrences
+func (h *TemplateHandlers) CreateRecurrence(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req RecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startsAt, err := parseScheduleTime(req.StartsAt)
+	if err != nil || startsAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Expected YYYY-MM-DD or YYYY-MM-DDTHH:MM"})
+		return
+	}
+
+	recurrence, err := h.recurrenceService.CreateRecurrence(userObj.ID, services.CreateRecurrenceInput{
+		TemplateID:     uint(id),
+		RRule:          req.RRule,
+		StartsAt:       *startsAt,
+		DueOffsetHours: req.DueOffsetHours,
+		StudentIDs:     req.StudentIDs,
+		GroupIDs:       req.GroupIDs,
+	})
+	if err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Recurrence created successfully",
+		"recurrence": recurrence,
+	})
+}
+
+// GetRecurrences handles GET /instructor/recurrences
+func (h *TemplateHandlers) GetRecurrences(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	recurrences, err := h.recurrenceService.GetRecurrences(userObj.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recurrences": recurrences,
+		"total":       len(recurrences),
+	})
+}
+
+// DeactivateRecurrence handles DELETE /instructor/recurrences/:id
+func (h *TemplateHandlers) DeactivateRecurrence(c *gin.Context) {
+	userObj, ok := instructorFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	if err := h.recurrenceService.DeactivateRecurrence(uint(id), userObj.ID); err != nil {
+		respondServiceError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurrence stopped successfully",
+	})
+}
+
+// toInput converts the request into service input
+func (req TemplateRequest) toInput() services.TemplateInput {
+	return services.TemplateInput{
+		Name:               req.Name,
+		Title:              req.Title,
+		Description:        req.Description,
+		URL:                req.URL,
+		Category:           req.Category,
+		GracePeriodMinutes: req.GracePeriodMinutes,
+	}
+}
+
+// instructorFromContext returns the authenticated instructor or writes an error response
+func instructorFromContext(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsInstructor() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+
+	return userObj, true
+}
+
+// userFromContext returns the authenticated user or writes an error response
+func userFromContext(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+	return user.(*models.User), true
+}
+
+// studentFromContext returns the authenticated student or writes an error response
+func studentFromContext(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	userObj := user.(*models.User)
+	if !userObj.IsStudent() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+
+	return userObj, true
+}
+
+// respondServiceError maps service errors to HTTP responses
+func respondServiceError(c *gin.Context, err error, fallbackStatus int) {
+	if strings.Contains(err.Error(), "access denied") {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.Contains(err.Error(), "record not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(fallbackStatus, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"zipcodereader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrackedLinkHandlers handles the per-student links that record when a reading is opened
+type TrackedLinkHandlers struct {
+	trackedLinkService *services.TrackedLinkService
+}
+
+// NewTrackedLinkHandlers creates new tracked link handlers
+func NewTrackedLinkHandlers(trackedLinkService *services.TrackedLinkService) *TrackedLinkHandlers {
+	return &TrackedLinkHandlers{trackedLinkService: trackedLinkService}
+}
+
+// Open handles GET /r/:token. The token identifies the student, so the link works
+// without a session, for example when opened from an email on another device.
+func (h *TrackedLinkHandlers) Open(c *gin.Context) {
+	destination, err := h.trackedLinkService.Open(c.Param("token"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+			return
+		}
+		if strings.Contains(err.Error(), "destination") {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open link"})
+		return
+	}
+
+	// Tracked links are personal, so keep them out of shared caches and referrers
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Redirect(http.StatusFound, destination)
+}
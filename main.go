@@ -11,6 +11,7 @@ import (
 	"zipcodereader/middleware"
 	"zipcodereader/models"
 	"zipcodereader/services"
+	"zipcodereader/storage"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
@@ -69,18 +70,105 @@ func main() {
 	studentAssignmentService := services.NewStudentAssignmentService(db)
 	progressTrackingService := services.NewProgressTrackingService(db)
 	dueDateNotificationService := services.NewDueDateNotificationService(db)
+	notificationService := services.NewNotificationService(db)
+	templateService := services.NewTemplateService(db)
+	recurrenceService := services.NewRecurrenceService(db)
+	readingListService := services.NewReadingListService(db)
+	groupService := services.NewGroupService(db)
+	readingNoteService := services.NewReadingNoteService(db)
+	quizService := services.NewQuizService(db)
+	discussionService := services.NewDiscussionService(db)
+	linkPreviewService := services.NewLinkPreviewService(db, services.LinkPreviewOptions{
+		Timeout:      cfg.LinkPreviewTimeout,
+		MaxBodyBytes: cfg.LinkPreviewMaxBytes,
+		CacheTTL:     cfg.LinkPreviewCacheTTL,
+	})
+	linkCheckerService := services.NewLinkCheckerService(db, services.LinkCheckerOptions{
+		Timeout:   cfg.LinkCheckTimeout,
+		HostDelay: cfg.LinkCheckHostDelay,
+	})
+	archiveService := services.NewArchiveService(db, services.ArchiveOptions{
+		Dir:      cfg.ArchiveDir,
+		Timeout:  cfg.ArchiveTimeout,
+		MaxBytes: cfg.ArchiveMaxBytes,
+	})
+	contentExtractionService := services.NewContentExtractionService(db, services.ContentExtractionOptions{
+		Timeout:      cfg.ExtractionTimeout,
+		MaxBodyBytes: cfg.ExtractionMaxBytes,
+	})
+
+	// Uploaded documents live on local disk unless an S3-compatible bucket is configured
+	var fileStorage storage.Storage
+	switch cfg.StorageBackend {
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			log.Fatal("S3_ENDPOINT and S3_BUCKET are required when STORAGE_BACKEND is s3")
+		}
+		fileStorage = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Bucket:          cfg.S3Bucket,
+			Region:          cfg.S3Region,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	case "local":
+		fileStorage = storage.NewLocalStorage(cfg.StorageDir)
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q", cfg.StorageBackend)
+	}
+	fileService := services.NewFileService(db, fileStorage, cfg.UploadMaxBytes)
+	annotationService := services.NewAnnotationService(db, archiveService)
+	peerReviewService := services.NewPeerReviewService(db)
+	gradingService := services.NewGradingService(db)
+	readingSessionService := services.NewReadingSessionService(db)
+	trackedLinkService := services.NewTrackedLinkService(db)
+	riskService := services.NewRiskService(db)
+	insightsService := services.NewInsightsService(db)
+	achievementService := services.NewAchievementService(db)
+	leaderboardService := services.NewLeaderboardService(db)
+	auditService := services.NewAuditService(db)
+
+	// Start background jobs
+	releaseScheduler := services.NewReleaseSchedulerService(db)
+	releaseScheduler.Start(cfg.ReleaseCheckInterval, nil)
+	recurrenceService.Start(cfg.RecurrenceCheckInterval, nil)
+	linkCheckerService.Start(cfg.LinkCheckInterval, nil)
+	contentExtractionService.Start(cfg.ExtractionInterval, nil)
+	riskService.Start(cfg.RiskDigestCheckInterval, nil)
 
 	// Initialize assignment handlers
 	instructorAssignmentHandlers := handlers.NewInstructorAssignmentHandlers(assignmentService)
 	studentAssignmentHandlers := handlers.NewStudentAssignmentHandlers(studentAssignmentService)
 	progressTrackingHandlers := handlers.NewProgressTrackingHandlers(progressTrackingService)
 	dueDateNotificationHandlers := handlers.NewDueDateNotificationHandlers(dueDateNotificationService)
-	dashboardHandlers := handlers.NewDashboardHandlers(assignmentService, studentAssignmentService, cfg.UseLocalAuth)
+	notificationHandlers := handlers.NewNotificationHandlers(notificationService)
+	templateHandlers := handlers.NewTemplateHandlers(templateService, recurrenceService)
+	readingListHandlers := handlers.NewReadingListHandlers(readingListService)
+	groupHandlers := handlers.NewGroupHandlers(groupService)
+	readingNoteHandlers := handlers.NewReadingNoteHandlers(readingNoteService)
+	quizHandlers := handlers.NewQuizHandlers(quizService)
+	discussionHandlers := handlers.NewDiscussionHandlers(discussionService)
+	annotationHandlers := handlers.NewAnnotationHandlers(annotationService)
+	peerReviewHandlers := handlers.NewPeerReviewHandlers(peerReviewService)
+	gradingHandlers := handlers.NewGradingHandlers(gradingService, cfg.UseLocalAuth)
+	readingSessionHandlers := handlers.NewReadingSessionHandlers(readingSessionService)
+	trackedLinkHandlers := handlers.NewTrackedLinkHandlers(trackedLinkService)
+	riskHandlers := handlers.NewRiskHandlers(riskService)
+	insightsHandlers := handlers.NewInsightsHandlers(insightsService)
+	achievementHandlers := handlers.NewAchievementHandlers(achievementService)
+	leaderboardHandlers := handlers.NewLeaderboardHandlers(leaderboardService)
+	auditHandlers := handlers.NewAuditHandlers(auditService)
+	linkPreviewHandlers := handlers.NewLinkPreviewHandlers(linkPreviewService)
+	linkCheckHandlers := handlers.NewLinkCheckHandlers(linkCheckerService)
+	archiveHandlers := handlers.NewArchiveHandlers(archiveService)
+	readingTimeHandlers := handlers.NewReadingTimeHandlers(contentExtractionService)
+	fileHandlers := handlers.NewFileHandlers(fileService, cfg.UploadMaxBytes)
+	dashboardHandlers := handlers.NewDashboardHandlers(assignmentService, studentAssignmentService, archiveService, cfg.UseLocalAuth)
 
 	// Setup authentication routes based on mode
 	if cfg.UseLocalAuth {
 		log.Println("Using local authentication mode (default)")
-		localAuthHandler := handlers.NewLocalAuthHandler(db)
+		localAuthHandler := handlers.NewLocalAuthHandler(db, auditService)
 
 		// Local authentication routes
 		r.GET("/local/login", localAuthHandler.ShowLogin)
@@ -108,6 +196,26 @@ func main() {
 				}
 			})
 
+			// In-app notification routes
+			protected.GET("/notifications", notificationHandlers.GetNotifications)
+			protected.GET("/files/:id", fileHandlers.DownloadFile)
+			protected.POST("/notifications/:id/read", notificationHandlers.MarkAsRead)
+
+			// Assignment discussions are shared by the instructor and the assigned students
+			protected.GET("/assignments/:id/discussion", discussionHandlers.GetThreads)
+			protected.POST("/assignments/:id/discussion", discussionHandlers.CreatePost)
+			protected.PUT("/discussion/posts/:id", discussionHandlers.EditPost)
+			protected.DELETE("/discussion/posts/:id", discussionHandlers.DeletePost)
+			protected.GET("/discussion/posts/:id/history", discussionHandlers.GetPostHistory)
+			protected.PUT("/discussion/posts/:id/pin", discussionHandlers.PinPost)
+			protected.PUT("/discussion/posts/:id/hide", discussionHandlers.HidePost)
+
+			// Highlights and margin notes on archived readings
+			protected.GET("/assignments/:id/annotations", annotationHandlers.GetAnnotations)
+			protected.POST("/assignments/:id/annotations", annotationHandlers.CreateAnnotation)
+			protected.PUT("/annotations/:id", annotationHandlers.UpdateAnnotation)
+			protected.DELETE("/annotations/:id", annotationHandlers.DeleteAnnotation)
+
 			// Instructor assignment routes
 			instructorGroup := protected.Group("/instructor")
 			instructorGroup.Use(middleware.RequireRole("instructor"))
@@ -127,7 +235,52 @@ func main() {
 				instructorGroup.POST("/assignments/:id/assign", instructorAssignmentHandlers.AssignStudents)
 				instructorGroup.GET("/assignments/:id/progress", instructorAssignmentHandlers.GetAssignmentProgress)
 				instructorGroup.GET("/assignments/:id/students", instructorAssignmentHandlers.GetAssignmentStudents)
+				instructorGroup.GET("/assignments/:id/duplicates", instructorAssignmentHandlers.GetDuplicates)
+				instructorGroup.GET("/assignments/:id/reflections", readingNoteHandlers.GetReflections)
+				instructorGroup.GET("/assignments/:id/reflections/export", readingNoteHandlers.ExportReflections)
+				instructorGroup.GET("/assignments/:id/quiz", quizHandlers.GetQuiz)
+				instructorGroup.PUT("/assignments/:id/quiz", quizHandlers.SaveQuiz)
+				instructorGroup.DELETE("/assignments/:id/quiz", quizHandlers.DeleteQuiz)
+				instructorGroup.POST("/duplicate-check", instructorAssignmentHandlers.CheckDuplicates)
 				instructorGroup.POST("/assignments/:id/students/:student_id/remove", instructorAssignmentHandlers.RemoveStudent)
+				instructorGroup.POST("/assignments/:id/students/:student_id/extension", instructorAssignmentHandlers.GrantExtension)
+				instructorGroup.POST("/assignments/:id/students/:student_id/excuse", instructorAssignmentHandlers.SetExcused)
+				instructorGroup.PUT("/assignments/:id/resources", instructorAssignmentHandlers.SetResources)
+				instructorGroup.GET("/link-preview", linkPreviewHandlers.GetPreview)
+				instructorGroup.GET("/link-checks", linkCheckHandlers.GetLinkProblems)
+				instructorGroup.POST("/assignments/:id/check-link", linkCheckHandlers.CheckAssignment)
+				instructorGroup.POST("/assignments/:id/archive", archiveHandlers.CreateArchive)
+				instructorGroup.GET("/assignments/:id/archive", archiveHandlers.ShowInstructorArchive)
+				instructorGroup.GET("/assignments/:id/archive/assets/:name", archiveHandlers.ServeInstructorAsset)
+				instructorGroup.DELETE("/assignments/:id/archive", archiveHandlers.DeleteArchive)
+				instructorGroup.GET("/assignments/:id/annotations/popular", annotationHandlers.GetMostHighlighted)
+
+				// Peer review of reflections
+				instructorGroup.GET("/assignments/:id/peer-reviews", peerReviewHandlers.GetPeerReviews)
+				instructorGroup.PUT("/assignments/:id/peer-reviews", peerReviewHandlers.SavePeerReviewSettings)
+				instructorGroup.POST("/assignments/:id/peer-reviews/allocate", peerReviewHandlers.AllocatePeerReviews)
+
+				// Points, rubrics and the gradebook
+				instructorGroup.GET("/rubrics", gradingHandlers.GetRubrics)
+				instructorGroup.POST("/rubrics", gradingHandlers.CreateRubric)
+				instructorGroup.PUT("/rubrics/:id", gradingHandlers.UpdateRubric)
+				instructorGroup.DELETE("/rubrics/:id", gradingHandlers.DeleteRubric)
+				instructorGroup.PUT("/assignments/:id/grading", gradingHandlers.SetGradingPolicy)
+				instructorGroup.GET("/assignments/:id/grades", gradingHandlers.GetAssignmentGrades)
+				instructorGroup.PUT("/assignments/:id/students/:student_id/grade", gradingHandlers.GradeStudent)
+				instructorGroup.DELETE("/assignments/:id/students/:student_id/grade", gradingHandlers.DeleteGrade)
+				instructorGroup.GET("/gradebook", gradingHandlers.GetGradebook)
+				instructorGroup.GET("/gradebook-view", gradingHandlers.ShowGradebook)
+				instructorGroup.GET("/gradebook/export", gradingHandlers.ExportGradebook)
+				instructorGroup.PUT("/gradebook/weights", gradingHandlers.SetCategoryWeights)
+
+				// Uploaded document routes
+				instructorGroup.POST("/files", fileHandlers.UploadFile)
+				instructorGroup.GET("/files", fileHandlers.GetFiles)
+				instructorGroup.DELETE("/files/:id", fileHandlers.DeleteFile)
+				instructorGroup.PUT("/assignments/:id/file", fileHandlers.AttachFile)
+				instructorGroup.POST("/assignments/:id/extract", readingTimeHandlers.ExtractAssignment)
+				instructorGroup.PUT("/assignments/:id/estimate", readingTimeHandlers.SetEstimate)
 				instructorGroup.GET("/students", instructorAssignmentHandlers.GetAllStudents)
 				instructorGroup.GET("/students/:username/progress", instructorAssignmentHandlers.GetStudentProgress)
 				instructorGroup.GET("/students/:username/assignments", instructorAssignmentHandlers.ShowStudentAssignments)
@@ -137,13 +290,47 @@ func main() {
 
 				// Advanced progress tracking routes
 				instructorGroup.GET("/assignments/:id/detailed-progress", progressTrackingHandlers.GetDetailedProgressReport)
+				instructorGroup.GET("/assignments/:id/detailed-progress/export", progressTrackingHandlers.ExportDetailedProgressReport)
 				instructorGroup.GET("/progress/summary", progressTrackingHandlers.GetInstructorProgressSummary)
 				instructorGroup.GET("/progress/trends", progressTrackingHandlers.GetProgressTrends)
 				instructorGroup.GET("/progress/completion-analytics", progressTrackingHandlers.GetCompletionAnalytics)
+				instructorGroup.GET("/progress/cohorts", progressTrackingHandlers.GetCohortAnalytics)
+				instructorGroup.GET("/progress/at-risk", riskHandlers.GetAtRiskReport)
+				instructorGroup.GET("/progress/at-risk/settings", riskHandlers.GetRiskSettings)
+				instructorGroup.PUT("/progress/at-risk/settings", riskHandlers.UpdateRiskSettings)
+				instructorGroup.GET("/audit", auditHandlers.GetAuditLog)
+				instructorGroup.GET("/audit/export", auditHandlers.ExportAuditLog)
+				instructorGroup.GET("/leaderboard", leaderboardHandlers.GetInstructorLeaderboard)
+				instructorGroup.PUT("/leaderboard/settings", leaderboardHandlers.UpdateLeaderboardSettings)
 
 				// Due date notification routes for instructors
 				instructorGroup.GET("/due-dates/overview", dueDateNotificationHandlers.GetInstructorDueDateOverview)
 				instructorGroup.GET("/due-dates/notifications", dueDateNotificationHandlers.GetDueDateNotifications)
+
+				// Student group routes
+				instructorGroup.GET("/groups", groupHandlers.GetGroups)
+				instructorGroup.POST("/groups", groupHandlers.CreateGroup)
+				instructorGroup.GET("/groups/:id", groupHandlers.GetGroup)
+				instructorGroup.PUT("/groups/:id", groupHandlers.UpdateGroup)
+				instructorGroup.DELETE("/groups/:id", groupHandlers.DeleteGroup)
+
+				// Assignment template and recurrence routes
+				instructorGroup.GET("/templates", templateHandlers.GetTemplates)
+				instructorGroup.POST("/templates", templateHandlers.CreateTemplate)
+				instructorGroup.GET("/templates/:id", templateHandlers.GetTemplate)
+				instructorGroup.PUT("/templates/:id", templateHandlers.UpdateTemplate)
+				instructorGroup.DELETE("/templates/:id", templateHandlers.DeleteTemplate)
+				instructorGroup.POST("/templates/:id/assignments", templateHandlers.CreateAssignmentFromTemplate)
+				instructorGroup.POST("/templates/:id/recurrences", templateHandlers.CreateRecurrence)
+				instructorGroup.GET("/recurrences", templateHandlers.GetRecurrences)
+				instructorGroup.DELETE("/recurrences/:id", templateHandlers.DeactivateRecurrence)
+
+				// Reading list routes
+				instructorGroup.GET("/reading-lists", readingListHandlers.GetReadingLists)
+				instructorGroup.POST("/reading-lists", readingListHandlers.CreateReadingList)
+				instructorGroup.GET("/reading-lists/:id", readingListHandlers.GetReadingList)
+				instructorGroup.DELETE("/reading-lists/:id", readingListHandlers.DeleteReadingList)
+				instructorGroup.POST("/reading-lists/:id/assign", readingListHandlers.AssignReadingList)
 			}
 
 			// Student assignment routes
@@ -160,7 +347,30 @@ func main() {
 				studentGroup.POST("/assignments/:id/status", studentAssignmentHandlers.UpdateStatus)
 				studentGroup.POST("/assignments/:id/complete", studentAssignmentHandlers.MarkAsCompleted)
 				studentGroup.POST("/assignments/:id/progress", studentAssignmentHandlers.MarkAsInProgress)
+				studentGroup.GET("/assignments/:id/resources", studentAssignmentHandlers.GetResources)
+				studentGroup.GET("/assignments/:id/note", readingNoteHandlers.GetNote)
+				studentGroup.PUT("/assignments/:id/note", readingNoteHandlers.SaveNote)
+				studentGroup.GET("/assignments/:id/quiz", quizHandlers.GetStudentQuiz)
+				studentGroup.POST("/assignments/:id/quiz/attempts", quizHandlers.SubmitQuiz)
+
+				// Peer review of reflections
+				studentGroup.GET("/assignments/:id/peer-reviews", peerReviewHandlers.GetStudentPeerReviews)
+				studentGroup.PUT("/peer-reviews/:id", peerReviewHandlers.SubmitPeerReview)
+				studentGroup.GET("/assignments/:id/grade", gradingHandlers.GetStudentGrade)
+				studentGroup.POST("/assignments/:id/reading-sessions", readingSessionHandlers.StartSession)
+				studentGroup.GET("/assignments/:id/reading-time", readingSessionHandlers.GetReadingTime)
+				studentGroup.POST("/reading-sessions/:id/heartbeat", readingSessionHandlers.Heartbeat)
+				studentGroup.POST("/reading-sessions/:id/stop", readingSessionHandlers.StopSession)
+				studentGroup.POST("/assignments/:id/resources/:resource_id", studentAssignmentHandlers.SetResourceCompleted)
+				studentGroup.GET("/assignments/:id/archive", archiveHandlers.ShowStudentArchive)
+				studentGroup.GET("/assignments/:id/archive/assets/:name", archiveHandlers.ServeStudentAsset)
 				studentGroup.GET("/dashboard/stats", studentAssignmentHandlers.GetDashboardStats)
+				studentGroup.GET("/progress/insights", insightsHandlers.GetInsights)
+				studentGroup.PUT("/progress/goal", insightsHandlers.UpdateGoal)
+				studentGroup.GET("/achievements", achievementHandlers.GetAchievements)
+				studentGroup.GET("/leaderboards", leaderboardHandlers.GetStudentLeaderboards)
+				studentGroup.GET("/leaderboard/profile", leaderboardHandlers.GetLeaderboardProfile)
+				studentGroup.PUT("/leaderboard/profile", leaderboardHandlers.UpdateLeaderboardProfile)
 				studentGroup.GET("/assignments/overdue", studentAssignmentHandlers.GetOverdueAssignments)
 				studentGroup.GET("/assignments/upcoming", studentAssignmentHandlers.GetUpcomingAssignments)
 				studentGroup.GET("/assignments/recent", studentAssignmentHandlers.GetRecentlyCompleted)
@@ -173,6 +383,9 @@ func main() {
 				studentGroup.GET("/due-dates/alerts", dueDateNotificationHandlers.GetStudentDueDateAlerts)
 				studentGroup.GET("/due-dates/summary", dueDateNotificationHandlers.GetStudentDueDateSummary)
 				studentGroup.GET("/due-dates/notifications", dueDateNotificationHandlers.GetDueDateNotifications)
+
+				// Reading list routes for students
+				studentGroup.GET("/reading-lists", readingListHandlers.GetStudentReadingLists)
 			}
 		}
 
@@ -180,7 +393,7 @@ func main() {
 	} else {
 		log.Println("Using GitHub OAuth2 authentication mode (optional)")
 		authService := services.NewAuthService(db, cfg)
-		authHandler := handlers.NewAuthHandler(authService)
+		authHandler := handlers.NewAuthHandler(authService, auditService)
 
 		// GitHub OAuth2 routes
 		r.GET("/auth/login", authHandler.Login)
@@ -193,6 +406,26 @@ func main() {
 		{
 			protected.GET("/dashboard", authHandler.Dashboard)
 
+			// In-app notification routes
+			protected.GET("/notifications", notificationHandlers.GetNotifications)
+			protected.GET("/files/:id", fileHandlers.DownloadFile)
+			protected.POST("/notifications/:id/read", notificationHandlers.MarkAsRead)
+
+			// Assignment discussions are shared by the instructor and the assigned students
+			protected.GET("/assignments/:id/discussion", discussionHandlers.GetThreads)
+			protected.POST("/assignments/:id/discussion", discussionHandlers.CreatePost)
+			protected.PUT("/discussion/posts/:id", discussionHandlers.EditPost)
+			protected.DELETE("/discussion/posts/:id", discussionHandlers.DeletePost)
+			protected.GET("/discussion/posts/:id/history", discussionHandlers.GetPostHistory)
+			protected.PUT("/discussion/posts/:id/pin", discussionHandlers.PinPost)
+			protected.PUT("/discussion/posts/:id/hide", discussionHandlers.HidePost)
+
+			// Highlights and margin notes on archived readings
+			protected.GET("/assignments/:id/annotations", annotationHandlers.GetAnnotations)
+			protected.POST("/assignments/:id/annotations", annotationHandlers.CreateAnnotation)
+			protected.PUT("/annotations/:id", annotationHandlers.UpdateAnnotation)
+			protected.DELETE("/annotations/:id", annotationHandlers.DeleteAnnotation)
+
 			// Instructor assignment routes
 			instructorGroup := protected.Group("/instructor")
 			instructorGroup.Use(middleware.RequireRole("instructor"))
@@ -210,7 +443,52 @@ func main() {
 				instructorGroup.POST("/assignments/:id/assign", instructorAssignmentHandlers.AssignStudents)
 				instructorGroup.GET("/assignments/:id/progress", instructorAssignmentHandlers.GetAssignmentProgress)
 				instructorGroup.GET("/assignments/:id/students", instructorAssignmentHandlers.GetAssignmentStudents)
+				instructorGroup.GET("/assignments/:id/duplicates", instructorAssignmentHandlers.GetDuplicates)
+				instructorGroup.GET("/assignments/:id/reflections", readingNoteHandlers.GetReflections)
+				instructorGroup.GET("/assignments/:id/reflections/export", readingNoteHandlers.ExportReflections)
+				instructorGroup.GET("/assignments/:id/quiz", quizHandlers.GetQuiz)
+				instructorGroup.PUT("/assignments/:id/quiz", quizHandlers.SaveQuiz)
+				instructorGroup.DELETE("/assignments/:id/quiz", quizHandlers.DeleteQuiz)
+				instructorGroup.POST("/duplicate-check", instructorAssignmentHandlers.CheckDuplicates)
 				instructorGroup.POST("/assignments/:id/students/:student_id/remove", instructorAssignmentHandlers.RemoveStudent)
+				instructorGroup.POST("/assignments/:id/students/:student_id/extension", instructorAssignmentHandlers.GrantExtension)
+				instructorGroup.POST("/assignments/:id/students/:student_id/excuse", instructorAssignmentHandlers.SetExcused)
+				instructorGroup.PUT("/assignments/:id/resources", instructorAssignmentHandlers.SetResources)
+				instructorGroup.GET("/link-preview", linkPreviewHandlers.GetPreview)
+				instructorGroup.GET("/link-checks", linkCheckHandlers.GetLinkProblems)
+				instructorGroup.POST("/assignments/:id/check-link", linkCheckHandlers.CheckAssignment)
+				instructorGroup.POST("/assignments/:id/archive", archiveHandlers.CreateArchive)
+				instructorGroup.GET("/assignments/:id/archive", archiveHandlers.ShowInstructorArchive)
+				instructorGroup.GET("/assignments/:id/archive/assets/:name", archiveHandlers.ServeInstructorAsset)
+				instructorGroup.DELETE("/assignments/:id/archive", archiveHandlers.DeleteArchive)
+				instructorGroup.GET("/assignments/:id/annotations/popular", annotationHandlers.GetMostHighlighted)
+
+				// Peer review of reflections
+				instructorGroup.GET("/assignments/:id/peer-reviews", peerReviewHandlers.GetPeerReviews)
+				instructorGroup.PUT("/assignments/:id/peer-reviews", peerReviewHandlers.SavePeerReviewSettings)
+				instructorGroup.POST("/assignments/:id/peer-reviews/allocate", peerReviewHandlers.AllocatePeerReviews)
+
+				// Points, rubrics and the gradebook
+				instructorGroup.GET("/rubrics", gradingHandlers.GetRubrics)
+				instructorGroup.POST("/rubrics", gradingHandlers.CreateRubric)
+				instructorGroup.PUT("/rubrics/:id", gradingHandlers.UpdateRubric)
+				instructorGroup.DELETE("/rubrics/:id", gradingHandlers.DeleteRubric)
+				instructorGroup.PUT("/assignments/:id/grading", gradingHandlers.SetGradingPolicy)
+				instructorGroup.GET("/assignments/:id/grades", gradingHandlers.GetAssignmentGrades)
+				instructorGroup.PUT("/assignments/:id/students/:student_id/grade", gradingHandlers.GradeStudent)
+				instructorGroup.DELETE("/assignments/:id/students/:student_id/grade", gradingHandlers.DeleteGrade)
+				instructorGroup.GET("/gradebook", gradingHandlers.GetGradebook)
+				instructorGroup.GET("/gradebook-view", gradingHandlers.ShowGradebook)
+				instructorGroup.GET("/gradebook/export", gradingHandlers.ExportGradebook)
+				instructorGroup.PUT("/gradebook/weights", gradingHandlers.SetCategoryWeights)
+
+				// Uploaded document routes
+				instructorGroup.POST("/files", fileHandlers.UploadFile)
+				instructorGroup.GET("/files", fileHandlers.GetFiles)
+				instructorGroup.DELETE("/files/:id", fileHandlers.DeleteFile)
+				instructorGroup.PUT("/assignments/:id/file", fileHandlers.AttachFile)
+				instructorGroup.POST("/assignments/:id/extract", readingTimeHandlers.ExtractAssignment)
+				instructorGroup.PUT("/assignments/:id/estimate", readingTimeHandlers.SetEstimate)
 				instructorGroup.GET("/students", instructorAssignmentHandlers.GetAllStudents)
 				instructorGroup.GET("/students/:username/progress", instructorAssignmentHandlers.GetStudentProgress)
 				instructorGroup.GET("/students/:username/assignments", instructorAssignmentHandlers.ShowStudentAssignments)
@@ -218,13 +496,47 @@ func main() {
 				instructorGroup.DELETE("/students/:username/assignments/:assignment_id/remove", instructorAssignmentHandlers.RemoveFromStudent)
 				instructorGroup.GET("/dashboard/stats", instructorAssignmentHandlers.GetDashboardStats) // Advanced progress tracking routes
 				instructorGroup.GET("/assignments/:id/detailed-progress", progressTrackingHandlers.GetDetailedProgressReport)
+				instructorGroup.GET("/assignments/:id/detailed-progress/export", progressTrackingHandlers.ExportDetailedProgressReport)
 				instructorGroup.GET("/progress/summary", progressTrackingHandlers.GetInstructorProgressSummary)
 				instructorGroup.GET("/progress/trends", progressTrackingHandlers.GetProgressTrends)
 				instructorGroup.GET("/progress/completion-analytics", progressTrackingHandlers.GetCompletionAnalytics)
+				instructorGroup.GET("/progress/cohorts", progressTrackingHandlers.GetCohortAnalytics)
+				instructorGroup.GET("/progress/at-risk", riskHandlers.GetAtRiskReport)
+				instructorGroup.GET("/progress/at-risk/settings", riskHandlers.GetRiskSettings)
+				instructorGroup.PUT("/progress/at-risk/settings", riskHandlers.UpdateRiskSettings)
+				instructorGroup.GET("/audit", auditHandlers.GetAuditLog)
+				instructorGroup.GET("/audit/export", auditHandlers.ExportAuditLog)
+				instructorGroup.GET("/leaderboard", leaderboardHandlers.GetInstructorLeaderboard)
+				instructorGroup.PUT("/leaderboard/settings", leaderboardHandlers.UpdateLeaderboardSettings)
 
 				// Due date notification routes for instructors
 				instructorGroup.GET("/due-dates/overview", dueDateNotificationHandlers.GetInstructorDueDateOverview)
 				instructorGroup.GET("/due-dates/notifications", dueDateNotificationHandlers.GetDueDateNotifications)
+
+				// Student group routes
+				instructorGroup.GET("/groups", groupHandlers.GetGroups)
+				instructorGroup.POST("/groups", groupHandlers.CreateGroup)
+				instructorGroup.GET("/groups/:id", groupHandlers.GetGroup)
+				instructorGroup.PUT("/groups/:id", groupHandlers.UpdateGroup)
+				instructorGroup.DELETE("/groups/:id", groupHandlers.DeleteGroup)
+
+				// Assignment template and recurrence routes
+				instructorGroup.GET("/templates", templateHandlers.GetTemplates)
+				instructorGroup.POST("/templates", templateHandlers.CreateTemplate)
+				instructorGroup.GET("/templates/:id", templateHandlers.GetTemplate)
+				instructorGroup.PUT("/templates/:id", templateHandlers.UpdateTemplate)
+				instructorGroup.DELETE("/templates/:id", templateHandlers.DeleteTemplate)
+				instructorGroup.POST("/templates/:id/assignments", templateHandlers.CreateAssignmentFromTemplate)
+				instructorGroup.POST("/templates/:id/recurrences", templateHandlers.CreateRecurrence)
+				instructorGroup.GET("/recurrences", templateHandlers.GetRecurrences)
+				instructorGroup.DELETE("/recurrences/:id", templateHandlers.DeactivateRecurrence)
+
+				// Reading list routes
+				instructorGroup.GET("/reading-lists", readingListHandlers.GetReadingLists)
+				instructorGroup.POST("/reading-lists", readingListHandlers.CreateReadingList)
+				instructorGroup.GET("/reading-lists/:id", readingListHandlers.GetReadingList)
+				instructorGroup.DELETE("/reading-lists/:id", readingListHandlers.DeleteReadingList)
+				instructorGroup.POST("/reading-lists/:id/assign", readingListHandlers.AssignReadingList)
 			}
 
 			// Student assignment routes
@@ -241,7 +553,30 @@ func main() {
 				studentGroup.POST("/assignments/:id/status", studentAssignmentHandlers.UpdateStatus)
 				studentGroup.POST("/assignments/:id/complete", studentAssignmentHandlers.MarkAsCompleted)
 				studentGroup.POST("/assignments/:id/progress", studentAssignmentHandlers.MarkAsInProgress)
+				studentGroup.GET("/assignments/:id/resources", studentAssignmentHandlers.GetResources)
+				studentGroup.GET("/assignments/:id/note", readingNoteHandlers.GetNote)
+				studentGroup.PUT("/assignments/:id/note", readingNoteHandlers.SaveNote)
+				studentGroup.GET("/assignments/:id/quiz", quizHandlers.GetStudentQuiz)
+				studentGroup.POST("/assignments/:id/quiz/attempts", quizHandlers.SubmitQuiz)
+
+				// Peer review of reflections
+				studentGroup.GET("/assignments/:id/peer-reviews", peerReviewHandlers.GetStudentPeerReviews)
+				studentGroup.PUT("/peer-reviews/:id", peerReviewHandlers.SubmitPeerReview)
+				studentGroup.GET("/assignments/:id/grade", gradingHandlers.GetStudentGrade)
+				studentGroup.POST("/assignments/:id/reading-sessions", readingSessionHandlers.StartSession)
+				studentGroup.GET("/assignments/:id/reading-time", readingSessionHandlers.GetReadingTime)
+				studentGroup.POST("/reading-sessions/:id/heartbeat", readingSessionHandlers.Heartbeat)
+				studentGroup.POST("/reading-sessions/:id/stop", readingSessionHandlers.StopSession)
+				studentGroup.POST("/assignments/:id/resources/:resource_id", studentAssignmentHandlers.SetResourceCompleted)
+				studentGroup.GET("/assignments/:id/archive", archiveHandlers.ShowStudentArchive)
+				studentGroup.GET("/assignments/:id/archive/assets/:name", archiveHandlers.ServeStudentAsset)
 				studentGroup.GET("/dashboard/stats", studentAssignmentHandlers.GetDashboardStats)
+				studentGroup.GET("/progress/insights", insightsHandlers.GetInsights)
+				studentGroup.PUT("/progress/goal", insightsHandlers.UpdateGoal)
+				studentGroup.GET("/achievements", achievementHandlers.GetAchievements)
+				studentGroup.GET("/leaderboards", leaderboardHandlers.GetStudentLeaderboards)
+				studentGroup.GET("/leaderboard/profile", leaderboardHandlers.GetLeaderboardProfile)
+				studentGroup.PUT("/leaderboard/profile", leaderboardHandlers.UpdateLeaderboardProfile)
 				studentGroup.GET("/assignments/overdue", studentAssignmentHandlers.GetOverdueAssignments)
 				studentGroup.GET("/assignments/upcoming", studentAssignmentHandlers.GetUpcomingAssignments)
 				studentGroup.GET("/assignments/recent", studentAssignmentHandlers.GetRecentlyCompleted)
@@ -254,6 +589,9 @@ func main() {
 				studentGroup.GET("/due-dates/alerts", dueDateNotificationHandlers.GetStudentDueDateAlerts)
 				studentGroup.GET("/due-dates/summary", dueDateNotificationHandlers.GetStudentDueDateSummary)
 				studentGroup.GET("/due-dates/notifications", dueDateNotificationHandlers.GetDueDateNotifications)
+
+				// Reading list routes for students
+				studentGroup.GET("/reading-lists", readingListHandlers.GetStudentReadingLists)
 			}
 		}
 	}
@@ -261,6 +599,9 @@ func main() {
 	// Common routes
 	r.GET("/health", h.Health)
 
+	// Tracked reading links identify the student by their token
+	r.GET("/r/:token", trackedLinkHandlers.Open)
+
 	// Home page - check if user is logged in
 	r.GET("/", func(c *gin.Context) {
 		session := sessions.Default(c)
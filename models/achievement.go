@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Achievement is a badge a student earned. Badges tied to one reading, like finishing it
+// first, record the assignment; all others are earned once and leave it at 0.
+type Achievement struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_achievement"`
+	Badge        string    `json:"badge" gorm:"not null;uniqueIndex:idx_achievement"`
+	AssignmentID uint      `json:"assignment_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_achievement"`
+	AwardedAt    time.Time `json:"awarded_at"`
+}
+
+// AwardAchievement stores a badge unless the student already has it and reports whether it is new
+func AwardAchievement(db *gorm.DB, achievement *Achievement) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(achievement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetAchievementsByUser retrieves a student's badges, newest first
+func GetAchievementsByUser(db *gorm.DB, userID uint) ([]Achievement, error) {
+	var achievements []Achievement
+	result := db.Where("user_id = ?", userID).Order("awarded_at DESC, id DESC").Find(&achievements)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return achievements, nil
+}
+
+// GetAchievementCounts counts the badges of several students, keyed by user ID
+func GetAchievementCounts(db *gorm.DB, userIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		UserID uint
+		Count  int
+	}
+	result := db.Model(&Achievement{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("user_id IN ?", userIDs).
+		Group("user_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountCompletions counts a student's completed readings and those completed on time
+func CountCompletions(db *gorm.DB, studentID uint) (completed int, onTime int, err error) {
+	var row struct {
+		Completed int
+		OnTime    int
+	}
+	result := db.Model(&StudentAssignment{}).
+		Select("COUNT(*) AS completed, COALESCE(SUM(CASE WHEN completion_timing = ? THEN 1 ELSE 0 END), 0) AS on_time", TimingOnTime).
+		Where("student_id = ? AND status = ?", studentID, StatusCompleted).
+		Scan(&row)
+	return row.Completed, row.OnTime, result.Error
+}
+
+// IsFirstCompletion checks if no other student completed the assignment before this one
+func (sa *StudentAssignment) IsFirstCompletion(db *gorm.DB) (bool, error) {
+	if sa.CompletedAt == nil {
+		return false, nil
+	}
+	var earlier int64
+	result := db.Model(&StudentAssignment{}).
+		Where("assignment_id = ? AND id <> ? AND status = ? AND completed_at <= ?", sa.AssignmentID, sa.ID, StatusCompleted, *sa.CompletedAt).
+		Count(&earlier)
+	return earlier == 0, result.Error
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Annotation visibility
+const (
+	AnnotationPrivate = "private"
+	AnnotationShared  = "shared"
+)
+
+// Annotation is a highlight, optionally with a margin note, on the archived copy of an
+// assignment. The passage is anchored the way the W3C Web Annotation model does it: by the
+// quoted text with some context on either side, and by its character offsets in the
+// archive's text so it can be found again quickly.
+type Annotation struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AssignmentID uint      `json:"assignment_id" gorm:"index;not null"`
+	ArchiveID    uint      `json:"archive_id"` // archive the selection was made on
+	UserID       uint      `json:"user_id" gorm:"index;not null"`
+	User         User      `json:"-" gorm:"foreignKey:UserID"`
+	AuthorName   string    `json:"author_name" gorm:"-"`
+	Exact        string    `json:"exact" gorm:"type:text;not null"`
+	Prefix       string    `json:"prefix"`
+	Suffix       string    `json:"suffix"`
+	StartOffset  int       `json:"start"`
+	EndOffset    int       `json:"end"`
+	Note         string    `json:"note" gorm:"type:text"`
+	Visibility   string    `json:"visibility" gorm:"not null;default:private"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HighlightedPassage is a passage of an assignment and how many readers highlighted it
+type HighlightedPassage struct {
+	Exact       string `json:"exact"`
+	Readers     int    `json:"readers"`
+	Annotations int    `json:"annotations"`
+	Notes       int    `json:"notes"`
+}
+
+// CreateAnnotation stores a new annotation
+func CreateAnnotation(db *gorm.DB, annotation *Annotation) error {
+	result := db.Create(annotation)
+	return result.Error
+}
+
+// GetAnnotationByID retrieves an annotation with its author
+func GetAnnotationByID(db *gorm.DB, annotationID uint) (*Annotation, error) {
+	var annotation Annotation
+	result := db.Preload("User").First(&annotation, annotationID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &annotation, nil
+}
+
+// GetVisibleAnnotations retrieves the annotations on an assignment that a user may read:
+// their own and those shared with the class, in reading order
+func GetVisibleAnnotations(db *gorm.DB, assignmentID uint, userID uint) ([]Annotation, error) {
+	var annotations []Annotation
+	result := db.Preload("User").
+		Where("assignment_id = ? AND (user_id = ? OR visibility = ?)", assignmentID, userID, AnnotationShared).
+		Order("start_offset ASC, id ASC").
+		Find(&annotations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return annotations, nil
+}
+
+// UpdateAnnotation changes the note and visibility of an annotation
+func UpdateAnnotation(db *gorm.DB, annotation *Annotation, note, visibility string) error {
+	result := db.Model(annotation).Omit(clause.Associations).Updates(map[string]interface{}{
+		"note":       note,
+		"visibility": visibility,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	annotation.Note = note
+	annotation.Visibility = visibility
+	return nil
+}
+
+// DeleteAnnotation removes an annotation
+func DeleteAnnotation(db *gorm.DB, annotation *Annotation) error {
+	result := db.Delete(annotation)
+	return result.Error
+}
+
+// GetMostHighlightedPassages ranks the passages of an assignment by how many readers
+// highlighted exactly that text, private highlights included
+func GetMostHighlightedPassages(db *gorm.DB, assignmentID uint, limit int) ([]HighlightedPassage, error) {
+	var passages []HighlightedPassage
+	result := db.Model(&Annotation{}).
+		Select("exact, COUNT(DISTINCT user_id) AS readers, COUNT(*) AS annotations, SUM(CASE WHEN note <> '' THEN 1 ELSE 0 END) AS notes").
+		Where("assignment_id = ?", assignmentID).
+		Group("exact").
+		Order("readers DESC, annotations DESC, exact ASC").
+		Limit(limit).
+		Scan(&passages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return passages, nil
+}
@@ -4,46 +4,82 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Assignment represents a reading assignment in the system
 type Assignment struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Title       string         `json:"title" gorm:"not null"`
-	Description string         `json:"description"`
-	URL         string         `json:"url" gorm:"not null"`
-	Category    string         `json:"category"`
-	DueDate     *time.Time     `json:"due_date"`
-	CreatedByID uint           `json:"created_by_id"`
-	CreatedBy   User           `json:"created_by" gorm:"foreignKey:CreatedByID"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID                 uint                 `json:"id" gorm:"primaryKey"`
+	Title              string               `json:"title" gorm:"not null"`
+	Description        string               `json:"description"`
+	URL                string               `json:"url" gorm:"not null"`
+	Category           string               `json:"category"`
+	DueDate            *time.Time           `json:"due_date"`
+	GracePeriodMinutes int                  `json:"grace_period_minutes" gorm:"default:0"` // late work within the grace period counts as on time
+	MinReflectionWords int                  `json:"min_reflection_words" gorm:"default:0"` // words of reflection required before completing; 0 means optional
+	Points             int                  `json:"points" gorm:"default:0"`               // points possible; 0 leaves the assignment ungraded
+	RubricID           *uint                `json:"rubric_id"`                             // rubric the assignment is scored with
+	LatePenaltyPerDay  int                  `json:"late_penalty_per_day" gorm:"default:0"` // percent of the score deducted per day late
+	MaxLatePenalty     int                  `json:"max_late_penalty" gorm:"default:0"`     // cap on the late deduction in percent; 0 means no cap
+	PublishAt          *time.Time           `json:"publish_at"`                            // nil means visible immediately
+	UnpublishAt        *time.Time           `json:"unpublish_at"`                          // nil means never hidden
+	ReleasedAt         *time.Time           `json:"released_at"`                           // set once students were notified of a scheduled release
+	TemplateID         *uint                `json:"template_id"`                           // template the assignment was created from
+	RecurrenceID       *uint                `json:"recurrence_id"`                         // recurrence that generated the assignment
+	WordCount          int                  `json:"word_count"`                            // words in the extracted article text
+	EstimatedMinutes   int                  `json:"estimated_minutes"`                     // reading time, computed from WordCount unless overridden
+	EstimateOverridden bool                 `json:"estimate_overridden"`                   // the instructor set EstimatedMinutes by hand
+	ExtractedURL       string               `json:"-"`                                     // URL the word count was taken from
+	ExtractedAt        *time.Time           `json:"extracted_at"`
+	NormalizedURL      string               `json:"-" gorm:"index"` // URL compared when looking for duplicate readings
+	ContentHash        string               `json:"-" gorm:"index"` // SHA-256 of the extracted text or uploaded file
+	FileID             *uint                `json:"file_id"`        // uploaded document assigned instead of an external URL
+	File               *UploadedFile        `json:"file,omitempty" gorm:"foreignKey:FileID"`
+	Resources          []AssignmentResource `json:"resources,omitempty" gorm:"foreignKey:AssignmentID"`
+	CreatedByID        uint                 `json:"created_by_id"`
+	CreatedBy          User                 `json:"created_by" gorm:"foreignKey:CreatedByID"`
+	CreatedAt          time.Time            `json:"created_at"`
+	UpdatedAt          time.Time            `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt       `json:"deleted_at" gorm:"index"`
 }
 
 // CreateAssignment creates a new assignment with validation
 func CreateAssignment(db *gorm.DB, title, description, url, category string, dueDate *time.Time, createdByID uint) (*Assignment, error) {
+	return CreateScheduledAssignment(db, title, description, url, category, dueDate, nil, nil, createdByID)
+}
+
+// CreateScheduledAssignment creates a new assignment that becomes visible to students at publishAt
+func CreateScheduledAssignment(db *gorm.DB, title, description, url, category string, dueDate, publishAt, unpublishAt *time.Time, createdByID uint) (*Assignment, error) {
 	assignment := &Assignment{
 		Title:       title,
 		Description: description,
 		URL:         url,
 		Category:    category,
 		DueDate:     dueDate,
+		PublishAt:   publishAt,
+		UnpublishAt: unpublishAt,
 		CreatedByID: createdByID,
 	}
 
-	result := db.Create(assignment)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := InsertAssignment(db, assignment); err != nil {
+		return nil, err
 	}
 
 	return assignment, nil
 }
 
+// InsertAssignment persists a fully populated assignment
+func InsertAssignment(db *gorm.DB, assignment *Assignment) error {
+	assignment.NormalizedURL = NormalizeURL(assignment.URL)
+	return db.Create(assignment).Error
+}
+
 // GetAssignmentByID retrieves an assignment by ID
 func GetAssignmentByID(db *gorm.DB, id uint) (*Assignment, error) {
 	var assignment Assignment
-	result := db.Preload("CreatedBy").First(&assignment, id)
+	result := db.Preload("CreatedBy").Preload("File").Preload("Resources", func(db *gorm.DB) *gorm.DB {
+		return db.Order("assignment_resources.position ASC")
+	}).First(&assignment, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -63,29 +99,228 @@ func GetAssignmentsByInstructor(db *gorm.DB, instructorID uint) ([]Assignment, e
 // UpdateAssignment updates an existing assignment
 func (a *Assignment) UpdateAssignment(db *gorm.DB, title, description, url, category string, dueDate *time.Time) error {
 	updates := map[string]interface{}{
-		"title":       title,
-		"description": description,
-		"url":         url,
-		"category":    category,
-		"due_date":    dueDate,
+		"title":          title,
+		"description":    description,
+		"url":            url,
+		"normalized_url": NormalizeURL(url),
+		"category":       category,
+		"due_date":       dueDate,
+	}
+
+	// The content hash belongs to the old page until it is extracted again
+	if url != a.URL {
+		updates["content_hash"] = ""
+	}
+
+	result := db.Model(a).Updates(updates)
+	return result.Error
+}
+
+// UpdateSchedule updates the release window of an assignment
+func (a *Assignment) UpdateSchedule(db *gorm.DB, publishAt, unpublishAt *time.Time) error {
+	updates := map[string]interface{}{
+		"publish_at":   publishAt,
+		"unpublish_at": unpublishAt,
+	}
+
+	// Moving the release into the future means students must be notified again
+	if publishAt != nil && publishAt.After(time.Now()) {
+		updates["released_at"] = nil
+	}
+
+	result := db.Model(a).Updates(updates)
+	return result.Error
+}
+
+// AttachFile makes an uploaded file the assignment's reading; nil detaches it
+func (a *Assignment) AttachFile(db *gorm.DB, file *UploadedFile) error {
+	updates := map[string]interface{}{"file_id": nil, "content_hash": ""}
+	if file != nil {
+		path := UploadedFilePath(file.ID)
+		updates["file_id"] = file.ID
+		updates["url"] = path
+		updates["normalized_url"] = NormalizeURL(path)
+		updates["content_hash"] = file.SHA256
+	}
+
+	// Skip associations so a preloaded File does not write its ID back
+	result := db.Model(a).Omit(clause.Associations).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	a.File = file
+	return nil
+}
+
+// UpdateReadingEstimate stores the extracted word count and the computed reading time.
+// An instructor override keeps its estimate.
+func (a *Assignment) UpdateReadingEstimate(db *gorm.DB, extractedURL, contentHash string, wordCount, estimatedMinutes int, extractedAt time.Time) error {
+	updates := map[string]interface{}{
+		"word_count":    wordCount,
+		"extracted_url": extractedURL,
+		"content_hash":  contentHash,
+		"extracted_at":  extractedAt,
+	}
+	if !a.EstimateOverridden {
+		updates["estimated_minutes"] = estimatedMinutes
+	}
+
+	result := db.Model(a).Updates(updates)
+	return result.Error
+}
+
+// SetEstimateOverride sets the reading time by hand, or restores the computed estimate when minutes is nil
+func (a *Assignment) SetEstimateOverride(db *gorm.DB, minutes *int, computedMinutes int) error {
+	updates := map[string]interface{}{
+		"estimated_minutes":   computedMinutes,
+		"estimate_overridden": false,
+	}
+	if minutes != nil {
+		updates["estimated_minutes"] = *minutes
+		updates["estimate_overridden"] = true
 	}
 
 	result := db.Model(a).Updates(updates)
 	return result.Error
 }
 
+// GetAssignmentsNeedingExtraction retrieves assignments whose URL has not been analyzed yet
+func GetAssignmentsNeedingExtraction(db *gorm.DB, limit int) ([]Assignment, error) {
+	var assignments []Assignment
+	result := db.Where("url <> '' AND file_id IS NULL AND (extracted_at IS NULL OR extracted_url <> url)").
+		Order("id ASC").
+		Limit(limit).
+		Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// FindDuplicateAssignments retrieves other assignments with the same normalized URL or content
+func FindDuplicateAssignments(db *gorm.DB, assignment *Assignment) ([]Assignment, error) {
+	var duplicates []Assignment
+	result := db.Preload("CreatedBy").
+		Where("id <> ?", assignment.ID).
+		Where(db.Where("normalized_url = ? AND normalized_url <> ''", assignment.NormalizedURL).
+			Or("content_hash = ? AND content_hash <> ''", assignment.ContentHash)).
+		Order("created_at ASC").
+		Find(&duplicates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return duplicates, nil
+}
+
+// PriorCompletion records a student who completed the same reading under another assignment
+type PriorCompletion struct {
+	StudentID       uint       `json:"student_id"`
+	StudentName     string     `json:"student_name"`
+	AssignmentID    uint       `json:"assignment_id"`
+	AssignmentTitle string     `json:"assignment_title"`
+	CompletedAt     *time.Time `json:"completed_at"`
+}
+
+// GetPriorCompletions finds which of the given students already completed the assignment's
+// reading under a different assignment
+func GetPriorCompletions(db *gorm.DB, assignment *Assignment, studentIDs []uint) ([]PriorCompletion, error) {
+	var completions []PriorCompletion
+	if len(studentIDs) == 0 {
+		return completions, nil
+	}
+
+	result := db.Table("student_assignments").
+		Select("student_assignments.student_id, users.username AS student_name, assignments.id AS assignment_id, assignments.title AS assignment_title, student_assignments.completed_at").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Joins("JOIN users ON users.id = student_assignments.student_id").
+		Where("student_assignments.student_id IN ? AND student_assignments.status = ? AND student_assignments.deleted_at IS NULL", studentIDs, StatusCompleted).
+		Where("assignments.id <> ? AND assignments.deleted_at IS NULL", assignment.ID).
+		Where(db.Where("assignments.normalized_url = ? AND assignments.normalized_url <> ''", assignment.NormalizedURL).
+			Or("assignments.content_hash = ? AND assignments.content_hash <> ''", assignment.ContentHash)).
+		Order("student_assignments.completed_at ASC").
+		Scan(&completions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return completions, nil
+}
+
 // DeleteAssignment soft deletes an assignment
 func (a *Assignment) DeleteAssignment(db *gorm.DB) error {
 	result := db.Delete(a)
 	return result.Error
 }
 
-// IsOverdue checks if the assignment is overdue
+// UpdateLatePolicy updates the grace period applied after the due date
+func (a *Assignment) UpdateLatePolicy(db *gorm.DB, gracePeriodMinutes int) error {
+	result := db.Model(a).Update("grace_period_minutes", gracePeriodMinutes)
+	return result.Error
+}
+
+// UpdateReflectionRequirement sets how many words of reflection students must write before completing
+func (a *Assignment) UpdateReflectionRequirement(db *gorm.DB, minWords int) error {
+	result := db.Model(a).Update("min_reflection_words", minWords)
+	return result.Error
+}
+
+// UpdateGradingPolicy sets the point value, rubric and late penalty rules of the assignment
+func (a *Assignment) UpdateGradingPolicy(db *gorm.DB, points int, rubricID *uint, latePenaltyPerDay, maxLatePenalty int) error {
+	result := db.Model(a).Omit(clause.Associations).Updates(map[string]interface{}{
+		"points":               points,
+		"rubric_id":            rubricID,
+		"late_penalty_per_day": latePenaltyPerDay,
+		"max_late_penalty":     maxLatePenalty,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	a.Points = points
+	a.RubricID = rubricID
+	a.LatePenaltyPerDay = latePenaltyPerDay
+	a.MaxLatePenalty = maxLatePenalty
+	return nil
+}
+
+// GracePeriod returns the assignment grace period as a duration
+func (a *Assignment) GracePeriod() time.Duration {
+	return time.Duration(a.GracePeriodMinutes) * time.Minute
+}
+
+// IsOverdue checks if the assignment is overdue, including its grace period
 func (a *Assignment) IsOverdue() bool {
 	if a.DueDate == nil {
 		return false
 	}
-	return time.Now().After(*a.DueDate)
+	return time.Now().After(a.DueDate.Add(a.GracePeriod()))
+}
+
+// IsPublished checks if the assignment is visible to students at the given time
+func (a *Assignment) IsPublished(now time.Time) bool {
+	if a.PublishAt != nil && a.PublishAt.After(now) {
+		return false
+	}
+	if a.UnpublishAt != nil && !a.UnpublishAt.After(now) {
+		return false
+	}
+	return true
+}
+
+// PublishedAt is a query scope that limits results to assignments visible to students at the given time.
+// The assignments table must be part of the query.
+func PublishedAt(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(assignments.publish_at IS NULL OR assignments.publish_at <= ?) AND (assignments.unpublish_at IS NULL OR assignments.unpublish_at > ?)", now, now)
+	}
+}
+
+// GetPendingReleases retrieves scheduled assignments whose publish time has passed but whose students were not notified yet
+func GetPendingReleases(db *gorm.DB, now time.Time) ([]Assignment, error) {
+	var assignments []Assignment
+	result := db.Where("publish_at IS NOT NULL AND publish_at <= ? AND released_at IS NULL", now).Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
 }
 
 // GetAssignmentsByCategory retrieves assignments by category
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssignmentArchive describes the stored offline copy of an assignment's page.
+// The HTML and images live in the archive directory under the assignment ID.
+type AssignmentArchive struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AssignmentID uint      `json:"assignment_id" gorm:"not null;uniqueIndex"`
+	SourceURL    string    `json:"source_url" gorm:"not null"`
+	FinalURL     string    `json:"final_url"`
+	Title        string    `json:"title"`
+	SizeBytes    int64     `json:"size_bytes"`
+	ImageCount   int       `json:"image_count"`
+	ArchivedAt   time.Time `json:"archived_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// GetAssignmentArchive retrieves the archive of an assignment
+func GetAssignmentArchive(db *gorm.DB, assignmentID uint) (*AssignmentArchive, error) {
+	var archive AssignmentArchive
+	result := db.Where("assignment_id = ?", assignmentID).First(&archive)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &archive, nil
+}
+
+// SaveAssignmentArchive stores an archive, replacing any previous one for the assignment
+func SaveAssignmentArchive(db *gorm.DB, archive *AssignmentArchive) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", archive.AssignmentID).Delete(&AssignmentArchive{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(archive).Error
+	})
+}
+
+// DeleteAssignmentArchive removes the archive record of an assignment
+func DeleteAssignmentArchive(db *gorm.DB, assignmentID uint) error {
+	return db.Where("assignment_id = ?", assignmentID).Delete(&AssignmentArchive{}).Error
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrResourcesIncomplete is returned when an assignment is completed before its required resources are finished
+var ErrResourcesIncomplete = errors.New("resources incomplete")
+
+// AssignmentResource is one typed piece of content within an assignment
+type AssignmentResource struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	AssignmentID     uint      `json:"assignment_id" gorm:"not null;index"`
+	Type             string    `json:"type" gorm:"not null"`
+	Title            string    `json:"title" gorm:"not null"`
+	URL              string    `json:"url" gorm:"not null"`
+	EstimatedMinutes int       `json:"estimated_minutes"`
+	Optional         bool      `json:"optional"` // optional resources do not block completion
+	Position         int       `json:"position"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Resource type constants
+const (
+	ResourceArticle     = "article"
+	ResourceVideo       = "video"
+	ResourcePDF         = "pdf"
+	ResourceRepository  = "repository"
+	ResourceBookChapter = "book_chapter"
+)
+
+// IsValidResourceType reports whether the resource type is supported
+func IsValidResourceType(resourceType string) bool {
+	switch resourceType {
+	case ResourceArticle, ResourceVideo, ResourcePDF, ResourceRepository, ResourceBookChapter:
+		return true
+	}
+	return false
+}
+
+// StudentResourceProgress records that a student finished a single resource
+type StudentResourceProgress struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	StudentAssignmentID uint      `json:"student_assignment_id" gorm:"not null;uniqueIndex:idx_student_resource"`
+	ResourceID          uint      `json:"resource_id" gorm:"not null;uniqueIndex:idx_student_resource"`
+	CompletedAt         time.Time `json:"completed_at"`
+}
+
+// GetAssignmentResources retrieves an assignment's resources in order
+func GetAssignmentResources(db *gorm.DB, assignmentID uint) ([]AssignmentResource, error) {
+	var resources []AssignmentResource
+	result := db.Where("assignment_id = ?", assignmentID).Order("position ASC, id ASC").Find(&resources)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return resources, nil
+}
+
+// GetCompletedResourceIDs returns the set of resources a student assignment has finished
+func GetCompletedResourceIDs(db *gorm.DB, studentAssignmentID uint) (map[uint]time.Time, error) {
+	var progress []StudentResourceProgress
+	if err := db.Where("student_assignment_id = ?", studentAssignmentID).Find(&progress).Error; err != nil {
+		return nil, err
+	}
+
+	completed := make(map[uint]time.Time, len(progress))
+	for _, p := range progress {
+		completed[p.ResourceID] = p.CompletedAt
+	}
+	return completed, nil
+}
+
+// SetResourceCompleted marks or unmarks a resource as finished for a student assignment
+func SetResourceCompleted(db *gorm.DB, studentAssignmentID, resourceID uint, completed bool) error {
+	if !completed {
+		return db.Where("student_assignment_id = ? AND resource_id = ?", studentAssignmentID, resourceID).
+			Delete(&StudentResourceProgress{}).Error
+	}
+
+	var existing StudentResourceProgress
+	err := db.Where("student_assignment_id = ? AND resource_id = ?", studentAssignmentID, resourceID).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	return db.Create(&StudentResourceProgress{
+		StudentAssignmentID: studentAssignmentID,
+		ResourceID:          resourceID,
+		CompletedAt:         time.Now(),
+	}).Error
+}
+
+// ResourcesComplete reports whether every required resource is in the completed set.
+// When all resources are optional, finishing any one of them counts.
+func ResourcesComplete(resources []AssignmentResource, completed map[uint]time.Time) bool {
+	required := 0
+	for _, resource := range resources {
+		if resource.Optional {
+			continue
+		}
+		required++
+		if _, ok := completed[resource.ID]; !ok {
+			return false
+		}
+	}
+
+	if required == 0 {
+		for _, resource := range resources {
+			if _, ok := completed[resource.ID]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// checkResources rejects completion while a resource the assignment requires is unfinished
+func (sa *StudentAssignment) checkResources(db *gorm.DB) error {
+	resources, err := GetAssignmentResources(db, sa.AssignmentID)
+	if err != nil || len(resources) == 0 {
+		return err
+	}
+
+	completed, err := GetCompletedResourceIDs(db, sa.ID)
+	if err != nil {
+		return err
+	}
+
+	if !ResourcesComplete(resources, completed) {
+		return fmt.Errorf("%w: finish the required resources before completing this reading", ErrResourcesIncomplete)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssignmentTemplate holds reusable assignment fields that new assignments can be created from
+type AssignmentTemplate struct {
+	ID                 uint           `json:"id" gorm:"primaryKey"`
+	Name               string         `json:"name" gorm:"not null"`
+	Title              string         `json:"title" gorm:"not null"`
+	Description        string         `json:"description"`
+	URL                string         `json:"url" gorm:"not null"`
+	Category           string         `json:"category"`
+	GracePeriodMinutes int            `json:"grace_period_minutes" gorm:"default:0"`
+	CreatedByID        uint           `json:"created_by_id" gorm:"not null;index"`
+	CreatedBy          User           `json:"created_by" gorm:"foreignKey:CreatedByID"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// CreateAssignmentTemplate creates a new assignment template
+func CreateAssignmentTemplate(db *gorm.DB, template *AssignmentTemplate) error {
+	return db.Create(template).Error
+}
+
+// GetAssignmentTemplateByID retrieves an assignment template by ID
+func GetAssignmentTemplateByID(db *gorm.DB, id uint) (*AssignmentTemplate, error) {
+	var template AssignmentTemplate
+	result := db.First(&template, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &template, nil
+}
+
+// GetAssignmentTemplatesByInstructor retrieves all templates created by an instructor
+func GetAssignmentTemplatesByInstructor(db *gorm.DB, instructorID uint) ([]AssignmentTemplate, error) {
+	var templates []AssignmentTemplate
+	result := db.Where("created_by_id = ?", instructorID).Order("name ASC").Find(&templates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return templates, nil
+}
+
+// Update saves changes to an assignment template
+func (t *AssignmentTemplate) Update(db *gorm.DB, name, title, description, url, category string, gracePeriodMinutes int) error {
+	updates := map[string]interface{}{
+		"name":                 name,
+		"title":                title,
+		"description":          description,
+		"url":                  url,
+		"category":             category,
+		"grace_period_minutes": gracePeriodMinutes,
+	}
+
+	result := db.Model(t).Updates(updates)
+	return result.Error
+}
+
+// Delete soft deletes an assignment template
+func (t *AssignmentTemplate) Delete(db *gorm.DB) error {
+	return db.Delete(t).Error
+}
+
+// AssignmentRecurrence generates dated assignments from a template on a schedule
+type AssignmentRecurrence struct {
+	ID               uint               `json:"id" gorm:"primaryKey"`
+	TemplateID       uint               `json:"template_id" gorm:"not null;index"`
+	Template         AssignmentTemplate `json:"template" gorm:"foreignKey:TemplateID"`
+	RRule            string             `json:"rrule" gorm:"not null"`           // RFC 5545 subset, e.g. FREQ=WEEKLY;BYDAY=MO
+	StartsAt         time.Time          `json:"starts_at" gorm:"not null"`       // first occurrence and time of day for all occurrences
+	DueOffsetHours   int                `json:"due_offset_hours"`                // due date relative to each occurrence, 0 for no due date
+	NextOccurrenceAt *time.Time         `json:"next_occurrence_at" gorm:"index"` // nil once the rule is exhausted
+	OccurrenceCount  int                `json:"occurrence_count" gorm:"default:0"`
+	Active           bool               `json:"active" gorm:"default:true"`
+	Students         []User             `json:"students" gorm:"many2many:assignment_recurrence_students"`
+	Groups           []Group            `json:"groups" gorm:"many2many:assignment_recurrence_groups"` // members are resolved at each occurrence
+	CreatedByID      uint               `json:"created_by_id" gorm:"not null;index"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt     `json:"deleted_at" gorm:"index"`
+}
+
+// GetAssignmentRecurrenceByID retrieves a recurrence with its template, roster and groups
+func GetAssignmentRecurrenceByID(db *gorm.DB, id uint) (*AssignmentRecurrence, error) {
+	var recurrence AssignmentRecurrence
+	result := db.Preload("Template").Preload("Students").Preload("Groups").First(&recurrence, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &recurrence, nil
+}
+
+// GetAssignmentRecurrencesByInstructor retrieves all recurrences created by an instructor
+func GetAssignmentRecurrencesByInstructor(db *gorm.DB, instructorID uint) ([]AssignmentRecurrence, error) {
+	var recurrences []AssignmentRecurrence
+	result := db.Preload("Template").Preload("Students").Preload("Groups").Where("created_by_id = ?", instructorID).Find(&recurrences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return recurrences, nil
+}
+
+// GetDueRecurrences retrieves active recurrences whose next occurrence is at or before the horizon
+func GetDueRecurrences(db *gorm.DB, horizon time.Time) ([]AssignmentRecurrence, error) {
+	var recurrences []AssignmentRecurrence
+	result := db.Preload("Template").Preload("Students").Preload("Groups").
+		Where("active = ? AND next_occurrence_at IS NOT NULL AND next_occurrence_at <= ?", true, horizon).
+		Find(&recurrences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return recurrences, nil
+}
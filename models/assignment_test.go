@@ -16,7 +16,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Auto-migrate models
-	err = db.AutoMigrate(&User{}, &Assignment{}, &StudentAssignment{})
+	err = db.AutoMigrate(All()...)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -286,3 +286,50 @@ func TestGetAssignmentsByCategory(t *testing.T) {
 		t.Errorf("Expected 1 homework assignment, got %d", len(homeworkAssignments))
 	}
 }
+
+func TestAssignmentIsPublished(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		publishAt   *time.Time
+		unpublishAt *time.Time
+		expected    bool
+	}{
+		{"no schedule", nil, nil, true},
+		{"published in the past", &past, nil, true},
+		{"scheduled for the future", &future, nil, false},
+		{"window closed", &past, &past, false},
+		{"window still open", &past, &future, true},
+	}
+
+	for _, tt := range tests {
+		assignment := &Assignment{PublishAt: tt.publishAt, UnpublishAt: tt.unpublishAt}
+		if got := assignment.IsPublished(now); got != tt.expected {
+			t.Errorf("%s: expected IsPublished %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestGetPendingReleases(t *testing.T) {
+	db := setupTestDB(t)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	CreateAssignment(db, "Immediate", "", "https://example.com/1", "reading", nil, instructor.ID)
+	due, _ := CreateScheduledAssignment(db, "Due", "", "https://example.com/2", "reading", nil, &past, nil, instructor.ID)
+	CreateScheduledAssignment(db, "Later", "", "https://example.com/3", "reading", nil, &future, nil, instructor.ID)
+
+	pending, err := GetPendingReleases(db, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get pending releases: %v", err)
+	}
+
+	if len(pending) != 1 || pending[0].ID != due.ID {
+		t.Fatalf("Expected only assignment %d to be pending, got %v", due.ID, pending)
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit actions
+const (
+	AuditAssignmentCreated = "assignment.created"
+	AuditAssignmentUpdated = "assignment.updated"
+	AuditAssignmentDeleted = "assignment.deleted"
+	AuditStudentAssigned   = "assignment.student_assigned"
+	AuditStudentRemoved    = "assignment.student_removed"
+	AuditExtensionChanged  = "assignment.extension_changed"
+	AuditExcuseChanged     = "assignment.excuse_changed"
+	AuditLogin             = "auth.login"
+	AuditLoginFailed       = "auth.login_failed"
+	AuditRegistered        = "auth.registered"
+)
+
+// Audit targets
+const (
+	AuditTargetAssignment        = "assignment"
+	AuditTargetStudentAssignment = "student_assignment"
+	AuditTargetUser              = "user"
+)
+
+// ErrAuditEventImmutable is returned when something tries to change or delete an audit event
+var ErrAuditEventImmutable = errors.New("audit events cannot be changed")
+
+// AuditEvent records who did what to which record, and from where. Events are append-only.
+// OwnerID is the user whose audit log shows the event: the instructor who owns the
+// assignment, or the user who logged in. A failed login for an unknown username has none.
+// Changes to a student's place on an assignment target the student and name the assignment.
+type AuditEvent struct {
+	ID           uint                   `json:"id" gorm:"primaryKey"`
+	ActorID      *uint                  `json:"actor_id" gorm:"index"` // nil when nobody was signed in, as on a failed login
+	ActorName    string                 `json:"actor_name"`            // username at the time, or the username tried
+	OwnerID      *uint                  `json:"-" gorm:"index"`
+	Action       string                 `json:"action" gorm:"index;not null"`
+	TargetType   string                 `json:"target_type"`
+	TargetID     uint                   `json:"target_id"`
+	AssignmentID *uint                  `json:"assignment_id,omitempty" gorm:"index"`
+	Changes      map[string]AuditChange `json:"changes,omitempty" gorm:"serializer:json"`
+	IP           string                 `json:"ip"`
+	CreatedAt    time.Time              `json:"created_at" gorm:"index"`
+}
+
+// AuditChange is a field's value before and after an audited action
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditFilter narrows the audit events returned to an owner
+type AuditFilter struct {
+	Action       string
+	ActorID      *uint
+	TargetType   string
+	TargetID     *uint
+	AssignmentID *uint
+	From         *time.Time // inclusive
+	To           *time.Time // exclusive
+	Limit        int
+}
+
+// BeforeUpdate keeps audit events append-only
+func (e *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+// BeforeDelete keeps audit events append-only
+func (e *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+// CreateAuditEvents appends events to the audit log
+func CreateAuditEvents(db *gorm.DB, events []AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Create(&events).Error
+}
+
+// GetAuditEvents retrieves an owner's audit events matching the filter, newest first
+func GetAuditEvents(db *gorm.DB, ownerID uint, filter AuditFilter) ([]AuditEvent, error) {
+	query := db.Where("owner_id = ?", ownerID)
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.AssignmentID != nil {
+		query = query.Where("assignment_id = ?", *filter.AssignmentID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var events []AuditEvent
+	result := query.Order("created_at DESC, id DESC").Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DiscussionPost is a post in an assignment's discussion. A post without a parent opens a
+// thread; replies point at the post they answer and at the thread they belong to.
+type DiscussionPost struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	AssignmentID uint             `json:"assignment_id" gorm:"index;not null"`
+	ThreadID     *uint            `json:"thread_id" gorm:"index"` // nil for the post that opens a thread
+	ParentID     *uint            `json:"parent_id" gorm:"index"`
+	AuthorID     uint             `json:"author_id" gorm:"not null"`
+	Author       User             `json:"-" gorm:"foreignKey:AuthorID"`
+	AuthorName   string           `json:"author_name" gorm:"-"`
+	AuthorRole   string           `json:"author_role" gorm:"-"`
+	Content      string           `json:"content" gorm:"type:text"`      // Markdown as written by the author
+	ContentHTML  string           `json:"content_html" gorm:"type:text"` // sanitized rendering of Content
+	Pinned       bool             `json:"pinned" gorm:"default:false"`   // pinned by the instructor, as the answer for replies
+	Hidden       bool             `json:"hidden" gorm:"default:false"`   // hidden from students by the instructor
+	HiddenByID   *uint            `json:"-"`
+	EditedAt     *time.Time       `json:"edited_at"`
+	Deleted      bool             `json:"deleted" gorm:"-"`
+	Replies      []DiscussionPost `json:"replies,omitempty" gorm:"-"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+// DiscussionRevision keeps the content a post had before it was edited
+type DiscussionRevision struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PostID    uint      `json:"post_id" gorm:"index;not null"`
+	EditorID  uint      `json:"editor_id"`
+	Content   string    `json:"content" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RootID returns the ID of the thread the post belongs to
+func (p *DiscussionPost) RootID() uint {
+	if p.ThreadID != nil {
+		return *p.ThreadID
+	}
+	return p.ID
+}
+
+// CreateDiscussionPost stores a new post
+func CreateDiscussionPost(db *gorm.DB, post *DiscussionPost) error {
+	result := db.Create(post)
+	return result.Error
+}
+
+// GetDiscussionPost retrieves a post with its author
+func GetDiscussionPost(db *gorm.DB, postID uint) (*DiscussionPost, error) {
+	var post DiscussionPost
+	result := db.Preload("Author").First(&post, postID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &post, nil
+}
+
+// GetDiscussionPosts retrieves every post on an assignment, oldest first. Deleted posts are
+// included so that replies to them keep their place.
+func GetDiscussionPosts(db *gorm.DB, assignmentID uint) ([]DiscussionPost, error) {
+	var posts []DiscussionPost
+	result := db.Unscoped().Preload("Author").
+		Where("assignment_id = ?", assignmentID).
+		Order("created_at ASC, id ASC").
+		Find(&posts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return posts, nil
+}
+
+// GetDiscussionParticipants returns the IDs of everyone who posted in a thread
+func GetDiscussionParticipants(db *gorm.DB, threadID uint) ([]uint, error) {
+	var authorIDs []uint
+	result := db.Model(&DiscussionPost{}).
+		Where("id = ? OR thread_id = ?", threadID, threadID).
+		Distinct().
+		Pluck("author_id", &authorIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return authorIDs, nil
+}
+
+// EditDiscussionPost replaces a post's content, keeping the previous content as a revision
+func EditDiscussionPost(db *gorm.DB, post *DiscussionPost, editorID uint, content, contentHTML string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		revision := &DiscussionRevision{PostID: post.ID, EditorID: editorID, Content: post.Content}
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err := tx.Model(post).Omit(clause.Associations).Updates(map[string]interface{}{
+			"content":      content,
+			"content_html": contentHTML,
+			"edited_at":    &now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		post.Content = content
+		post.ContentHTML = contentHTML
+		post.EditedAt = &now
+		return nil
+	})
+}
+
+// GetDiscussionRevisions retrieves the earlier versions of a post, oldest first
+func GetDiscussionRevisions(db *gorm.DB, postID uint) ([]DiscussionRevision, error) {
+	var revisions []DiscussionRevision
+	result := db.Where("post_id = ?", postID).Order("created_at ASC, id ASC").Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+// DeleteDiscussionPost removes a post; its content is kept for the instructor's history
+func DeleteDiscussionPost(db *gorm.DB, post *DiscussionPost) error {
+	result := db.Delete(post)
+	return result.Error
+}
+
+// SetDiscussionPostPinned pins or unpins a post
+func SetDiscussionPostPinned(db *gorm.DB, post *DiscussionPost, pinned bool) error {
+	result := db.Model(post).Omit(clause.Associations).Update("pinned", pinned)
+	if result.Error != nil {
+		return result.Error
+	}
+	post.Pinned = pinned
+	return nil
+}
+
+// SetDiscussionPostHidden hides a post from students or shows it again
+func SetDiscussionPostHidden(db *gorm.DB, post *DiscussionPost, hidden bool, moderatorID uint) error {
+	var hiddenByID *uint
+	if hidden {
+		hiddenByID = &moderatorID
+	}
+	result := db.Model(post).Omit(clause.Associations).Updates(map[string]interface{}{
+		"hidden":       hidden,
+		"hidden_by_id": hiddenByID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	post.Hidden = hidden
+	post.HiddenByID = hiddenByID
+	return nil
+}
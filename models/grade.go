@@ -0,0 +1,235 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Rubric is a reusable set of scoring criteria an instructor grades assignments with
+type Rubric struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name" gorm:"not null"`
+	Description string            `json:"description"`
+	Criteria    []RubricCriterion `json:"criteria" gorm:"serializer:json"`
+	CreatedByID uint              `json:"created_by_id" gorm:"index;not null"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// RubricCriterion is one line of a grading rubric, scored from 0 to Points
+type RubricCriterion struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Points      int    `json:"points"`
+}
+
+// Grade is an instructor's score for a student assignment. RawScore is out of the
+// assignment's points before any late penalty, which is worked out from the completion
+// time whenever the grade is read so that extensions and excusals apply automatically.
+type Grade struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	StudentAssignmentID uint      `json:"student_assignment_id" gorm:"uniqueIndex;not null"`
+	CriterionScores     []float64 `json:"criterion_scores,omitempty" gorm:"serializer:json"` // in rubric order, when graded with a rubric
+	RawScore            float64   `json:"raw_score"`
+	Feedback            string    `json:"feedback" gorm:"type:text"`
+	GradedByID          uint      `json:"graded_by_id"`
+	GradedAt            time.Time `json:"graded_at"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// CategoryWeight is the weight an instructor gives an assignment category in the gradebook
+type CategoryWeight struct {
+	ID           uint    `json:"id" gorm:"primaryKey"`
+	InstructorID uint    `json:"instructor_id" gorm:"not null;uniqueIndex:idx_category_weight"`
+	Category     string  `json:"category" gorm:"not null;uniqueIndex:idx_category_weight"`
+	Weight       float64 `json:"weight"`
+}
+
+// TotalPoints sums the points of every criterion
+func (r *Rubric) TotalPoints() int {
+	total := 0
+	for _, criterion := range r.Criteria {
+		total += criterion.Points
+	}
+	return total
+}
+
+// LatePenaltyPercent returns the percentage deducted from the student's score for completing
+// late: the assignment's daily penalty for every started day past the effective due date and
+// grace period, up to its cap. Unfinished and excused work carries no penalty.
+func (sa *StudentAssignment) LatePenaltyPercent() int {
+	if sa.CompletedAt == nil || sa.Assignment.LatePenaltyPerDay <= 0 || !sa.IsPastDue(*sa.CompletedAt) {
+		return 0
+	}
+
+	deadline := sa.EffectiveDueDate().Add(sa.Assignment.GracePeriod())
+	days := int(math.Ceil(sa.CompletedAt.Sub(deadline).Hours() / 24))
+	penalty := days * sa.Assignment.LatePenaltyPerDay
+
+	limit := 100
+	if sa.Assignment.MaxLatePenalty > 0 && sa.Assignment.MaxLatePenalty < limit {
+		limit = sa.Assignment.MaxLatePenalty
+	}
+	if penalty > limit {
+		penalty = limit
+	}
+	return penalty
+}
+
+// ApplyLatePenalty deducts a percentage penalty from a score, rounded to hundredths
+func ApplyLatePenalty(score float64, penaltyPercent int) float64 {
+	return math.Round(score*float64(100-penaltyPercent)) / 100
+}
+
+// CreateRubric stores a new rubric
+func CreateRubric(db *gorm.DB, rubric *Rubric) error {
+	result := db.Create(rubric)
+	return result.Error
+}
+
+// GetRubricByID retrieves a rubric by ID
+func GetRubricByID(db *gorm.DB, rubricID uint) (*Rubric, error) {
+	var rubric Rubric
+	result := db.First(&rubric, rubricID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rubric, nil
+}
+
+// GetRubricsByInstructor retrieves an instructor's rubrics by name
+func GetRubricsByInstructor(db *gorm.DB, instructorID uint) ([]Rubric, error) {
+	var rubrics []Rubric
+	result := db.Where("created_by_id = ?", instructorID).Order("name ASC").Find(&rubrics)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rubrics, nil
+}
+
+// SaveRubric updates a rubric
+func SaveRubric(db *gorm.DB, rubric *Rubric) error {
+	result := db.Save(rubric)
+	return result.Error
+}
+
+// DeleteRubric removes a rubric and detaches it from the assignments that used it
+func DeleteRubric(db *gorm.DB, rubric *Rubric) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Assignment{}).Where("rubric_id = ?", rubric.ID).Update("rubric_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(rubric).Error
+	})
+}
+
+// GetGradedAssignmentsByInstructor retrieves an instructor's assignments that carry points, by due date
+func GetGradedAssignmentsByInstructor(db *gorm.DB, instructorID uint) ([]Assignment, error) {
+	var assignments []Assignment
+	result := db.Where("created_by_id = ? AND points > 0", instructorID).
+		Order("due_date IS NULL, due_date ASC, id ASC").
+		Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// CountGradesByRubric counts the grades given on assignments scored with a rubric
+func CountGradesByRubric(db *gorm.DB, rubricID uint) (int64, error) {
+	var count int64
+	result := db.Model(&Grade{}).
+		Joins("JOIN student_assignments ON student_assignments.id = grades.student_assignment_id").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.rubric_id = ?", rubricID).
+		Count(&count)
+	return count, result.Error
+}
+
+// CountGradesByAssignment counts the grades given on an assignment
+func CountGradesByAssignment(db *gorm.DB, assignmentID uint) (int64, error) {
+	var count int64
+	result := db.Model(&Grade{}).
+		Joins("JOIN student_assignments ON student_assignments.id = grades.student_assignment_id").
+		Where("student_assignments.assignment_id = ?", assignmentID).
+		Count(&count)
+	return count, result.Error
+}
+
+// GetGrade retrieves the grade of a student assignment
+func GetGrade(db *gorm.DB, studentAssignmentID uint) (*Grade, error) {
+	var grade Grade
+	result := db.Where("student_assignment_id = ?", studentAssignmentID).First(&grade)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &grade, nil
+}
+
+// GetGradesByStudentAssignments retrieves the grades of many student assignments, keyed by student assignment ID
+func GetGradesByStudentAssignments(db *gorm.DB, studentAssignmentIDs []uint) (map[uint]Grade, error) {
+	grades := make(map[uint]Grade)
+	if len(studentAssignmentIDs) == 0 {
+		return grades, nil
+	}
+
+	var rows []Grade
+	result := db.Where("student_assignment_id IN ?", studentAssignmentIDs).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, grade := range rows {
+		grades[grade.StudentAssignmentID] = grade
+	}
+	return grades, nil
+}
+
+// SaveGrade creates or replaces the grade of a student assignment
+func SaveGrade(db *gorm.DB, grade *Grade) error {
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_assignment_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"criterion_scores", "raw_score", "feedback", "graded_by_id", "graded_at", "updated_at"}),
+	}).Create(grade)
+	return result.Error
+}
+
+// DeleteGrade removes the grade of a student assignment
+func DeleteGrade(db *gorm.DB, studentAssignmentID uint) error {
+	result := db.Where("student_assignment_id = ?", studentAssignmentID).Delete(&Grade{})
+	return result.Error
+}
+
+// GetCategoryWeights retrieves an instructor's category weights, keyed by category
+func GetCategoryWeights(db *gorm.DB, instructorID uint) (map[string]float64, error) {
+	var rows []CategoryWeight
+	result := db.Where("instructor_id = ?", instructorID).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	weights := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		weights[row.Category] = row.Weight
+	}
+	return weights, nil
+}
+
+// SetCategoryWeights replaces an instructor's category weights
+func SetCategoryWeights(db *gorm.DB, instructorID uint, weights map[string]float64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("instructor_id = ?", instructorID).Delete(&CategoryWeight{}).Error; err != nil {
+			return err
+		}
+		for category, weight := range weights {
+			row := CategoryWeight{InstructorID: instructorID, Category: category, Weight: weight}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
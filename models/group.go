@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Group is a named set of students an instructor assigns readings to together
+type Group struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	Course      string         `json:"course" gorm:"index"` // the course the group studies, for comparing cohorts across courses
+	CreatedByID uint           `json:"created_by_id" gorm:"not null;index"`
+	CreatedBy   User           `json:"-" gorm:"foreignKey:CreatedByID"`
+	Members     []GroupMember  `json:"members" gorm:"foreignKey:GroupID"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// GroupMember places a student in a group
+type GroupMember struct {
+	ID       uint      `json:"id" gorm:"primaryKey"`
+	GroupID  uint      `json:"group_id" gorm:"not null;uniqueIndex:idx_group_member"`
+	UserID   uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_group_member;index"`
+	User     User      `json:"user" gorm:"foreignKey:UserID"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// CreateGroup creates a group together with its members
+func CreateGroup(db *gorm.DB, group *Group) error {
+	return db.Create(group).Error
+}
+
+// GetGroupByID retrieves a group with its members
+func GetGroupByID(db *gorm.DB, id uint) (*Group, error) {
+	var group Group
+	result := db.Preload("Members.User").First(&group, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &group, nil
+}
+
+// GetGroupsByInstructor retrieves all groups created by an instructor
+func GetGroupsByInstructor(db *gorm.DB, instructorID uint) ([]Group, error) {
+	var groups []Group
+	result := db.Preload("Members.User").Where("created_by_id = ?", instructorID).Order("name ASC").Find(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
+
+// GetGroupMemberIDs returns the distinct students in any of the groups
+func GetGroupMemberIDs(db *gorm.DB, groupIDs []uint) ([]uint, error) {
+	var studentIDs []uint
+	if len(groupIDs) == 0 {
+		return studentIDs, nil
+	}
+	result := db.Model(&GroupMember{}).Distinct("user_id").Where("group_id IN ?", groupIDs).Order("user_id ASC").Pluck("user_id", &studentIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return studentIDs, nil
+}
+
+// Update saves the group's details and replaces its members.
+// Students who stay in the group keep their original join date.
+func (g *Group) Update(db *gorm.DB, name, description, course string, studentIDs []uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(g).Updates(map[string]interface{}{"name": name, "description": description, "course": course}).Error; err != nil {
+			return err
+		}
+
+		removed := tx.Where("group_id = ?", g.ID)
+		if len(studentIDs) > 0 {
+			removed = removed.Where("user_id NOT IN ?", studentIDs)
+		}
+		if err := removed.Delete(&GroupMember{}).Error; err != nil {
+			return err
+		}
+
+		var existing []uint
+		if err := tx.Model(&GroupMember{}).Where("group_id = ?", g.ID).Pluck("user_id", &existing).Error; err != nil {
+			return err
+		}
+		kept := make(map[uint]bool, len(existing))
+		for _, studentID := range existing {
+			kept[studentID] = true
+		}
+		var joining []uint
+		for _, studentID := range studentIDs {
+			if !kept[studentID] {
+				joining = append(joining, studentID)
+			}
+		}
+		if len(joining) > 0 {
+			members := NewGroupMembers(joining)
+			for i := range members {
+				members[i].GroupID = g.ID
+			}
+			if err := tx.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+
+		g.Name, g.Description, g.Course = name, description, course
+		return nil
+	})
+}
+
+// Delete soft deletes a group; its members keep their assignments
+func (g *Group) Delete(db *gorm.DB) error {
+	return db.Delete(g).Error
+}
+
+// NewGroupMembers builds the memberships for students joining a group now
+func NewGroupMembers(studentIDs []uint) []GroupMember {
+	now := time.Now()
+	members := make([]GroupMember, 0, len(studentIDs))
+	for _, studentID := range studentIDs {
+		members = append(members, GroupMember{UserID: studentID, JoinedAt: now})
+	}
+	return members
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeaderboardSettings records whether an instructor shows a leaderboard to the students on their roster
+type LeaderboardSettings struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	InstructorID uint      `json:"instructor_id" gorm:"not null;uniqueIndex"`
+	Enabled      bool      `json:"enabled" gorm:"default:false"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// LeaderboardProfile is how a student appears on leaderboards. Students who opt out are left
+// off every board; a pseudonym replaces their username.
+type LeaderboardProfile struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	StudentID uint      `json:"student_id" gorm:"not null;uniqueIndex"`
+	OptOut    bool      `json:"opt_out" gorm:"default:false"`
+	Pseudonym string    `json:"pseudonym"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LeaderboardRow is a student's completion totals on one instructor's readings
+type LeaderboardRow struct {
+	StudentID uint
+	Completed int
+	OnTime    int
+}
+
+// GetLeaderboardSettings retrieves an instructor's leaderboard settings
+func GetLeaderboardSettings(db *gorm.DB, instructorID uint) (*LeaderboardSettings, error) {
+	var settings LeaderboardSettings
+	result := db.Where("instructor_id = ?", instructorID).First(&settings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &settings, nil
+}
+
+// SaveLeaderboardSettings creates or updates an instructor's leaderboard settings
+func SaveLeaderboardSettings(db *gorm.DB, settings *LeaderboardSettings) error {
+	result := db.Save(settings)
+	return result.Error
+}
+
+// GetLeaderboardInstructors retrieves the instructors with a leaderboard enabled who assigned readings to a student
+func GetLeaderboardInstructors(db *gorm.DB, studentID uint) ([]User, error) {
+	var instructors []User
+	result := db.Model(&User{}).
+		Joins("JOIN leaderboard_settings ON leaderboard_settings.instructor_id = users.id AND leaderboard_settings.enabled = ?", true).
+		Where("users.id IN (?)", db.Model(&StudentAssignment{}).
+			Select("assignments.created_by_id").
+			Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id AND assignments.deleted_at IS NULL").
+			Where("student_assignments.student_id = ?", studentID)).
+		Order("users.username ASC").
+		Find(&instructors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return instructors, nil
+}
+
+// GetLeaderboardProfile retrieves a student's leaderboard profile
+func GetLeaderboardProfile(db *gorm.DB, studentID uint) (*LeaderboardProfile, error) {
+	var profile LeaderboardProfile
+	result := db.Where("student_id = ?", studentID).First(&profile)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &profile, nil
+}
+
+// GetLeaderboardProfiles retrieves the leaderboard profiles of several students, keyed by student ID
+func GetLeaderboardProfiles(db *gorm.DB, studentIDs []uint) (map[uint]LeaderboardProfile, error) {
+	profiles := make(map[uint]LeaderboardProfile, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return profiles, nil
+	}
+
+	var rows []LeaderboardProfile
+	result := db.Where("student_id IN ?", studentIDs).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		profiles[row.StudentID] = row
+	}
+	return profiles, nil
+}
+
+// SaveLeaderboardProfile creates or updates a student's leaderboard profile
+func SaveLeaderboardProfile(db *gorm.DB, profile *LeaderboardProfile) error {
+	result := db.Save(profile)
+	return result.Error
+}
+
+// GetLeaderboardRows totals the completions of every student on an instructor's roster
+func GetLeaderboardRows(db *gorm.DB, instructorID uint) ([]LeaderboardRow, error) {
+	var rows []LeaderboardRow
+	result := db.Model(&StudentAssignment{}).
+		Select("student_assignments.student_id, "+
+			"SUM(CASE WHEN student_assignments.status = ? THEN 1 ELSE 0 END) AS completed, "+
+			"SUM(CASE WHEN student_assignments.status = ? AND student_assignments.completion_timing = ? THEN 1 ELSE 0 END) AS on_time", StatusCompleted, StatusCompleted, TimingOnTime).
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.created_by_id = ? AND assignments.deleted_at IS NULL", instructorID).
+		Group("student_assignments.student_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LinkCheck records the latest reachability check of an assignment URL
+type LinkCheck struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	AssignmentID   uint       `json:"assignment_id" gorm:"not null;uniqueIndex"`
+	Assignment     Assignment `json:"assignment" gorm:"foreignKey:AssignmentID"`
+	URL            string     `json:"url" gorm:"not null"`
+	Health         string     `json:"health" gorm:"not null;index"`
+	StatusCode     int        `json:"status_code"`
+	RedirectChain  []string   `json:"redirect_chain" gorm:"serializer:json"`
+	FinalURL       string     `json:"final_url"`
+	Error          string     `json:"error"`
+	CheckedAt      time.Time  `json:"checked_at"`
+	NotifiedHealth string     `json:"-"` // health the owner was last notified about
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Link health constants
+const (
+	LinkHealthOK     = "ok"
+	LinkHealthMoved  = "moved"
+	LinkHealthBroken = "broken"
+)
+
+// GetLinkCheckByAssignment retrieves the latest check for an assignment
+func GetLinkCheckByAssignment(db *gorm.DB, assignmentID uint) (*LinkCheck, error) {
+	var check LinkCheck
+	result := db.Where("assignment_id = ?", assignmentID).First(&check)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &check, nil
+}
+
+// SaveLinkCheck creates or replaces the check for an assignment
+func SaveLinkCheck(db *gorm.DB, check *LinkCheck) error {
+	if check.ID == 0 {
+		return db.Create(check).Error
+	}
+	return db.Save(check).Error
+}
+
+// GetLinkProblemsByInstructor retrieves broken or moved links on an instructor's assignments
+func GetLinkProblemsByInstructor(db *gorm.DB, instructorID uint) ([]LinkCheck, error) {
+	var checks []LinkCheck
+	result := db.Preload("Assignment").
+		Joins("JOIN assignments ON assignments.id = link_checks.assignment_id").
+		Where("assignments.created_by_id = ? AND assignments.deleted_at IS NULL", instructorID).
+		Where("link_checks.health <> ?", LinkHealthOK).
+		Order("link_checks.checked_at DESC").
+		Find(&checks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return checks, nil
+}
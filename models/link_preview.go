@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LinkPreview caches metadata fetched from an assignment URL
+type LinkPreview struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	URL         string    `json:"url" gorm:"not null;uniqueIndex"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"image_url"`
+	SiteName    string    `json:"site_name"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// GetLinkPreview retrieves a cached preview fetched after the given time
+func GetLinkPreview(db *gorm.DB, url string, fetchedAfter time.Time) (*LinkPreview, error) {
+	var preview LinkPreview
+	result := db.Where("url = ? AND fetched_at > ?", url, fetchedAfter).First(&preview)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &preview, nil
+}
+
+// SaveLinkPreview stores a preview, replacing any cached entry for the same URL
+func SaveLinkPreview(db *gorm.DB, preview *LinkPreview) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("url = ?", preview.URL).Delete(&LinkPreview{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(preview).Error
+	})
+}
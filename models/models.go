@@ -14,9 +14,33 @@ type BaseModel struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
-// User model will be implemented in Phase 2
-// Assignment model will be implemented in Phase 3
-// Group model will be implemented in Phase 5
-
-// This file serves as a placeholder for the models package
-// Actual models will be added in their respective phases
+// All returns one of every model stored in the database, in migration order.
+// Migrations and test databases both migrate this list, so new models are added here.
+func All() []interface{} {
+	return []interface{}{
+		&User{},
+		&Group{}, &GroupMember{},
+		&Assignment{},
+		&StudentAssignment{},
+		&Notification{},
+		&AssignmentTemplate{},
+		&AssignmentRecurrence{},
+		&ReadingList{}, &ReadingListItem{},
+		&AssignmentResource{}, &StudentResourceProgress{},
+		&LinkPreview{},
+		&LinkCheck{},
+		&AssignmentArchive{},
+		&UploadedFile{},
+		&ReadingNote{},
+		&Quiz{}, &QuizQuestion{}, &QuizAttempt{},
+		&DiscussionPost{}, &DiscussionRevision{},
+		&Annotation{},
+		&PeerReviewSetup{}, &PeerReview{},
+		&Rubric{}, &Grade{}, &CategoryWeight{},
+		&ReadingSession{},
+		&RiskSettings{},
+		&ReadingGoal{},
+		&Achievement{}, &LeaderboardSettings{}, &LeaderboardProfile{},
+		&AuditEvent{},
+	}
+}
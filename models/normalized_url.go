@@ -0,0 +1,58 @@
+package models
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams lists query parameters that only identify where a link was shared
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"ref":     true,
+	"ref_src": true,
+}
+
+// NormalizeURL reduces a reading URL to a form that compares equal for the same page.
+// The scheme, a leading "www.", default ports, trailing slashes, fragments and tracking
+// parameters are ignored and the remaining query parameters are sorted. URLs that cannot
+// be parsed, and paths within the application, are only trimmed.
+func NormalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := parsed.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	path := strings.TrimRight(parsed.EscapedPath(), "/")
+
+	query := parsed.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if trackingParams[lower] || strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+
+	normalized := host + path
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"scheme and www", "http://www.example.com/article", "https://example.com/article"},
+		{"trailing slash", "https://example.com/article/", "https://example.com/article"},
+		{"host case and default port", "https://EXAMPLE.com:443/article", "https://example.com/article"},
+		{"tracking parameters", "https://example.com/article?utm_source=news&id=7&fbclid=abc", "https://example.com/article?id=7"},
+		{"parameter order", "https://example.com/search?b=2&a=1", "https://example.com/search?a=1&b=2"},
+		{"fragment", "https://example.com/article#comments", "https://example.com/article"},
+	}
+
+	for _, tt := range tests {
+		if NormalizeURL(tt.a) != NormalizeURL(tt.b) {
+			t.Errorf("%s: expected %q and %q to match, got %q and %q", tt.name, tt.a, tt.b, NormalizeURL(tt.a), NormalizeURL(tt.b))
+		}
+	}
+
+	different := [][2]string{
+		{"https://example.com/Article", "https://example.com/article"},
+		{"https://example.com/article?id=7", "https://example.com/article?id=8"},
+		{"https://example.com:8080/article", "https://example.com/article"},
+		{"https://blog.example.com/article", "https://example.com/article"},
+	}
+	for _, pair := range different {
+		if NormalizeURL(pair[0]) == NormalizeURL(pair[1]) {
+			t.Errorf("Expected %q and %q to stay different", pair[0], pair[1])
+		}
+	}
+
+	if got := NormalizeURL(" /files/3 "); got != "/files/3" {
+		t.Errorf("Expected application paths to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification represents an in-app message delivered to a user
+type Notification struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	Type         string     `json:"type" gorm:"not null"`
+	Message      string     `json:"message" gorm:"not null"`
+	AssignmentID *uint      `json:"assignment_id"`
+	ReadAt       *time.Time `json:"read_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Notification type constants
+const (
+	NotificationAssignmentReleased = "assignment_released"
+	NotificationLinkProblem        = "link_problem"
+	NotificationPriorCompletion    = "prior_completion"
+	NotificationDiscussionPost     = "discussion_post"
+	NotificationDiscussionReply    = "discussion_reply"
+	NotificationDiscussionAnswer   = "discussion_answer"
+	NotificationPeerReviewAssigned = "peer_review_assigned"
+	NotificationPeerReviewReceived = "peer_review_received"
+	NotificationAtRiskDigest       = "at_risk_digest"
+	NotificationAchievement        = "achievement"
+)
+
+// CreateNotification creates a new notification for a user
+func CreateNotification(db *gorm.DB, userID uint, notificationType, message string, assignmentID *uint) (*Notification, error) {
+	notification := &Notification{
+		UserID:       userID,
+		Type:         notificationType,
+		Message:      message,
+		AssignmentID: assignmentID,
+	}
+
+	result := db.Create(notification)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notification, nil
+}
+
+// GetNotificationsByUser retrieves notifications for a user, newest first
+func GetNotificationsByUser(db *gorm.DB, userID uint, unreadOnly bool) ([]Notification, error) {
+	var notifications []Notification
+	query := db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	result := query.Order("created_at DESC").Find(&notifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead marks a user's notification as read
+func MarkNotificationRead(db *gorm.DB, notificationID, userID uint) error {
+	result := db.Model(&Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PeerReviewSetup configures peer review of the reflections students write on an assignment
+type PeerReviewSetup struct {
+	ID                   uint                  `json:"id" gorm:"primaryKey"`
+	AssignmentID         uint                  `json:"assignment_id" gorm:"uniqueIndex;not null"`
+	ReviewsPerReflection int                   `json:"reviews_per_reflection" gorm:"default:2"`
+	Anonymous            bool                  `json:"anonymous" gorm:"default:false"` // hide reviewer and author names from students
+	DueDate              *time.Time            `json:"due_date"`
+	Rubric               []PeerReviewCriterion `json:"rubric" gorm:"serializer:json"`
+	AllocatedAt          *time.Time            `json:"allocated_at"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+}
+
+// PeerReviewCriterion is one line of a peer review rubric, scored from 0 to MaxScore
+type PeerReviewCriterion struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MaxScore    int    `json:"max_score"`
+}
+
+// PeerReview is one student's review of a classmate's reflection. Scores line up with the
+// criteria of the assignment's rubric.
+type PeerReview struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	AssignmentID        uint       `json:"assignment_id" gorm:"index;not null;uniqueIndex:idx_peer_review_pair"`
+	ReviewerID          uint       `json:"reviewer_id" gorm:"index;not null;uniqueIndex:idx_peer_review_pair"`
+	Reviewer            User       `json:"-" gorm:"foreignKey:ReviewerID"`
+	AuthorID            uint       `json:"author_id" gorm:"not null;uniqueIndex:idx_peer_review_pair"`
+	Author              User       `json:"-" gorm:"foreignKey:AuthorID"`
+	StudentAssignmentID uint       `json:"student_assignment_id" gorm:"index;not null"` // the author's assignment, whose note is reviewed
+	Scores              []int      `json:"scores" gorm:"serializer:json"`
+	Comments            string     `json:"comments" gorm:"type:text"`
+	SubmittedAt         *time.Time `json:"submitted_at"`
+	ReviewerName        string     `json:"reviewer_name,omitempty" gorm:"-"`
+	AuthorName          string     `json:"author_name,omitempty" gorm:"-"`
+	ReflectionHTML      string     `json:"reflection_html,omitempty" gorm:"-"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// IsSubmitted reports whether the reviewer has submitted the review
+func (r *PeerReview) IsSubmitted() bool {
+	return r.SubmittedAt != nil
+}
+
+// GetPeerReviewSetup retrieves the peer review setup of an assignment
+func GetPeerReviewSetup(db *gorm.DB, assignmentID uint) (*PeerReviewSetup, error) {
+	var setup PeerReviewSetup
+	result := db.Where("assignment_id = ?", assignmentID).First(&setup)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &setup, nil
+}
+
+// SavePeerReviewSetup creates or updates a peer review setup
+func SavePeerReviewSetup(db *gorm.DB, setup *PeerReviewSetup) error {
+	result := db.Save(setup)
+	return result.Error
+}
+
+// ReplacePeerReviews discards an assignment's review allocation and stores a new one
+func ReplacePeerReviews(db *gorm.DB, setup *PeerReviewSetup, reviews []PeerReview) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", setup.AssignmentID).Delete(&PeerReview{}).Error; err != nil {
+			return err
+		}
+		if len(reviews) > 0 {
+			if err := tx.Create(&reviews).Error; err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		if err := tx.Model(setup).Update("allocated_at", now).Error; err != nil {
+			return err
+		}
+		setup.AllocatedAt = &now
+		return nil
+	})
+}
+
+// CountSubmittedPeerReviews counts the reviews submitted on an assignment
+func CountSubmittedPeerReviews(db *gorm.DB, assignmentID uint) (int64, error) {
+	var count int64
+	result := db.Model(&PeerReview{}).Where("assignment_id = ? AND submitted_at IS NOT NULL", assignmentID).Count(&count)
+	return count, result.Error
+}
+
+// GetPeerReviewByID retrieves a peer review with its reviewer and author
+func GetPeerReviewByID(db *gorm.DB, reviewID uint) (*PeerReview, error) {
+	var review PeerReview
+	result := db.Preload("Reviewer").Preload("Author").First(&review, reviewID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &review, nil
+}
+
+// GetPeerReviewsByAssignment retrieves every review allocated on an assignment
+func GetPeerReviewsByAssignment(db *gorm.DB, assignmentID uint) ([]PeerReview, error) {
+	var reviews []PeerReview
+	result := db.Preload("Reviewer").Preload("Author").
+		Where("assignment_id = ?", assignmentID).
+		Order("id ASC").
+		Find(&reviews)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reviews, nil
+}
+
+// GetPeerReviewsByReviewer retrieves the reviews a student was asked to write on an assignment
+func GetPeerReviewsByReviewer(db *gorm.DB, assignmentID uint, reviewerID uint) ([]PeerReview, error) {
+	var reviews []PeerReview
+	result := db.Preload("Author").
+		Where("assignment_id = ? AND reviewer_id = ?", assignmentID, reviewerID).
+		Order("id ASC").
+		Find(&reviews)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reviews, nil
+}
+
+// GetSubmittedPeerReviewsByAuthor retrieves the submitted reviews of a student's reflection
+func GetSubmittedPeerReviewsByAuthor(db *gorm.DB, assignmentID uint, authorID uint) ([]PeerReview, error) {
+	var reviews []PeerReview
+	result := db.Preload("Reviewer").
+		Where("assignment_id = ? AND author_id = ? AND submitted_at IS NOT NULL", assignmentID, authorID).
+		Order("submitted_at ASC").
+		Find(&reviews)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reviews, nil
+}
+
+// SubmitPeerReview records a reviewer's scores and comments
+func SubmitPeerReview(db *gorm.DB, review *PeerReview, scores []int, comments string) error {
+	now := time.Now()
+	review.Scores = scores
+	review.Comments = comments
+	review.SubmittedAt = &now
+	result := db.Model(review).Omit(clause.Associations).Select("scores", "comments", "submitted_at").Updates(review)
+	return result.Error
+}
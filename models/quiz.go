@@ -0,0 +1,228 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Quiz question types
+const (
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionTrueFalse      = "true_false"
+	QuestionShortAnswer    = "short_answer"
+)
+
+// ErrQuizNotPassed is returned when an assignment is completed before its quiz has been passed
+var ErrQuizNotPassed = errors.New("quiz not passed")
+
+// Quiz is a short comprehension check attached to an assignment
+type Quiz struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	AssignmentID uint           `json:"assignment_id" gorm:"uniqueIndex;not null"`
+	PassingScore int            `json:"passing_score" gorm:"default:0"` // percent needed to complete the assignment; 0 means the quiz is not required
+	MaxAttempts  int            `json:"max_attempts" gorm:"default:0"`  // 0 allows unlimited attempts
+	Questions    []QuizQuestion `json:"questions" gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// QuizQuestion is one question of a quiz. AcceptedAnswers holds the index of the correct
+// choice for multiple choice, "true" or "false" for true/false, and every acceptable
+// response for short answer questions. It is never shown to students.
+type QuizQuestion struct {
+	ID              uint     `json:"id" gorm:"primaryKey"`
+	QuizID          uint     `json:"quiz_id" gorm:"index;not null"`
+	Position        int      `json:"position"`
+	Type            string   `json:"type" gorm:"not null"`
+	Prompt          string   `json:"prompt" gorm:"type:text;not null"`
+	Choices         []string `json:"choices,omitempty" gorm:"serializer:json"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty" gorm:"serializer:json"`
+	Points          int      `json:"points" gorm:"default:1"`
+}
+
+// QuizAttempt is a student's graded submission of a quiz
+type QuizAttempt struct {
+	ID                  uint            `json:"id" gorm:"primaryKey"`
+	QuizID              uint            `json:"quiz_id" gorm:"index;not null"`
+	StudentAssignmentID uint            `json:"student_assignment_id" gorm:"index;not null"`
+	Answers             map[uint]string `json:"answers" gorm:"serializer:json"` // keyed by question ID
+	Score               int             `json:"score"`
+	MaxScore            int             `json:"max_score"`
+	Percent             int             `json:"percent"`
+	Passed              bool            `json:"passed"`
+	SubmittedAt         time.Time       `json:"submitted_at"`
+}
+
+// QuizResult summarizes a student's attempts on a quiz
+type QuizResult struct {
+	StudentAssignmentID uint `json:"student_assignment_id"`
+	Attempts            int  `json:"attempts"`
+	BestPercent         int  `json:"best_percent"`
+	Passed              bool `json:"passed"`
+}
+
+// HideAnswers removes the accepted answers so the quiz can be shown to students
+func (q *Quiz) HideAnswers() {
+	for i := range q.Questions {
+		q.Questions[i].AcceptedAnswers = nil
+	}
+}
+
+// IsCorrect reports whether a student's answer is accepted for the question. Short
+// answers are compared without regard to case or spacing.
+func (qq *QuizQuestion) IsCorrect(answer string) bool {
+	answer = normalizeQuizAnswer(answer)
+	if answer == "" {
+		return false
+	}
+	for _, accepted := range qq.AcceptedAnswers {
+		if normalizeQuizAnswer(accepted) == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeQuizAnswer lowercases an answer and collapses its whitespace
+func normalizeQuizAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+// Grade scores a set of answers against the quiz
+func (q *Quiz) Grade(answers map[uint]string) *QuizAttempt {
+	attempt := &QuizAttempt{QuizID: q.ID, Answers: answers}
+	for _, question := range q.Questions {
+		attempt.MaxScore += question.Points
+		if question.IsCorrect(answers[question.ID]) {
+			attempt.Score += question.Points
+		}
+	}
+	if attempt.MaxScore > 0 {
+		attempt.Percent = attempt.Score * 100 / attempt.MaxScore
+	}
+	attempt.Passed = attempt.Percent >= q.PassingScore
+	return attempt
+}
+
+// GetQuizByAssignment retrieves the quiz of an assignment with its questions in order
+func GetQuizByAssignment(db *gorm.DB, assignmentID uint) (*Quiz, error) {
+	var quiz Quiz
+	result := db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC, id ASC")
+	}).Where("assignment_id = ?", assignmentID).First(&quiz)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &quiz, nil
+}
+
+// SaveQuiz creates or replaces the quiz of an assignment. Existing questions are replaced
+// while earlier attempts keep the scores they were given.
+func SaveQuiz(db *gorm.DB, quiz *Quiz) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing Quiz
+		err := tx.Where("assignment_id = ?", quiz.AssignmentID).First(&existing).Error
+		switch {
+		case err == nil:
+			quiz.ID = existing.ID
+			quiz.CreatedAt = existing.CreatedAt
+			if err := tx.Where("quiz_id = ?", existing.ID).Delete(&QuizQuestion{}).Error; err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		for i := range quiz.Questions {
+			quiz.Questions[i].ID = 0
+			quiz.Questions[i].Position = i + 1
+		}
+		return tx.Save(quiz).Error
+	})
+}
+
+// DeleteQuiz removes the quiz of an assignment along with its questions and attempts
+func DeleteQuiz(db *gorm.DB, quiz *Quiz) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&QuizAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("quiz_id = ?", quiz.ID).Delete(&QuizQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(quiz).Error
+	})
+}
+
+// CreateQuizAttempt stores a graded attempt
+func CreateQuizAttempt(db *gorm.DB, attempt *QuizAttempt) error {
+	if attempt.SubmittedAt.IsZero() {
+		attempt.SubmittedAt = time.Now()
+	}
+	result := db.Create(attempt)
+	return result.Error
+}
+
+// GetQuizAttempts retrieves a student's attempts on a quiz, oldest first
+func GetQuizAttempts(db *gorm.DB, quizID uint, studentAssignmentID uint) ([]QuizAttempt, error) {
+	var attempts []QuizAttempt
+	result := db.Where("quiz_id = ? AND student_assignment_id = ?", quizID, studentAssignmentID).
+		Order("submitted_at ASC, id ASC").
+		Find(&attempts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return attempts, nil
+}
+
+// GetQuizResultsByAssignment summarizes every student's attempts on an assignment's quiz
+func GetQuizResultsByAssignment(db *gorm.DB, assignmentID uint) ([]QuizResult, error) {
+	var results []QuizResult
+	result := db.Table("quiz_attempts").
+		Select("quiz_attempts.student_assignment_id, COUNT(*) AS attempts, MAX(quiz_attempts.percent) AS best_percent, MAX(quiz_attempts.passed) AS passed").
+		Joins("JOIN quizzes ON quizzes.id = quiz_attempts.quiz_id").
+		Where("quizzes.assignment_id = ?", assignmentID).
+		Group("quiz_attempts.student_assignment_id").
+		Scan(&results)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+// checkQuiz ensures the student passed the assignment's quiz when its instructor requires it
+func (sa *StudentAssignment) checkQuiz(db *gorm.DB) error {
+	quiz, err := GetQuizByAssignment(db, sa.AssignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if quiz.PassingScore <= 0 {
+		return nil
+	}
+
+	var passed int64
+	err = db.Model(&QuizAttempt{}).
+		Where("quiz_id = ? AND student_assignment_id = ? AND passed = ?", quiz.ID, sa.ID, true).
+		Count(&passed).Error
+	if err != nil {
+		return err
+	}
+
+	if passed == 0 {
+		return fmt.Errorf("%w: score at least %d%% on the quiz before completing this reading", ErrQuizNotPassed, quiz.PassingScore)
+	}
+	return nil
+}
+
+// IsCompletionRequirementError reports whether err means the student still has work to do
+// (resources to finish, a reflection to write or a quiz to pass) before the assignment can be completed
+func IsCompletionRequirementError(err error) bool {
+	return errors.Is(err, ErrResourcesIncomplete) || errors.Is(err, ErrReflectionRequired) || errors.Is(err, ErrQuizNotPassed)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReadingGoal is the number of readings a student aims to complete each week
+type ReadingGoal struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	StudentID         uint      `json:"student_id" gorm:"not null;uniqueIndex"`
+	WeeklyCompletions int       `json:"weekly_completions"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GetReadingGoal retrieves a student's weekly goal
+func GetReadingGoal(db *gorm.DB, studentID uint) (*ReadingGoal, error) {
+	var goal ReadingGoal
+	result := db.Where("student_id = ?", studentID).First(&goal)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &goal, nil
+}
+
+// SetReadingGoal creates or updates a student's weekly goal
+func SetReadingGoal(db *gorm.DB, studentID uint, weeklyCompletions int) (*ReadingGoal, error) {
+	goal, err := GetReadingGoal(db, studentID)
+	if err != nil {
+		goal = &ReadingGoal{StudentID: studentID}
+	}
+	goal.WeeklyCompletions = weeklyCompletions
+	if err := db.Save(goal).Error; err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// DeleteReadingGoal removes a student's weekly goal
+func DeleteReadingGoal(db *gorm.DB, studentID uint) error {
+	return db.Where("student_id = ?", studentID).Delete(&ReadingGoal{}).Error
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReadingList is an ordered path of assignments that students work through in sequence
+type ReadingList struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Title       string            `json:"title" gorm:"not null"`
+	Description string            `json:"description"`
+	Sequential  bool              `json:"sequential"` // items without explicit prerequisites require the previous item
+	CreatedByID uint              `json:"created_by_id" gorm:"not null;index"`
+	CreatedBy   User              `json:"created_by" gorm:"foreignKey:CreatedByID"`
+	Items       []ReadingListItem `json:"items" gorm:"foreignKey:ReadingListID"`
+	Students    []User            `json:"-" gorm:"many2many:reading_list_students"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"deleted_at" gorm:"index"`
+}
+
+// ReadingListItem places an assignment at a position within a reading list
+type ReadingListItem struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	ReadingListID uint              `json:"reading_list_id" gorm:"not null;index"`
+	AssignmentID  uint              `json:"assignment_id" gorm:"not null;index"`
+	Assignment    Assignment        `json:"assignment" gorm:"foreignKey:AssignmentID"`
+	Position      int               `json:"position" gorm:"not null"`
+	Prerequisites []ReadingListItem `json:"prerequisites,omitempty" gorm:"many2many:reading_list_item_prerequisites;joinForeignKey:ItemID;joinReferences:PrerequisiteID"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// CreateReadingList creates a reading list together with its items
+func CreateReadingList(db *gorm.DB, list *ReadingList) error {
+	return db.Create(list).Error
+}
+
+// preloadReadingList loads items in order with their assignments and prerequisites
+func preloadReadingList(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("reading_list_items.position ASC")
+	}).Preload("Items.Assignment").Preload("Items.Prerequisites")
+}
+
+// GetReadingListByID retrieves a reading list with its ordered items
+func GetReadingListByID(db *gorm.DB, id uint) (*ReadingList, error) {
+	var list ReadingList
+	result := preloadReadingList(db).First(&list, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &list, nil
+}
+
+// GetReadingListsByInstructor retrieves all reading lists created by an instructor
+func GetReadingListsByInstructor(db *gorm.DB, instructorID uint) ([]ReadingList, error) {
+	var lists []ReadingList
+	result := preloadReadingList(db).Where("created_by_id = ?", instructorID).Order("title ASC").Find(&lists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return lists, nil
+}
+
+// GetReadingListsByStudent retrieves all reading lists assigned to a student
+func GetReadingListsByStudent(db *gorm.DB, studentID uint) ([]ReadingList, error) {
+	var lists []ReadingList
+	result := preloadReadingList(db).
+		Joins("JOIN reading_list_students ON reading_list_students.reading_list_id = reading_lists.id").
+		Where("reading_list_students.user_id = ?", studentID).
+		Order("reading_lists.title ASC").
+		Find(&lists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return lists, nil
+}
+
+// GetReadingListsContainingAssignment retrieves the student's reading lists that include an assignment
+func GetReadingListsContainingAssignment(db *gorm.DB, assignmentID, studentID uint) ([]ReadingList, error) {
+	var lists []ReadingList
+	result := preloadReadingList(db).
+		Joins("JOIN reading_list_students ON reading_list_students.reading_list_id = reading_lists.id").
+		Where("reading_list_students.user_id = ?", studentID).
+		Where("reading_lists.id IN (?)", db.Model(&ReadingListItem{}).Select("reading_list_id").Where("assignment_id = ?", assignmentID)).
+		Find(&lists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return lists, nil
+}
+
+// AddStudents assigns the reading list to students, ignoring those already assigned
+func (l *ReadingList) AddStudents(db *gorm.DB, students []User) error {
+	return db.Model(l).Association("Students").Append(students)
+}
+
+// Delete soft deletes a reading list; its assignments are left untouched
+func (l *ReadingList) Delete(db *gorm.DB) error {
+	return db.Delete(l).Error
+}
+
+// ItemPrerequisites returns the items that must be completed before item can be started
+func (l *ReadingList) ItemPrerequisites(index int) []ReadingListItem {
+	item := l.Items[index]
+	if len(item.Prerequisites) > 0 {
+		return item.Prerequisites
+	}
+	if l.Sequential && index > 0 {
+		return []ReadingListItem{l.Items[index-1]}
+	}
+	return nil
+}
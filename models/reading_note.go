@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrReflectionRequired is returned when an assignment is completed without the reflection its instructor requires
+var ErrReflectionRequired = errors.New("reflection required")
+
+// ReadingNote holds a student's notes and reflection on an assignment
+type ReadingNote struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	StudentAssignmentID uint      `json:"student_assignment_id" gorm:"uniqueIndex;not null"`
+	Content             string    `json:"content" gorm:"type:text"`      // Markdown as written by the student
+	ContentHTML         string    `json:"content_html" gorm:"type:text"` // sanitized rendering of Content
+	WordCount           int       `json:"word_count"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// ReflectionEntry is a student's reflection as instructors read it
+type ReflectionEntry struct {
+	StudentAssignmentID uint       `json:"student_assignment_id"`
+	StudentID           uint       `json:"student_id"`
+	StudentName         string     `json:"student_name"`
+	Status              string     `json:"status"`
+	CompletedAt         *time.Time `json:"completed_at"`
+	Content             string     `json:"content"`
+	ContentHTML         string     `json:"content_html"`
+	WordCount           int        `json:"word_count"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// CountWords counts the whitespace-separated words of a note
+func CountWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+// GetReadingNote retrieves the note of a student assignment
+func GetReadingNote(db *gorm.DB, studentAssignmentID uint) (*ReadingNote, error) {
+	var note ReadingNote
+	result := db.Where("student_assignment_id = ?", studentAssignmentID).First(&note)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &note, nil
+}
+
+// SaveReadingNote creates or updates a note
+func SaveReadingNote(db *gorm.DB, note *ReadingNote) error {
+	result := db.Save(note)
+	return result.Error
+}
+
+// GetReflectionsByAssignment retrieves the notes students wrote on an assignment, by student name
+func GetReflectionsByAssignment(db *gorm.DB, assignmentID uint) ([]ReflectionEntry, error) {
+	var entries []ReflectionEntry
+	result := db.Table("reading_notes").
+		Select("reading_notes.student_assignment_id, student_assignments.student_id, users.username AS student_name, student_assignments.status, student_assignments.completed_at, reading_notes.content, reading_notes.content_html, reading_notes.word_count, reading_notes.updated_at").
+		Joins("JOIN student_assignments ON student_assignments.id = reading_notes.student_assignment_id").
+		Joins("JOIN users ON users.id = student_assignments.student_id").
+		Where("student_assignments.assignment_id = ? AND student_assignments.deleted_at IS NULL", assignmentID).
+		Order("users.username ASC").
+		Scan(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+// checkReflection ensures the student wrote the reflection the assignment requires
+func (sa *StudentAssignment) checkReflection(db *gorm.DB) error {
+	required := sa.Assignment.MinReflectionWords
+	if required <= 0 {
+		return nil
+	}
+
+	words := 0
+	note, err := GetReadingNote(db, sa.ID)
+	if err == nil {
+		words = note.WordCount
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if words < required {
+		return fmt.Errorf("%w: write at least %d words about this reading before completing it (you have %d)", ErrReflectionRequired, required, words)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reading session sources
+const (
+	ReadingSourceTimer   = "timer"   // the student started the reading timer
+	ReadingSourceLink    = "link"    // the student opened the reading from its assignment page
+	ReadingSourceArchive = "archive" // the student is reading the archived copy
+)
+
+// ReadingSession is a stretch of time a student spent on a reading. The page tracking the
+// session sends heartbeats while the student reads; only the time between heartbeats that
+// arrive close together counts as active, so a tab left open overnight adds nothing.
+type ReadingSession struct {
+	ID                  uint       `json:"id" gorm:"primaryKey"`
+	StudentAssignmentID uint       `json:"student_assignment_id" gorm:"index;not null"`
+	StudentID           uint       `json:"student_id" gorm:"index;not null"`
+	Source              string     `json:"source" gorm:"not null"`
+	StartedAt           time.Time  `json:"started_at"`
+	LastSeenAt          time.Time  `json:"last_seen_at"` // time of the latest heartbeat
+	EndedAt             *time.Time `json:"ended_at"`
+	ActiveSeconds       int        `json:"active_seconds"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ReadingTime is the active reading time recorded on a student assignment
+type ReadingTime struct {
+	StudentAssignmentID uint `json:"student_assignment_id"`
+	ActiveSeconds       int  `json:"active_seconds"`
+	Sessions            int  `json:"sessions"`
+}
+
+// ActiveMinutes rounds the active reading time to whole minutes
+func (rt ReadingTime) ActiveMinutes() int {
+	return (rt.ActiveSeconds + 30) / 60
+}
+
+// IsOpen checks if the session is still waiting for heartbeats
+func (rs *ReadingSession) IsOpen() bool {
+	return rs.EndedAt == nil
+}
+
+// CreateReadingSession stores a new reading session
+func CreateReadingSession(db *gorm.DB, session *ReadingSession) error {
+	result := db.Create(session)
+	return result.Error
+}
+
+// GetReadingSessionByID retrieves a reading session by ID
+func GetReadingSessionByID(db *gorm.DB, sessionID uint) (*ReadingSession, error) {
+	var session ReadingSession
+	result := db.First(&session, sessionID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// GetOpenReadingSessions retrieves the sessions on a student assignment that have not ended
+func GetOpenReadingSessions(db *gorm.DB, studentAssignmentID uint) ([]ReadingSession, error) {
+	var sessions []ReadingSession
+	result := db.Where("student_assignment_id = ? AND ended_at IS NULL", studentAssignmentID).Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// SaveReadingSession records a session's heartbeat and end
+func SaveReadingSession(db *gorm.DB, session *ReadingSession) error {
+	result := db.Model(session).Select("last_seen_at", "ended_at", "active_seconds").Updates(session)
+	return result.Error
+}
+
+// GetReadingTime totals the active reading time of a student assignment
+func GetReadingTime(db *gorm.DB, studentAssignmentID uint) (ReadingTime, error) {
+	readingTime := ReadingTime{StudentAssignmentID: studentAssignmentID}
+	result := db.Model(&ReadingSession{}).
+		Select("COALESCE(SUM(active_seconds), 0) AS active_seconds, COUNT(*) AS sessions").
+		Where("student_assignment_id = ?", studentAssignmentID).
+		Scan(&readingTime)
+	return readingTime, result.Error
+}
+
+// GetReadingTimesByAssignment totals the active reading time of each student on an assignment, keyed by student assignment ID
+func GetReadingTimesByAssignment(db *gorm.DB, assignmentID uint) (map[uint]ReadingTime, error) {
+	return scanReadingTimes(db.Model(&ReadingSession{}).
+		Joins("JOIN student_assignments ON student_assignments.id = reading_sessions.student_assignment_id").
+		Where("student_assignments.assignment_id = ?", assignmentID))
+}
+
+// GetReadingTimesByInstructor totals the active reading time of each student assignment on an instructor's assignments, keyed by student assignment ID
+func GetReadingTimesByInstructor(db *gorm.DB, instructorID uint) (map[uint]ReadingTime, error) {
+	return scanReadingTimes(db.Model(&ReadingSession{}).
+		Joins("JOIN student_assignments ON student_assignments.id = reading_sessions.student_assignment_id").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.created_by_id = ?", instructorID))
+}
+
+// scanReadingTimes groups the sessions matched by a query by student assignment
+func scanReadingTimes(query *gorm.DB) (map[uint]ReadingTime, error) {
+	var rows []ReadingTime
+	result := query.
+		Select("reading_sessions.student_assignment_id, SUM(reading_sessions.active_seconds) AS active_seconds, COUNT(*) AS sessions").
+		Group("reading_sessions.student_assignment_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	times := make(map[uint]ReadingTime, len(rows))
+	for _, row := range rows {
+		times[row.StudentAssignmentID] = row
+	}
+	return times, nil
+}
+
+// GetReadingSessionsByStudent retrieves a student's reading sessions started since the given time
+func GetReadingSessionsByStudent(db *gorm.DB, studentID uint, since time.Time) ([]ReadingSession, error) {
+	var sessions []ReadingSession
+	result := db.Where("student_id = ? AND started_at >= ?", studentID, since).Order("started_at ASC").Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RiskSettings holds the weights an instructor gives each at-risk factor and whether they
+// want the weekly digest of students who need attention
+type RiskSettings struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	InstructorID     uint       `json:"instructor_id" gorm:"not null;uniqueIndex"`
+	OverdueWeight    int        `json:"overdue_weight"`
+	NotStartedWeight int        `json:"not_started_weight"`
+	TrendWeight      int        `json:"trend_weight"`
+	InactivityWeight int        `json:"inactivity_weight"`
+	WeeklyDigest     bool       `json:"weekly_digest" gorm:"default:false"`
+	LastDigestAt     *time.Time `json:"last_digest_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// DefaultRiskSettings returns the weights used until an instructor picks their own
+func DefaultRiskSettings(instructorID uint) RiskSettings {
+	return RiskSettings{
+		InstructorID:     instructorID,
+		OverdueWeight:    35,
+		NotStartedWeight: 25,
+		TrendWeight:      20,
+		InactivityWeight: 20,
+	}
+}
+
+// TotalWeight sums the factor weights
+func (rs *RiskSettings) TotalWeight() int {
+	return rs.OverdueWeight + rs.NotStartedWeight + rs.TrendWeight + rs.InactivityWeight
+}
+
+// GetRiskSettings retrieves an instructor's risk settings
+func GetRiskSettings(db *gorm.DB, instructorID uint) (*RiskSettings, error) {
+	var settings RiskSettings
+	result := db.Where("instructor_id = ?", instructorID).First(&settings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &settings, nil
+}
+
+// SaveRiskSettings creates or updates an instructor's risk settings
+func SaveRiskSettings(db *gorm.DB, settings *RiskSettings) error {
+	result := db.Save(settings)
+	return result.Error
+}
+
+// GetRiskDigestsDue retrieves the settings of instructors whose weekly digest has not been sent since the given time
+func GetRiskDigestsDue(db *gorm.DB, sentBefore time.Time) ([]RiskSettings, error) {
+	var settings []RiskSettings
+	result := db.Where("weekly_digest = ? AND (last_digest_at IS NULL OR last_digest_at <= ?)", true, sentBefore).
+		Order("instructor_id ASC").
+		Find(&settings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return settings, nil
+}
+
+// MarkRiskDigestSent records when an instructor's digest went out
+func (rs *RiskSettings) MarkRiskDigestSent(db *gorm.DB, at time.Time) error {
+	rs.LastDigestAt = &at
+	return db.Model(rs).Update("last_digest_at", at).Error
+}
+
+// GetLastReadingActivity retrieves when each student last sent a reading heartbeat on an instructor's assignments, keyed by student ID
+func GetLastReadingActivity(db *gorm.DB, instructorID uint) (map[uint]time.Time, error) {
+	var rows []struct {
+		StudentID  uint
+		LastSeenAt time.Time
+	}
+	result := db.Model(&ReadingSession{}).
+		Select("reading_sessions.student_id, reading_sessions.last_seen_at").
+		Joins("JOIN student_assignments ON student_assignments.id = reading_sessions.student_assignment_id").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.created_by_id = ?", instructorID).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	latest := make(map[uint]time.Time)
+	for _, row := range rows {
+		if row.LastSeenAt.After(latest[row.StudentID]) {
+			latest[row.StudentID] = row.LastSeenAt
+		}
+	}
+	return latest, nil
+}
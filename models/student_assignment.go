@@ -8,16 +8,24 @@ import (
 
 // StudentAssignment represents the relationship between a student and an assignment
 type StudentAssignment struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	AssignmentID uint           `json:"assignment_id" gorm:"not null"`
-	Assignment   Assignment     `json:"assignment" gorm:"foreignKey:AssignmentID"`
-	StudentID    uint           `json:"student_id" gorm:"not null"`
-	Student      User           `json:"student" gorm:"foreignKey:StudentID"`
-	Status       string         `json:"status" gorm:"default:assigned"` // assigned, in_progress, completed
-	CompletedAt  *time.Time     `json:"completed_at"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	AssignmentID     uint           `json:"assignment_id" gorm:"not null"`
+	Assignment       Assignment     `json:"assignment" gorm:"foreignKey:AssignmentID"`
+	StudentID        uint           `json:"student_id" gorm:"not null"`
+	Student          User           `json:"student" gorm:"foreignKey:StudentID"`
+	Status           string         `json:"status" gorm:"default:assigned"` // assigned, in_progress, completed
+	CompletedAt      *time.Time     `json:"completed_at"`
+	DueDateOverride  *time.Time     `json:"due_date_override"` // per-student extension, replaces the assignment due date
+	Excused          bool           `json:"excused" gorm:"default:false"`
+	CompletionTiming string         `json:"completion_timing"` // on_time, late, excused; set on completion
+	LinkToken        *string        `json:"-" gorm:"uniqueIndex"`
+	TrackedLink      string         `json:"tracked_url,omitempty" gorm:"-"` // only filled in for the student's own view
+	LinkOpenCount    int            `json:"link_open_count" gorm:"default:0"`
+	FirstOpenedAt    *time.Time     `json:"first_opened_at"`
+	LastOpenedAt     *time.Time     `json:"last_opened_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 // Assignment status constants
@@ -27,12 +35,29 @@ const (
 	StatusCompleted  = "completed"
 )
 
+// Completion timing constants
+const (
+	TimingOnTime  = "on_time"
+	TimingLate    = "late"
+	TimingExcused = "excused"
+)
+
+// EffectiveDueDateSQL selects the per-student due date, falling back to the assignment due date.
+// Both student_assignments and assignments must be part of the query.
+const EffectiveDueDateSQL = "COALESCE(student_assignments.due_date_override, assignments.due_date)"
+
 // CreateStudentAssignment creates a new student assignment
 func CreateStudentAssignment(db *gorm.DB, assignmentID, studentID uint) (*StudentAssignment, error) {
+	token, err := NewLinkToken()
+	if err != nil {
+		return nil, err
+	}
+
 	studentAssignment := &StudentAssignment{
 		AssignmentID: assignmentID,
 		StudentID:    studentID,
 		Status:       StatusAssigned,
+		LinkToken:    &token,
 	}
 
 	result := db.Create(studentAssignment)
@@ -63,8 +88,22 @@ func GetStudentAssignmentByID(db *gorm.DB, studentAssignmentID, studentID uint)
 	return &studentAssignment, nil
 }
 
-// GetStudentAssignmentsByStudent retrieves all assignments for a specific student
+// GetStudentAssignmentsByStudent retrieves all published assignments for a specific student
 func GetStudentAssignmentsByStudent(db *gorm.DB, studentID uint) ([]StudentAssignment, error) {
+	var studentAssignments []StudentAssignment
+	result := db.Preload("Assignment").Preload("Assignment.CreatedBy").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND student_assignments.deleted_at IS NULL", studentID).
+		Find(&studentAssignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return studentAssignments, nil
+}
+
+// GetAllStudentAssignmentsByStudent retrieves all assignments for a specific student, including scheduled ones
+func GetAllStudentAssignmentsByStudent(db *gorm.DB, studentID uint) ([]StudentAssignment, error) {
 	var studentAssignments []StudentAssignment
 	result := db.Preload("Assignment").Preload("Assignment.CreatedBy").Where("student_id = ? AND deleted_at IS NULL", studentID).Find(&studentAssignments)
 	if result.Error != nil {
@@ -83,16 +122,113 @@ func GetStudentAssignmentsByAssignment(db *gorm.DB, assignmentID uint) ([]Studen
 	return studentAssignments, nil
 }
 
+// GetStudentAssignmentsByAssignments retrieves the student assignments of several assignments with their students
+func GetStudentAssignmentsByAssignments(db *gorm.DB, assignmentIDs []uint) ([]StudentAssignment, error) {
+	var studentAssignments []StudentAssignment
+	if len(assignmentIDs) == 0 {
+		return studentAssignments, nil
+	}
+	result := db.Preload("Student").Where("assignment_id IN ?", assignmentIDs).Find(&studentAssignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return studentAssignments, nil
+}
+
+// GetStudentAssignmentStatuses retrieves the status and dates of several assignments' student assignments,
+// without the other columns or any preloads, for totals over many assignments
+func GetStudentAssignmentStatuses(db *gorm.DB, assignmentIDs []uint) ([]StudentAssignment, error) {
+	return getStudentAssignmentStatuses(db, assignmentIDs)
+}
+
+// GetUnfinishedStudentAssignmentStatuses is GetStudentAssignmentStatuses for the readings not completed yet
+func GetUnfinishedStudentAssignmentStatuses(db *gorm.DB, assignmentIDs []uint) ([]StudentAssignment, error) {
+	return getStudentAssignmentStatuses(db.Where("status <> ?", StatusCompleted), assignmentIDs)
+}
+
+func getStudentAssignmentStatuses(db *gorm.DB, assignmentIDs []uint) ([]StudentAssignment, error) {
+	var studentAssignments []StudentAssignment
+	if len(assignmentIDs) == 0 {
+		return studentAssignments, nil
+	}
+	result := db.Select("id", "assignment_id", "student_id", "status", "completed_at", "due_date_override", "excused", "created_at").
+		Where("assignment_id IN ?", assignmentIDs).
+		Find(&studentAssignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return studentAssignments, nil
+}
+
+// AssignmentCompletionCount is how many students an assignment has and how many of them have not completed it
+type AssignmentCompletionCount struct {
+	AssignmentID uint
+	Total        int
+	Incomplete   int
+}
+
+// GetAssignmentCompletionCounts counts the students and incomplete readings of several assignments, keyed by assignment ID
+func GetAssignmentCompletionCounts(db *gorm.DB, assignmentIDs []uint) (map[uint]AssignmentCompletionCount, error) {
+	counts := make(map[uint]AssignmentCompletionCount, len(assignmentIDs))
+	if len(assignmentIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []AssignmentCompletionCount
+	err := db.Model(&StudentAssignment{}).
+		Select("assignment_id, COUNT(*) as total, SUM(CASE WHEN status <> ? THEN 1 ELSE 0 END) as incomplete", StatusCompleted).
+		Where("assignment_id IN ?", assignmentIDs).
+		Group("assignment_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		counts[result.AssignmentID] = result
+	}
+	return counts, nil
+}
+
+// GetStudentAssignmentsByInstructor retrieves every student assignment on an instructor's assignments with the assignment and student
+func GetStudentAssignmentsByInstructor(db *gorm.DB, instructorID uint) ([]StudentAssignment, error) {
+	var studentAssignments []StudentAssignment
+	result := db.Preload("Assignment").Preload("Student").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.created_by_id = ? AND assignments.deleted_at IS NULL", instructorID).
+		Find(&studentAssignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return studentAssignments, nil
+}
+
 // UpdateStatus updates the status of a student assignment
 func (sa *StudentAssignment) UpdateStatus(db *gorm.DB, status string) error {
 	updates := map[string]interface{}{
 		"status": status,
 	}
 
-	// If marking as completed, set completed_at timestamp
+	// If marking as completed, set completed_at timestamp and record timeliness
 	if status == StatusCompleted {
+		if err := sa.loadAssignment(db); err != nil {
+			return err
+		}
+		if err := sa.checkResources(db); err != nil {
+			return err
+		}
+		if err := sa.checkReflection(db); err != nil {
+			return err
+		}
+		if err := sa.checkQuiz(db); err != nil {
+			return err
+		}
 		now := time.Now()
 		updates["completed_at"] = &now
+		updates["completion_timing"] = sa.timingAt(now)
+	} else {
+		// Reopening a reading takes back its completion, so it no longer counts as on time or late
+		updates["completed_at"] = nil
+		updates["completion_timing"] = ""
 	}
 
 	result := db.Model(sa).Updates(updates)
@@ -114,18 +250,89 @@ func (sa *StudentAssignment) IsCompleted() bool {
 	return sa.Status == StatusCompleted
 }
 
+// EffectiveDueDate returns the student's due date, honoring any extension
+func (sa *StudentAssignment) EffectiveDueDate() *time.Time {
+	if sa.DueDateOverride != nil {
+		return sa.DueDateOverride
+	}
+	return sa.Assignment.DueDate
+}
+
+// IsPastDue checks if the given time is past the effective due date plus the assignment grace period
+func (sa *StudentAssignment) IsPastDue(at time.Time) bool {
+	dueDate := sa.EffectiveDueDate()
+	if dueDate == nil || sa.Excused {
+		return false
+	}
+	return at.After(dueDate.Add(sa.Assignment.GracePeriod()))
+}
+
 // IsOverdue checks if the assignment is overdue
 func (sa *StudentAssignment) IsOverdue() bool {
-	if sa.Assignment.DueDate == nil {
-		return false
+	return sa.IsPastDue(time.Now()) && !sa.IsCompleted()
+}
+
+// timingAt classifies a completion at the given time as on time, late or excused
+func (sa *StudentAssignment) timingAt(completedAt time.Time) string {
+	if sa.Excused {
+		return TimingExcused
+	}
+	if sa.IsPastDue(completedAt) {
+		return TimingLate
+	}
+	return TimingOnTime
+}
+
+// loadAssignment loads the parent assignment if it was not preloaded
+func (sa *StudentAssignment) loadAssignment(db *gorm.DB) error {
+	if sa.Assignment.ID != 0 {
+		return nil
+	}
+	return db.First(&sa.Assignment, sa.AssignmentID).Error
+}
+
+// SetDueDateOverride grants (or with nil, revokes) a per-student due date extension
+func (sa *StudentAssignment) SetDueDateOverride(db *gorm.DB, dueDate *time.Time) error {
+	if err := sa.loadAssignment(db); err != nil {
+		return err
 	}
-	return time.Now().After(*sa.Assignment.DueDate) && !sa.IsCompleted()
+	sa.DueDateOverride = dueDate
+	return sa.saveLatePolicy(db)
+}
+
+// SetExcused marks the student as excused from the due date
+func (sa *StudentAssignment) SetExcused(db *gorm.DB, excused bool) error {
+	if err := sa.loadAssignment(db); err != nil {
+		return err
+	}
+	sa.Excused = excused
+	return sa.saveLatePolicy(db)
+}
+
+// saveLatePolicy persists the override and excused flag, reclassifying any existing completion
+func (sa *StudentAssignment) saveLatePolicy(db *gorm.DB) error {
+	updates := map[string]interface{}{
+		"due_date_override": sa.DueDateOverride,
+		"excused":           sa.Excused,
+	}
+
+	if sa.CompletedAt != nil {
+		sa.CompletionTiming = sa.timingAt(*sa.CompletedAt)
+		updates["completion_timing"] = sa.CompletionTiming
+	}
+
+	result := db.Model(sa).Updates(updates)
+	return result.Error
 }
 
 // GetStudentAssignmentsByStatus retrieves student assignments by status
 func GetStudentAssignmentsByStatus(db *gorm.DB, studentID uint, status string) ([]StudentAssignment, error) {
 	var studentAssignments []StudentAssignment
-	result := db.Preload("Assignment").Preload("Assignment.CreatedBy").Where("student_id = ? AND status = ?", studentID, status).Find(&studentAssignments)
+	result := db.Preload("Assignment").Preload("Assignment.CreatedBy").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND student_assignments.status = ?", studentID, status).
+		Find(&studentAssignments)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -135,15 +342,42 @@ func GetStudentAssignmentsByStatus(db *gorm.DB, studentID uint, status string) (
 // GetOverdueAssignments retrieves overdue assignments for a student
 func GetOverdueAssignments(db *gorm.DB, studentID uint) ([]StudentAssignment, error) {
 	var studentAssignments []StudentAssignment
+	now := time.Now()
 	result := db.Preload("Assignment").Preload("Assignment.CreatedBy").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
-		Where("student_assignments.student_id = ? AND assignments.due_date < ? AND student_assignments.status != ?",
-			studentID, time.Now(), StatusCompleted).
+		Scopes(PublishedAt(now)).
+		Where("student_assignments.student_id = ? AND "+EffectiveDueDateSQL+" < ? AND student_assignments.status != ? AND student_assignments.excused = ?",
+			studentID, now, StatusCompleted, false).
 		Find(&studentAssignments)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return studentAssignments, nil
+
+	// Grace periods are applied in Go so the query stays portable across databases
+	overdue := studentAssignments[:0]
+	for _, sa := range studentAssignments {
+		if sa.IsPastDue(now) {
+			overdue = append(overdue, sa)
+		}
+	}
+	return overdue, nil
+}
+
+// GetReadingWorkload sums the estimated reading minutes of a student's unfinished
+// assignments due within the given window
+func GetReadingWorkload(db *gorm.DB, studentID uint, from, to time.Time) (int, error) {
+	var minutes int
+	result := db.Model(&StudentAssignment{}).
+		Select("COALESCE(SUM(assignments.estimated_minutes), 0)").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND "+EffectiveDueDateSQL+" >= ? AND "+EffectiveDueDateSQL+" <= ? AND student_assignments.status != ? AND student_assignments.excused = ?",
+			studentID, from, to, StatusCompleted, false).
+		Scan(&minutes)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return minutes, nil
 }
 
 // GetAssignmentProgress calculates the completion progress for an assignment
@@ -176,15 +410,51 @@ func GetAssignmentProgress(db *gorm.DB, assignmentID uint) (map[string]int, erro
 	return progress, nil
 }
 
+// GetInstructorStatusCounts counts the student assignments in each status across all of an instructor's assignments
+func GetInstructorStatusCounts(db *gorm.DB, instructorID uint) (map[string]int, error) {
+	var results []struct {
+		Status string
+		Count  int
+	}
+
+	err := db.Model(&StudentAssignment{}).
+		Select("student_assignments.status, COUNT(*) as count").
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Where("assignments.created_by_id = ? AND assignments.deleted_at IS NULL", instructorID).
+		Group("student_assignments.status").
+		Scan(&results).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{
+		StatusAssigned:   0,
+		StatusInProgress: 0,
+		StatusCompleted:  0,
+	}
+
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+
+	return counts, nil
+}
+
 // BulkCreateStudentAssignments creates multiple student assignments at once
 func BulkCreateStudentAssignments(db *gorm.DB, assignmentID uint, studentIDs []uint) error {
 	var studentAssignments []StudentAssignment
 
 	for _, studentID := range studentIDs {
+		token, err := NewLinkToken()
+		if err != nil {
+			return err
+		}
 		studentAssignments = append(studentAssignments, StudentAssignment{
 			AssignmentID: assignmentID,
 			StudentID:    studentID,
 			Status:       StatusAssigned,
+			LinkToken:    &token,
 		})
 	}
 
@@ -401,3 +401,114 @@ func TestRemoveStudentAssignment(t *testing.T) {
 		t.Error("Expected error when getting removed student assignment")
 	}
 }
+
+func TestGetStudentAssignmentsByStudentHidesScheduled(t *testing.T) {
+	db := setupTestDB(t)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	future := time.Now().Add(24 * time.Hour)
+	published, _ := CreateAssignment(db, "Published", "", "https://example.com/1", "reading", nil, instructor.ID)
+	scheduled, _ := CreateScheduledAssignment(db, "Scheduled", "", "https://example.com/2", "reading", nil, &future, nil, instructor.ID)
+
+	CreateStudentAssignment(db, published.ID, student.ID)
+	CreateStudentAssignment(db, scheduled.ID, student.ID)
+
+	visible, err := GetStudentAssignmentsByStudent(db, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignments: %v", err)
+	}
+
+	if len(visible) != 1 || visible[0].AssignmentID != published.ID {
+		t.Errorf("Expected only the published assignment to be visible, got %d assignments", len(visible))
+	}
+
+	all, err := GetAllStudentAssignmentsByStudent(db, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get all student assignments: %v", err)
+	}
+
+	if len(all) != 2 {
+		t.Errorf("Expected 2 assignments including scheduled ones, got %d", len(all))
+	}
+}
+
+func TestDueDateOverrideAndCompletionTiming(t *testing.T) {
+	db := setupTestDB(t)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student1 := createTestUser(t, db, "student1", "student")
+	student2 := createTestUser(t, db, "student2", "student")
+
+	pastDue := time.Now().Add(-48 * time.Hour)
+	assignment, _ := CreateAssignment(db, "Past Due", "", "https://example.com", "reading", &pastDue, instructor.ID)
+	CreateStudentAssignment(db, assignment.ID, student1.ID)
+	CreateStudentAssignment(db, assignment.ID, student2.ID)
+
+	// Student 2 gets an extension into next week
+	extended := time.Now().AddDate(0, 0, 7)
+	sa2, _ := GetStudentAssignment(db, assignment.ID, student2.ID)
+	if err := sa2.SetDueDateOverride(db, &extended); err != nil {
+		t.Fatalf("Failed to set due date override: %v", err)
+	}
+
+	overdue1, _ := GetOverdueAssignments(db, student1.ID)
+	if len(overdue1) != 1 {
+		t.Errorf("Expected student1 to have 1 overdue assignment, got %d", len(overdue1))
+	}
+
+	overdue2, _ := GetOverdueAssignments(db, student2.ID)
+	if len(overdue2) != 0 {
+		t.Errorf("Expected extension to clear overdue for student2, got %d", len(overdue2))
+	}
+
+	// Completions are classified against the effective due date
+	sa1, _ := GetStudentAssignment(db, assignment.ID, student1.ID)
+	sa1.MarkAsCompleted(db)
+	sa2.MarkAsCompleted(db)
+
+	sa1, _ = GetStudentAssignment(db, assignment.ID, student1.ID)
+	if sa1.CompletionTiming != TimingLate {
+		t.Errorf("Expected student1 completion to be '%s', got '%s'", TimingLate, sa1.CompletionTiming)
+	}
+
+	sa2, _ = GetStudentAssignment(db, assignment.ID, student2.ID)
+	if sa2.CompletionTiming != TimingOnTime {
+		t.Errorf("Expected student2 completion to be '%s', got '%s'", TimingOnTime, sa2.CompletionTiming)
+	}
+
+	// Excusing after the fact reclassifies the completion
+	if err := sa1.SetExcused(db, true); err != nil {
+		t.Fatalf("Failed to excuse student: %v", err)
+	}
+	sa1, _ = GetStudentAssignment(db, assignment.ID, student1.ID)
+	if sa1.CompletionTiming != TimingExcused {
+		t.Errorf("Expected student1 completion to be '%s', got '%s'", TimingExcused, sa1.CompletionTiming)
+	}
+}
+
+func TestGracePeriod(t *testing.T) {
+	db := setupTestDB(t)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	// Due an hour ago, but with a two hour grace period
+	dueDate := time.Now().Add(-time.Hour)
+	assignment, _ := CreateAssignment(db, "Grace", "", "https://example.com", "reading", &dueDate, instructor.ID)
+	assignment.UpdateLatePolicy(db, 120)
+	CreateStudentAssignment(db, assignment.ID, student.ID)
+
+	overdue, err := GetOverdueAssignments(db, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get overdue assignments: %v", err)
+	}
+	if len(overdue) != 0 {
+		t.Errorf("Expected no overdue assignments within grace period, got %d", len(overdue))
+	}
+
+	sa, _ := GetStudentAssignment(db, assignment.ID, student.ID)
+	sa.MarkAsCompleted(db)
+	sa, _ = GetStudentAssignment(db, assignment.ID, student.ID)
+	if sa.CompletionTiming != TimingOnTime {
+		t.Errorf("Expected completion within grace period to be '%s', got '%s'", TimingOnTime, sa.CompletionTiming)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewLinkToken generates the unguessable token of a student's tracked reading link
+func NewLinkToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// TrackedURL returns the link that opens the reading and records the visit, or the
+// reading itself for student assignments created before links were tracked
+func (sa *StudentAssignment) TrackedURL() string {
+	if sa.LinkToken == nil {
+		return sa.Assignment.URL
+	}
+	return "/r/" + *sa.LinkToken
+}
+
+// ShowTrackedLink fills in the tracked link for the student's own view. Other views leave
+// it out, so that only the student's visits count as opening the reading.
+func (sa *StudentAssignment) ShowTrackedLink() {
+	sa.TrackedLink = sa.TrackedURL()
+}
+
+// CompletedWithoutOpening checks if the student completed the reading without opening its tracked link first
+func (sa *StudentAssignment) CompletedWithoutOpening() bool {
+	if !sa.IsCompleted() || sa.CompletedAt == nil {
+		return false
+	}
+	return sa.FirstOpenedAt == nil || sa.FirstOpenedAt.After(*sa.CompletedAt)
+}
+
+// GetStudentAssignmentByLinkToken retrieves the student assignment a tracked link belongs to
+func GetStudentAssignmentByLinkToken(db *gorm.DB, token string) (*StudentAssignment, error) {
+	var studentAssignment StudentAssignment
+	result := db.Preload("Assignment").Where("link_token = ?", token).First(&studentAssignment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &studentAssignment, nil
+}
+
+// RecordLinkOpen counts a visit to the student's tracked link
+func (sa *StudentAssignment) RecordLinkOpen(db *gorm.DB, at time.Time) error {
+	result := db.Model(sa).UpdateColumns(map[string]interface{}{
+		"link_open_count": gorm.Expr("link_open_count + 1"),
+		"first_opened_at": gorm.Expr("COALESCE(first_opened_at, ?)", at),
+		"last_opened_at":  at,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	sa.LinkOpenCount++
+	if sa.FirstOpenedAt == nil {
+		sa.FirstOpenedAt = &at
+	}
+	sa.LastOpenedAt = &at
+	return nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadedFile is a document an instructor uploaded to assign instead of a URL.
+// Identical uploads share one stored object, addressed by content hash.
+type UploadedFile struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	OwnerID     uint      `json:"owner_id" gorm:"not null;index"`
+	FileName    string    `json:"file_name" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"` // sniffed from the content, not the client
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256" gorm:"not null;index"`
+	StorageKey  string    `json:"-" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UploadedFilePath returns the download path of an uploaded file
+func UploadedFilePath(fileID uint) string {
+	return fmt.Sprintf("/files/%d", fileID)
+}
+
+// GetUploadedFileByID retrieves an uploaded file by ID
+func GetUploadedFileByID(db *gorm.DB, id uint) (*UploadedFile, error) {
+	var file UploadedFile
+	result := db.First(&file, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &file, nil
+}
+
+// GetUploadedFilesByOwner retrieves an instructor's uploads, newest first
+func GetUploadedFilesByOwner(db *gorm.DB, ownerID uint) ([]UploadedFile, error) {
+	var files []UploadedFile
+	result := db.Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&files)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return files, nil
+}
+
+// CountUploadedFilesByKey counts the uploads sharing a stored object
+func CountUploadedFilesByKey(db *gorm.DB, storageKey string) (int64, error) {
+	var count int64
+	result := db.Model(&UploadedFile{}).Where("storage_key = ?", storageKey).Count(&count)
+	return count, result.Error
+}
+
+// CountAssignmentsUsingFile counts the assignments a file is attached to
+func CountAssignmentsUsingFile(db *gorm.DB, fileID uint) (int64, error) {
+	var count int64
+	result := db.Model(&Assignment{}).Where("file_id = ?", fileID).Count(&count)
+	return count, result.Error
+}
+
+// StudentHasFileAccess reports whether a student is assigned a released assignment using the file
+func StudentHasFileAccess(db *gorm.DB, fileID, studentID uint, now time.Time) (bool, error) {
+	var count int64
+	result := db.Model(&StudentAssignment{}).
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(PublishedAt(now)).
+		Where("student_assignments.student_id = ? AND assignments.file_id = ? AND assignments.deleted_at IS NULL", studentID, fileID).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
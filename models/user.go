@@ -70,6 +70,31 @@ func GetUserByID(db *gorm.DB, id uint) (*User, error) {
 	return &user, nil
 }
 
+// GetUsersByIDs retrieves several users, keyed by ID
+func GetUsersByIDs(db *gorm.DB, ids []uint) (map[uint]User, error) {
+	users := make(map[uint]User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	var rows []User
+	result := db.Where("id IN ?", ids).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, user := range rows {
+		users[user.ID] = user
+	}
+	return users, nil
+}
+
+// UsernameTaken checks if a username other than the given user's matches name, ignoring case
+func UsernameTaken(db *gorm.DB, name string, userID uint) (bool, error) {
+	var count int64
+	result := db.Model(&User{}).Where("LOWER(username) = LOWER(?) AND id <> ?", name, userID).Count(&count)
+	return count > 0, result.Error
+}
+
 // UpdateUser updates user information
 func (u *User) Update(db *gorm.DB) error {
 	return db.Save(u).Error
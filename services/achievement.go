@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// Badges
+const (
+	BadgeFirstReading  = "first_reading"
+	BadgeOnTime10      = "on_time_10"
+	BadgeReadings25    = "readings_25"
+	BadgeFirstToFinish = "first_to_finish"
+	BadgeWeekStreak    = "week_streak"
+)
+
+// Badge describes an achievement students can earn
+type Badge struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	PerAssignment bool   `json:"per_assignment"` // earned again on every reading that qualifies
+}
+
+// badgeRule decides whether a completion earns a badge
+type badgeRule struct {
+	Badge
+	earned func(event *completionEvent) (bool, error)
+}
+
+// completionEvent is a student completing a reading, with the totals the rules need
+type completionEvent struct {
+	db                *gorm.DB
+	studentAssignment *models.StudentAssignment
+	completed         int
+	onTime            int
+}
+
+// badgeRules lists every badge in the order they are shown
+var badgeRules = []badgeRule{
+	{
+		Badge: Badge{Code: BadgeFirstReading, Name: "First Reading", Description: "Complete your first reading"},
+		earned: func(e *completionEvent) (bool, error) {
+			return e.completed >= 1, nil
+		},
+	},
+	{
+		Badge: Badge{Code: BadgeOnTime10, Name: "Right on Time", Description: "Complete 10 readings on time"},
+		earned: func(e *completionEvent) (bool, error) {
+			return e.onTime >= 10, nil
+		},
+	},
+	{
+		Badge: Badge{Code: BadgeReadings25, Name: "Bookworm", Description: "Complete 25 readings"},
+		earned: func(e *completionEvent) (bool, error) {
+			return e.completed >= 25, nil
+		},
+	},
+	{
+		Badge: Badge{Code: BadgeFirstToFinish, Name: "First to Finish", Description: "Be the first student to complete a reading", PerAssignment: true},
+		earned: func(e *completionEvent) (bool, error) {
+			return e.studentAssignment.IsFirstCompletion(e.db)
+		},
+	},
+	{
+		Badge: Badge{Code: BadgeWeekStreak, Name: "Week Streak", Description: "Make reading progress seven days in a row"},
+		earned: func(e *completionEvent) (bool, error) {
+			insights, err := NewInsightsService(e.db).GetInsights(e.studentAssignment.StudentID, time.Now(), time.Local)
+			if err != nil {
+				return false, err
+			}
+			return insights.CurrentStreak >= 7, nil
+		},
+	},
+}
+
+// EarnedBadge is a badge a student has, with when and on which reading they earned it
+type EarnedBadge struct {
+	Badge
+	AssignmentID uint      `json:"assignment_id,omitempty"`
+	AwardedAt    time.Time `json:"awarded_at"`
+}
+
+// StudentAchievements lists the badges a student has earned and those still open to them
+type StudentAchievements struct {
+	Earned    []EarnedBadge `json:"earned"`
+	Available []Badge       `json:"available"`
+}
+
+// AchievementService serves the badges students earn
+type AchievementService struct {
+	db *gorm.DB
+}
+
+// NewAchievementService creates a new achievement service
+func NewAchievementService(db *gorm.DB) *AchievementService {
+	return &AchievementService{db: db}
+}
+
+// GetAchievements returns a student's badges and the ones they can still earn
+func (s *AchievementService) GetAchievements(studentID uint) (*StudentAchievements, error) {
+	achievements, err := models.GetAchievementsByUser(s.db, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	badges := make(map[string]Badge, len(badgeRules))
+	for _, rule := range badgeRules {
+		badges[rule.Code] = rule.Badge
+	}
+
+	result := &StudentAchievements{Earned: []EarnedBadge{}, Available: []Badge{}}
+	earned := make(map[string]bool)
+	for _, achievement := range achievements {
+		badge, ok := badges[achievement.Badge]
+		if !ok {
+			continue
+		}
+		earned[badge.Code] = true
+		result.Earned = append(result.Earned, EarnedBadge{Badge: badge, AssignmentID: achievement.AssignmentID, AwardedAt: achievement.AwardedAt})
+	}
+	for _, rule := range badgeRules {
+		if rule.PerAssignment || !earned[rule.Code] {
+			result.Available = append(result.Available, rule.Badge)
+		}
+	}
+	return result, nil
+}
+
+// evaluateAchievements awards the badges a student earned if their reading is now completed.
+// It runs after a status change is saved, so failures are logged rather than undoing it.
+func evaluateAchievements(db *gorm.DB, studentAssignment *models.StudentAssignment) {
+	if _, err := awardAchievements(db, studentAssignment.ID, studentAssignment.StudentID); err != nil {
+		log.Printf("failed to evaluate achievements for student assignment %d: %v", studentAssignment.ID, err)
+	}
+}
+
+// awardAchievements checks every badge rule against a completed reading and returns the badges newly earned.
+// Its callers have already checked the reading is released, so it looks the reading up directly.
+func awardAchievements(db *gorm.DB, studentAssignmentID uint, studentID uint) ([]Badge, error) {
+	studentAssignment, err := models.GetStudentAssignmentByID(db, studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !studentAssignment.IsCompleted() {
+		return nil, nil
+	}
+
+	event := &completionEvent{db: db, studentAssignment: studentAssignment}
+	event.completed, event.onTime, err = models.CountCompletions(db, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var awarded []Badge
+	for _, rule := range badgeRules {
+		earned, err := rule.earned(event)
+		if err != nil {
+			return awarded, err
+		}
+		if !earned {
+			continue
+		}
+
+		achievement := &models.Achievement{UserID: studentID, Badge: rule.Code, AwardedAt: time.Now()}
+		var assignmentID *uint
+		if rule.PerAssignment {
+			achievement.AssignmentID = studentAssignment.AssignmentID
+			assignmentID = &studentAssignment.AssignmentID
+		}
+		created, err := models.AwardAchievement(db, achievement)
+		if err != nil {
+			return awarded, err
+		}
+		if !created {
+			continue
+		}
+		awarded = append(awarded, rule.Badge)
+
+		message := fmt.Sprintf("🏅 You earned the '%s' badge: %s", rule.Name, rule.Description)
+		if _, err := models.CreateNotification(db, studentID, models.NotificationAchievement, message, assignmentID); err != nil {
+			return awarded, err
+		}
+	}
+	return awarded, nil
+}
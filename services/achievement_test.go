@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestAchievements(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAchievementService(db)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	fast := createTestUser(t, db, "fast", "student")
+	slow := createTestUser(t, db, "slow", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{fast.ID, slow.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	if err := studentService.MarkAsInProgress(assignment.ID, fast.ID); err != nil {
+		t.Fatalf("Failed to start assignment: %v", err)
+	}
+	if achievements, _ := service.GetAchievements(fast.ID); len(achievements.Earned) != 0 {
+		t.Errorf("Expected no badges before completing anything, got %+v", achievements.Earned)
+	}
+
+	for _, studentID := range []uint{fast.ID, slow.ID} {
+		if err := studentService.MarkAsCompleted(assignment.ID, studentID); err != nil {
+			t.Fatalf("Failed to complete assignment: %v", err)
+		}
+	}
+
+	achievements, err := service.GetAchievements(fast.ID)
+	if err != nil {
+		t.Fatalf("Failed to get achievements: %v", err)
+	}
+	earned := map[string]uint{}
+	for _, badge := range achievements.Earned {
+		earned[badge.Code] = badge.AssignmentID
+	}
+	if len(earned) != 2 || earned[BadgeFirstToFinish] != assignment.ID {
+		t.Errorf("Expected the first reading and first to finish badges, got %+v", achievements.Earned)
+	}
+	for _, badge := range achievements.Available {
+		if badge.Code == BadgeFirstReading {
+			t.Error("Expected an earned one-time badge to leave the available list")
+		}
+	}
+
+	achievements, _ = service.GetAchievements(slow.ID)
+	if len(achievements.Earned) != 1 || achievements.Earned[0].Code != BadgeFirstReading {
+		t.Errorf("Expected only the first reading badge for the second finisher, got %+v", achievements.Earned)
+	}
+
+	// Badges are only awarded once, however often the status changes
+	if err := studentService.UpdateAssignmentStatus(assignment.ID, slow.ID, models.StatusCompleted); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	notifications, _ := models.GetNotificationsByUser(db, slow.ID, false)
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationAchievement {
+		t.Errorf("Expected one achievement notification, got %+v", notifications)
+	}
+}
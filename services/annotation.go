@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"zipcodereader/models"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAnnotationQuote limits the length of a highlighted passage in bytes
+	maxAnnotationQuote = 2000
+	// maxAnnotationContext limits the prefix and suffix kept around a passage in bytes
+	maxAnnotationContext = 200
+	// maxAnnotationNote limits the length of a margin note in bytes
+	maxAnnotationNote = 5000
+	// popularPassageLimit is how many passages the most-highlighted report lists
+	popularPassageLimit = 10
+)
+
+// AnnotationService handles highlights and margin notes on archived readings
+type AnnotationService struct {
+	db             *gorm.DB
+	archiveService *ArchiveService
+}
+
+// NewAnnotationService creates a new annotation service
+func NewAnnotationService(db *gorm.DB, archiveService *ArchiveService) *AnnotationService {
+	return &AnnotationService{db: db, archiveService: archiveService}
+}
+
+// AnnotationInput represents a new highlight: the selected text, the text around it and
+// its character offsets in the archived reading, with an optional note
+type AnnotationInput struct {
+	Exact      string
+	Prefix     string
+	Suffix     string
+	Start      int
+	End        int
+	Note       string
+	Visibility string
+}
+
+// GetAnnotations returns the user's own annotations on an assignment and those shared with the class
+func (s *AnnotationService) GetAnnotations(assignmentID uint, user *models.User) ([]models.Annotation, error) {
+	if _, _, err := authorizeAssignmentMember(s.db, assignmentID, user); err != nil {
+		return nil, err
+	}
+
+	annotations, err := models.GetVisibleAnnotations(s.db, assignmentID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range annotations {
+		annotations[i].AuthorName = annotations[i].User.Username
+	}
+	return annotations, nil
+}
+
+// CreateAnnotation highlights a passage of an assignment's archived copy. The passage must
+// appear in the archive so that it can be anchored again when the page is displayed.
+func (s *AnnotationService) CreateAnnotation(assignmentID uint, user *models.User, input AnnotationInput) (*models.Annotation, error) {
+	if _, _, err := authorizeAssignmentMember(s.db, assignmentID, user); err != nil {
+		return nil, err
+	}
+
+	archive, err := models.GetAssignmentArchive(s.db, assignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("assignment has no archived copy to annotate")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Exact) == "" {
+		return nil, errors.New("select a passage to highlight")
+	}
+	if len(input.Exact) > maxAnnotationQuote {
+		return nil, errors.New("highlighted passage is too long")
+	}
+	if len(input.Prefix) > maxAnnotationContext || len(input.Suffix) > maxAnnotationContext {
+		return nil, errors.New("passage context is too long")
+	}
+	if input.Start < 0 || input.End <= input.Start {
+		return nil, errors.New("invalid passage position")
+	}
+
+	note, visibility, err := validateAnnotationNote(input.Note, input.Visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.archiveService.ReadArchiveContent(archive)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(collapseWhitespace(archiveText(content)), collapseWhitespace(input.Exact)) {
+		return nil, errors.New("passage not found in the archived reading")
+	}
+
+	annotation := &models.Annotation{
+		AssignmentID: assignmentID,
+		ArchiveID:    archive.ID,
+		UserID:       user.ID,
+		Exact:        input.Exact,
+		Prefix:       input.Prefix,
+		Suffix:       input.Suffix,
+		StartOffset:  input.Start,
+		EndOffset:    input.End,
+		Note:         note,
+		Visibility:   visibility,
+	}
+	if err := models.CreateAnnotation(s.db, annotation); err != nil {
+		return nil, err
+	}
+
+	annotation.AuthorName = user.Username
+	return annotation, nil
+}
+
+// UpdateAnnotation changes the note and visibility of the user's own annotation
+func (s *AnnotationService) UpdateAnnotation(annotationID uint, user *models.User, note, visibility string) (*models.Annotation, error) {
+	annotation, _, err := s.getAnnotation(annotationID, user)
+	if err != nil {
+		return nil, err
+	}
+	if annotation.UserID != user.ID {
+		return nil, errors.New("access denied")
+	}
+
+	note, visibility, err = validateAnnotationNote(note, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.UpdateAnnotation(s.db, annotation, note, visibility); err != nil {
+		return nil, err
+	}
+
+	annotation.AuthorName = annotation.User.Username
+	return annotation, nil
+}
+
+// DeleteAnnotation removes an annotation; authors may delete their own and instructors
+// shared annotations on their assignment
+func (s *AnnotationService) DeleteAnnotation(annotationID uint, user *models.User) error {
+	annotation, moderator, err := s.getAnnotation(annotationID, user)
+	if err != nil {
+		return err
+	}
+	if annotation.UserID != user.ID && !moderator {
+		return errors.New("access denied")
+	}
+	return models.DeleteAnnotation(s.db, annotation)
+}
+
+// GetMostHighlighted returns the passages of an instructor's assignment that the most
+// readers highlighted. Only the passages and counts are reported, never private notes.
+func (s *AnnotationService) GetMostHighlighted(assignmentID uint, instructorID uint) ([]models.HighlightedPassage, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	return models.GetMostHighlightedPassages(s.db, assignmentID, popularPassageLimit)
+}
+
+// getAnnotation loads an annotation the user can see and reports whether they moderate the assignment
+func (s *AnnotationService) getAnnotation(annotationID uint, user *models.User) (*models.Annotation, bool, error) {
+	annotation, err := models.GetAnnotationByID(s.db, annotationID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, moderator, err := authorizeAssignmentMember(s.db, annotation.AssignmentID, user)
+	if err != nil {
+		return nil, false, err
+	}
+	if annotation.UserID != user.ID && annotation.Visibility != models.AnnotationShared {
+		return nil, false, errors.New("annotation not found")
+	}
+	return annotation, moderator, nil
+}
+
+// validateAnnotationNote trims a note and defaults annotations to private
+func validateAnnotationNote(note, visibility string) (string, string, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > maxAnnotationNote {
+		return "", "", errors.New("note is too long")
+	}
+
+	switch visibility {
+	case "":
+		visibility = models.AnnotationPrivate
+	case models.AnnotationPrivate, models.AnnotationShared:
+	default:
+		return "", "", errors.New("visibility must be private or shared")
+	}
+	return note, visibility, nil
+}
+
+// archiveText returns the text of an archived page as a reader sees it
+func archiveText(content string) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return text.String()
+		case html.TextToken:
+			text.Write(tokenizer.Text())
+		}
+	}
+}
+
+// collapseWhitespace reduces every run of whitespace to a single space
+func collapseWhitespace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
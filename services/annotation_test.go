@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestAnnotations(t *testing.T) {
+	server := newArchiveTestServer(t, `<html><head><title>Channels</title></head><body>
+		<h1>Channels</h1>
+		<p>Do not communicate by sharing memory; instead,
+		share memory by communicating.</p>
+		<p>Channels are typed conduits.</p>
+		</body></html>`, nil)
+	defer server.Close()
+
+	archiveService, db := newTestArchiveService(t, 1<<20)
+	service := NewAnnotationService(db, archiveService)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	classmate := createTestUser(t, db, "student2", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Channels", URL: server.URL + "/channels"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{student.ID, classmate.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	quote := AnnotationInput{Exact: "share memory by communicating", Start: 60, End: 89}
+	if _, err := service.CreateAnnotation(assignment.ID, student, quote); err == nil {
+		t.Error("Expected annotations to require an archived copy")
+	}
+	if _, err := archiveService.ArchiveAssignment(assignment.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to archive page: %v", err)
+	}
+
+	private, err := service.CreateAnnotation(assignment.ID, student, quote)
+	if err != nil {
+		t.Fatalf("Failed to highlight passage: %v", err)
+	}
+	if private.Visibility != models.AnnotationPrivate {
+		t.Errorf("Expected highlights to default to private, got %q", private.Visibility)
+	}
+
+	// Selections spanning a line break in the source still match the archived text
+	shared, err := service.CreateAnnotation(assignment.ID, classmate, AnnotationInput{
+		Exact:      "instead,\n\t\tshare memory",
+		Start:      45,
+		End:        66,
+		Note:       "The Go proverb",
+		Visibility: models.AnnotationShared,
+	})
+	if err != nil {
+		t.Fatalf("Failed to annotate passage: %v", err)
+	}
+	if _, err := service.CreateAnnotation(assignment.ID, classmate, quote); err != nil {
+		t.Fatalf("Failed to highlight passage: %v", err)
+	}
+
+	invalid := []AnnotationInput{
+		{Exact: "  ", Start: 0, End: 2},
+		{Exact: "share memory", Start: 10, End: 5},
+		{Exact: "mutexes are simpler", Start: 0, End: 19},
+		{Exact: "share memory", Start: 0, End: 12, Visibility: "public"},
+	}
+	for _, input := range invalid {
+		if _, err := service.CreateAnnotation(assignment.ID, student, input); err == nil {
+			t.Errorf("Expected %+v to be rejected", input)
+		}
+	}
+
+	visible, err := service.GetAnnotations(assignment.ID, student)
+	if err != nil {
+		t.Fatalf("Failed to get annotations: %v", err)
+	}
+	if len(visible) != 2 {
+		t.Fatalf("Expected the student's own and the shared annotation, got %d", len(visible))
+	}
+	if visible[0].ID != shared.ID || visible[0].AuthorName != "student2" {
+		t.Errorf("Expected annotations in reading order with authors, got %+v", visible)
+	}
+
+	if _, err := service.UpdateAnnotation(shared.ID, student, "Mine now", models.AnnotationPrivate); err == nil {
+		t.Error("Expected students to be unable to edit others' annotations")
+	}
+	if _, err := service.UpdateAnnotation(private.ID, classmate, "Peeking", ""); err == nil {
+		t.Error("Expected private annotations to be hidden from classmates")
+	}
+	updated, err := service.UpdateAnnotation(private.ID, student, "Worth remembering", models.AnnotationShared)
+	if err != nil {
+		t.Fatalf("Failed to update annotation: %v", err)
+	}
+	if updated.Note != "Worth remembering" || updated.Visibility != models.AnnotationShared {
+		t.Errorf("Expected note and visibility to change, got %+v", updated)
+	}
+
+	passages, err := service.GetMostHighlighted(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get popular passages: %v", err)
+	}
+	if len(passages) != 2 || passages[0].Exact != quote.Exact || passages[0].Readers != 2 || passages[0].Notes != 1 {
+		t.Errorf("Expected the shared quote to rank first with 2 readers, got %+v", passages)
+	}
+	if _, err := service.GetMostHighlighted(assignment.ID, student.ID); err == nil {
+		t.Error("Expected students to be denied the highlight report")
+	}
+
+	if err := service.DeleteAnnotation(private.ID, classmate); err == nil {
+		t.Error("Expected classmates to be unable to delete annotations")
+	}
+	if err := service.DeleteAnnotation(shared.ID, instructor); err != nil {
+		t.Errorf("Expected the instructor to remove shared annotations: %v", err)
+	}
+}
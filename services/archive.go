@@ -0,0 +1,461 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxArchiveRedirects limits how many redirects an archive fetch follows
+	maxArchiveRedirects = 5
+	// maxArchiveImages limits how many images are stored with a page
+	maxArchiveImages = 50
+	// archiveIndexFile holds the sanitized page HTML within an archive directory
+	archiveIndexFile = "index.html"
+)
+
+// ErrArchiveTooLarge is returned when a page alone exceeds the archive size cap
+var ErrArchiveTooLarge = errors.New("page exceeds the archive size limit")
+
+// archiveAssetName matches the image file names written by the archiver
+var archiveAssetName = regexp.MustCompile(`^img-[0-9]+\.(png|jpg|gif|webp)$`)
+
+// archiveImageTypes maps the accepted image content types to file extensions.
+// SVG is excluded because it can carry scripts.
+var archiveImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// droppedArchiveElements are removed from archived pages together with their content
+var droppedArchiveElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"iframe": true, "frame": true, "frameset": true, "object": true, "embed": true, "applet": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true, "dialog": true,
+	"link": true, "meta": true, "base": true,
+	"svg": true, "math": true, "canvas": true, "audio": true, "video": true, "source": true, "track": true,
+	"nav": true, "aside": true, "footer": true,
+}
+
+// allowedArchiveAttributes are kept on any element; href and src are handled separately
+var allowedArchiveAttributes = map[string]bool{
+	"alt": true, "title": true, "lang": true, "dir": true,
+	"colspan": true, "rowspan": true, "width": true, "height": true,
+}
+
+// ArchiveOptions configures fetching and storage of offline page copies
+type ArchiveOptions struct {
+	Dir      string
+	Timeout  time.Duration
+	MaxBytes int64 // cap on the stored HTML plus images
+}
+
+// ArchiveService stores sanitized offline copies of assignment pages
+type ArchiveService struct {
+	db                       *gorm.DB
+	client                   *http.Client
+	guard                    *addressGuard
+	dir                      string
+	maxBytes                 int64
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewArchiveService creates a new archive service
+func NewArchiveService(db *gorm.DB, options ArchiveOptions) *ArchiveService {
+	s := &ArchiveService{
+		db:                       db,
+		guard:                    &addressGuard{},
+		dir:                      options.Dir,
+		maxBytes:                 options.MaxBytes,
+		studentAssignmentService: NewStudentAssignmentService(db),
+	}
+
+	s.client = newGuardedClient(s.guard, options.Timeout, func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxArchiveRedirects {
+			return errors.New("too many redirects")
+		}
+		return validateFetchURL(req.URL)
+	})
+
+	return s
+}
+
+// ArchiveAssignment fetches an assignment's page and stores a sanitized copy with its images,
+// replacing any earlier archive
+func (s *ArchiveService) ArchiveAssignment(assignmentID uint, instructorID uint) (*models.AssignmentArchive, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	if assignment.FileID != nil {
+		return nil, errors.New("uploaded files are already stored")
+	}
+
+	target, err := url.Parse(strings.TrimSpace(assignment.URL))
+	if err != nil || assignment.URL == "" {
+		return nil, errors.New("assignment has no valid URL")
+	}
+	if err := validateFetchURL(target); err != nil {
+		return nil, err
+	}
+
+	body, finalURL, err := s.fetchPage(target)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(s.dir, "tmp-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	// The original page size bounds the sanitized HTML; images get what remains
+	archiver := &pageArchiver{
+		service: s,
+		base:    finalURL,
+		dir:     staging,
+		budget:  s.maxBytes - int64(len(body)),
+		saved:   make(map[string]string),
+	}
+
+	title := strings.TrimSpace(findArchiveTitle(doc))
+	content, err := archiver.archiveBody(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(staging, archiveIndexFile), content, 0o644); err != nil {
+		return nil, err
+	}
+
+	// Swap the new copy in only after it was written completely
+	archiveDir := s.archiveDir(assignmentID)
+	if err := os.RemoveAll(archiveDir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, archiveDir); err != nil {
+		return nil, err
+	}
+
+	archive := &models.AssignmentArchive{
+		AssignmentID: assignmentID,
+		SourceURL:    target.String(),
+		FinalURL:     finalURL.String(),
+		Title:        firstNonEmpty(title, assignment.Title),
+		SizeBytes:    int64(len(content)) + archiver.imageBytes,
+		ImageCount:   len(archiver.saved),
+		ArchivedAt:   time.Now(),
+	}
+	if err := models.SaveAssignmentArchive(s.db, archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// GetArchive returns an assignment's archive metadata
+func (s *ArchiveService) GetArchive(assignmentID uint) (*models.AssignmentArchive, error) {
+	return models.GetAssignmentArchive(s.db, assignmentID)
+}
+
+// GetInstructorArchive returns the archive of an assignment owned by the instructor
+func (s *ArchiveService) GetInstructorArchive(assignmentID uint, instructorID uint) (*models.AssignmentArchive, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	return models.GetAssignmentArchive(s.db, assignmentID)
+}
+
+// GetStudentArchive returns the archive of an assignment released to the student
+func (s *ArchiveService) GetStudentArchive(studentAssignmentID uint, studentID uint) (*models.AssignmentArchive, error) {
+	studentAssignment, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentArchive(s.db, studentAssignment.AssignmentID)
+}
+
+// ReadArchiveContent returns the sanitized HTML of an archive
+func (s *ArchiveService) ReadArchiveContent(archive *models.AssignmentArchive) (string, error) {
+	content, err := os.ReadFile(filepath.Join(s.archiveDir(archive.AssignmentID), archiveIndexFile))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// ArchiveAssetPath returns the file path of an archived image
+func (s *ArchiveService) ArchiveAssetPath(archive *models.AssignmentArchive, name string) (string, error) {
+	if !archiveAssetName.MatchString(name) {
+		return "", errors.New("asset not found")
+	}
+	return filepath.Join(s.archiveDir(archive.AssignmentID), name), nil
+}
+
+// DeleteArchive removes an assignment's archive and its files
+func (s *ArchiveService) DeleteArchive(assignmentID uint, instructorID uint) error {
+	if _, err := s.GetInstructorArchive(assignmentID, instructorID); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(s.archiveDir(assignmentID)); err != nil {
+		return err
+	}
+	return models.DeleteAssignmentArchive(s.db, assignmentID)
+}
+
+// archiveDir returns the storage directory of an assignment's archive
+func (s *ArchiveService) archiveDir(assignmentID uint) string {
+	return filepath.Join(s.dir, strconv.FormatUint(uint64(assignmentID), 10))
+}
+
+// fetchPage downloads an HTML page within the size cap and returns it with its final URL
+func (s *ArchiveService) fetchPage(target *url.URL) ([]byte, *url.URL, error) {
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, nil, errors.New("invalid URL")
+	}
+	req.Header.Set("User-Agent", "ZipCodeReader-Archiver/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, nil, ErrBlockedAddress
+		}
+		return nil, nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, nil, fmt.Errorf("failed to fetch page: status %d", resp.StatusCode)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, nil, errors.New("only HTML pages can be archived")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	if int64(len(body)) > s.maxBytes {
+		return nil, nil, ErrArchiveTooLarge
+	}
+
+	return body, resp.Request.URL, nil
+}
+
+// pageArchiver sanitizes a single page and stores its images
+type pageArchiver struct {
+	service    *ArchiveService
+	base       *url.URL
+	dir        string
+	budget     int64             // bytes left for images
+	imageBytes int64             // bytes of images stored
+	saved      map[string]string // image URL to stored file name
+}
+
+// archiveBody sanitizes the document body and renders its content
+func (a *pageArchiver) archiveBody(doc *html.Node) ([]byte, error) {
+	body := findArchiveElement(doc, "body")
+	if body == nil {
+		return nil, errors.New("page has no content")
+	}
+
+	a.sanitize(body)
+
+	var buf bytes.Buffer
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&buf, child); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// sanitize removes active content and untrusted attributes below a node
+func (a *pageArchiver) sanitize(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch child.Type {
+		case html.CommentNode, html.DoctypeNode:
+			n.RemoveChild(child)
+		case html.ElementNode:
+			if droppedArchiveElements[child.Data] || child.Namespace != "" || !a.sanitizeElement(child) {
+				n.RemoveChild(child)
+			} else {
+				a.sanitize(child)
+			}
+		}
+
+		child = next
+	}
+}
+
+// sanitizeElement filters an element's attributes, making links absolute and images local.
+// It reports whether the element should be kept.
+func (a *pageArchiver) sanitizeElement(n *html.Node) bool {
+	var href, src string
+	attrs := n.Attr[:0]
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		switch {
+		case key == "href":
+			href = attr.Val
+		case key == "src":
+			src = attr.Val
+		case key == "data-src" && src == "":
+			// Lazy-loaded images keep their real source here
+			src = attr.Val
+		case allowedArchiveAttributes[key] && attr.Namespace == "":
+			attrs = append(attrs, html.Attribute{Key: key, Val: attr.Val})
+		}
+	}
+	n.Attr = attrs
+
+	switch n.Data {
+	case "a":
+		if link := a.resolve(href); link != nil {
+			n.Attr = append(n.Attr,
+				html.Attribute{Key: "href", Val: link.String()},
+				html.Attribute{Key: "target", Val: "_blank"},
+				html.Attribute{Key: "rel", Val: "noopener noreferrer"},
+			)
+		}
+	case "img":
+		image := a.resolve(src)
+		if image == nil {
+			return false
+		}
+		name, ok := a.saveImage(image)
+		if !ok {
+			return false
+		}
+		n.Attr = append(n.Attr, html.Attribute{Key: "src", Val: "archive/assets/" + name})
+	}
+	return true
+}
+
+// resolve makes a reference absolute, keeping only http and https URLs
+func (a *pageArchiver) resolve(ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil
+	}
+
+	resolved, err := a.base.Parse(ref)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return nil
+	}
+	return resolved
+}
+
+// saveImage downloads an image into the archive while the size budget allows it
+func (a *pageArchiver) saveImage(image *url.URL) (string, bool) {
+	if name, ok := a.saved[image.String()]; ok {
+		return name, true
+	}
+
+	if len(a.saved) >= maxArchiveImages || a.budget <= 0 {
+		return "", false
+	}
+
+	req, err := http.NewRequest(http.MethodGet, image.String(), nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("User-Agent", "ZipCodeReader-Archiver/1.0")
+
+	resp, err := a.service.client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	extension, ok := archiveImageTypes[mediaType]
+	if err != nil || !ok {
+		return "", false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, a.budget+1))
+	if err != nil || int64(len(data)) > a.budget {
+		return "", false
+	}
+
+	name := fmt.Sprintf("img-%d%s", len(a.saved)+1, extension)
+	if err := os.WriteFile(filepath.Join(a.dir, name), data, 0o644); err != nil {
+		return "", false
+	}
+
+	a.budget -= int64(len(data))
+	a.imageBytes += int64(len(data))
+	a.saved[image.String()] = name
+	return name, true
+}
+
+// findArchiveElement returns the first element with the given tag name
+func findArchiveElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findArchiveElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findArchiveTitle returns the text of the document's <title>
+func findArchiveTitle(doc *html.Node) string {
+	title := findArchiveElement(doc, "title")
+	if title == nil || title.FirstChild == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(title.FirstChild.Data), " ")
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+func newTestArchiveService(t *testing.T, maxBytes int64) (*ArchiveService, *gorm.DB) {
+	db := setupTestDB(t)
+	service := NewArchiveService(db, ArchiveOptions{
+		Dir:      t.TempDir(),
+		Timeout:  2 * time.Second,
+		MaxBytes: maxBytes,
+	})
+	// httptest servers listen on loopback
+	service.guard.allowPrivateNetworks = true
+	return service, db
+}
+
+func testPNG(t *testing.T, size int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newArchiveTestServer(t *testing.T, page string, images map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, ok := images[r.URL.Path]; ok {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(data)
+			return
+		}
+		if r.URL.Path == "/diagram.svg" {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			fmt.Fprint(w, `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	}))
+}
+
+func TestArchiveAssignmentSanitizesPage(t *testing.T) {
+	server := newArchiveTestServer(t, `<!DOCTYPE html><html><head>
+		<title>Understanding Goroutines</title>
+		<script>alert("head")</script>
+		<link rel="stylesheet" href="/site.css">
+		</head><body>
+		<nav><a href="/home">Home</a></nav>
+		<h1 class="headline" style="color:red" onclick="steal()">Understanding Goroutines</h1>
+		<p>Read <a href="/next" onmouseover="steal()">the next part</a> or <a href="javascript:steal()">this</a>.</p>
+		<img src="/gopher.png" alt="Gopher">
+		<img data-src="/gopher.png" alt="Same gopher">
+		<img src="/diagram.svg" alt="Diagram">
+		<script>document.cookie</script>
+		<iframe src="https://ads.example.com"></iframe>
+		<form action="/login"><input name="password"></form>
+		<!-- tracking comment -->
+		</body></html>`, map[string][]byte{"/gopher.png": testPNG(t, 4)})
+	defer server.Close()
+
+	service, db := newTestArchiveService(t, 1<<20)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	assignment, err := NewAssignmentService(db).CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Goroutines",
+		URL:   server.URL + "/article",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	archive, err := service.ArchiveAssignment(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to archive page: %v", err)
+	}
+
+	if archive.Title != "Understanding Goroutines" {
+		t.Errorf("Expected page title, got %q", archive.Title)
+	}
+	if archive.ImageCount != 1 {
+		t.Errorf("Expected the PNG to be stored once and the SVG skipped, got %d images", archive.ImageCount)
+	}
+
+	content, err := service.ReadArchiveContent(archive)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	for _, unsafe := range []string{"<script", "alert", "onclick", "onmouseover", "style=", "class=", "<iframe", "<form", "<input", "javascript:", "tracking comment", "<nav", "diagram.svg"} {
+		if strings.Contains(content, unsafe) {
+			t.Errorf("Expected %q to be removed from archived page:\n%s", unsafe, content)
+		}
+	}
+
+	for _, kept := range []string{
+		"<h1>Understanding Goroutines</h1>",
+		`href="` + server.URL + `/next"`,
+		`rel="noopener noreferrer"`,
+		`<img alt="Gopher" src="archive/assets/img-1.png"/>`,
+		`<img alt="Same gopher" src="archive/assets/img-1.png"/>`,
+	} {
+		if !strings.Contains(content, kept) {
+			t.Errorf("Expected archived page to contain %q:\n%s", kept, content)
+		}
+	}
+
+	path, err := service.ArchiveAssetPath(archive, "img-1.png")
+	if err != nil {
+		t.Fatalf("Failed to get asset path: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected archived image on disk: %v", err)
+	}
+
+	if _, err := service.ArchiveAssetPath(archive, "../index.html"); err == nil {
+		t.Error("Expected asset names outside the archive to be rejected")
+	}
+}
+
+func TestArchiveAssignmentRespectsSizeCap(t *testing.T) {
+	page := `<html><head><title>Big Images</title></head><body><p>Text</p><img src="/large.png"></body></html>`
+	large := bytes.Repeat([]byte("x"), 4096)
+	server := newArchiveTestServer(t, page, map[string][]byte{"/large.png": large})
+	defer server.Close()
+
+	// Room for the page but not the image
+	service, db := newTestArchiveService(t, int64(len(page))+1024)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	assignment, err := NewAssignmentService(db).CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Big Images",
+		URL:   server.URL + "/article",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	archive, err := service.ArchiveAssignment(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Expected page to be archived without the oversized image: %v", err)
+	}
+	if archive.ImageCount != 0 {
+		t.Errorf("Expected oversized image to be skipped, got %d images", archive.ImageCount)
+	}
+	if archive.SizeBytes > int64(len(page))+1024 {
+		t.Errorf("Expected archive within the size cap, got %d bytes", archive.SizeBytes)
+	}
+
+	// A page larger than the cap is refused outright
+	service.maxBytes = int64(len(page)) - 1
+	if _, err := service.ArchiveAssignment(assignment.ID, instructor.ID); !errors.Is(err, ErrArchiveTooLarge) {
+		t.Errorf("Expected ErrArchiveTooLarge, got %v", err)
+	}
+
+	// The earlier archive survives a failed refresh
+	if _, err := service.ReadArchiveContent(archive); err != nil {
+		t.Errorf("Expected previous archive to remain readable: %v", err)
+	}
+}
+
+func TestArchiveAccess(t *testing.T) {
+	server := newArchiveTestServer(t, `<html><body><p>Hello</p></body></html>`, nil)
+	defer server.Close()
+
+	service, db := newTestArchiveService(t, 1<<20)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	otherStudent := createTestUser(t, db, "student2", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Hello",
+		URL:   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	if _, err := service.ArchiveAssignment(assignment.ID, otherInstructor.ID); err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected access denied for another instructor, got %v", err)
+	}
+
+	if _, err := service.ArchiveAssignment(assignment.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to archive page: %v", err)
+	}
+
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	studentAssignment, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+
+	archive, err := service.GetStudentArchive(studentAssignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Expected assigned student to read the archive: %v", err)
+	}
+	if archive.AssignmentID != assignment.ID {
+		t.Errorf("Expected archive of assignment %d, got %d", assignment.ID, archive.AssignmentID)
+	}
+
+	if _, err := service.GetStudentArchive(studentAssignment.ID, otherStudent.ID); err == nil {
+		t.Error("Expected unassigned student to be denied the archive")
+	}
+
+	if err := service.DeleteArchive(assignment.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete archive: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(service.dir, fmt.Sprint(assignment.ID))); !os.IsNotExist(err) {
+		t.Errorf("Expected archive files to be removed, got %v", err)
+	}
+	if _, err := service.GetStudentArchive(studentAssignment.ID, student.ID); err == nil {
+		t.Error("Expected deleted archive to be gone")
+	}
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 	"zipcodereader/models"
 
@@ -10,7 +13,8 @@ import (
 
 // AssignmentService handles business logic for assignments
 type AssignmentService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	clientIP string // recorded in the audit log
 }
 
 // NewAssignmentService creates a new assignment service
@@ -18,6 +22,13 @@ func NewAssignmentService(db *gorm.DB) *AssignmentService {
 	return &AssignmentService{db: db}
 }
 
+// WithClientIP returns a copy of the service that records the given address in the audit log
+func (s *AssignmentService) WithClientIP(ip string) *AssignmentService {
+	scoped := *s
+	scoped.clientIP = ip
+	return &scoped
+}
+
 // GetDB returns the database instance
 func (s *AssignmentService) GetDB() *gorm.DB {
 	return s.db
@@ -25,11 +36,17 @@ func (s *AssignmentService) GetDB() *gorm.DB {
 
 // CreateAssignmentInput represents input for creating an assignment
 type CreateAssignmentInput struct {
-	Title       string
-	Description string
-	URL         string
-	Category    string
-	DueDate     *time.Time
+	Title              string
+	Description        string
+	URL                string
+	Category           string
+	DueDate            *time.Time
+	PublishAt          *time.Time
+	UnpublishAt        *time.Time
+	GracePeriodMinutes int
+	MinReflectionWords int // words of reflection required before students can complete
+	Resources          []ResourceInput
+	FileID             *uint // uploaded document assigned instead of a URL
 }
 
 // CreateAssignment creates a new assignment with validation
@@ -49,16 +66,86 @@ func (s *AssignmentService) CreateAssignment(instructorID uint, input CreateAssi
 		return nil, errors.New("title is required")
 	}
 
+	// Uploaded documents are served by the application
+	var file *models.UploadedFile
+	if input.FileID != nil {
+		var err error
+		if file, err = checkFileOwner(s.db, *input.FileID, instructorID); err != nil {
+			return nil, err
+		}
+		input.URL = models.UploadedFilePath(file.ID)
+	}
+
+	// Multi-resource assignments link to their first resource
+	if input.URL == "" && len(input.Resources) > 0 {
+		input.URL = input.Resources[0].URL
+	}
+
 	if input.URL == "" {
 		return nil, errors.New("URL is required")
 	}
 
-	// Create assignment
-	assignment, err := models.CreateAssignment(s.db, input.Title, input.Description, input.URL, input.Category, input.DueDate, instructorID)
+	if err := validateSchedule(input.PublishAt, input.UnpublishAt); err != nil {
+		return nil, err
+	}
+
+	if input.GracePeriodMinutes < 0 {
+		return nil, errors.New("grace period cannot be negative")
+	}
+
+	if input.MinReflectionWords < 0 {
+		return nil, errors.New("reflection length cannot be negative")
+	}
+
+	if err := validateResources(input.Resources); err != nil {
+		return nil, err
+	}
+
+	// The assignment and its settings are saved together so a failure leaves nothing behind
+	var assignment *models.Assignment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		assignment, err = models.CreateScheduledAssignment(tx, input.Title, input.Description, input.URL, input.Category, input.DueDate, input.PublishAt, input.UnpublishAt, instructorID)
+		if err != nil {
+			return err
+		}
+
+		if input.GracePeriodMinutes > 0 {
+			if err := assignment.UpdateLatePolicy(tx, input.GracePeriodMinutes); err != nil {
+				return err
+			}
+		}
+
+		if input.MinReflectionWords > 0 {
+			if err := assignment.UpdateReflectionRequirement(tx, input.MinReflectionWords); err != nil {
+				return err
+			}
+		}
+
+		if file != nil {
+			if err := assignment.AttachFile(tx, file); err != nil {
+				return err
+			}
+			assignment.FileID = &file.ID
+			assignment.ContentHash = file.SHA256
+		}
+
+		if len(input.Resources) > 0 {
+			if err := createAssignmentResources(tx, assignment.ID, input.Resources); err != nil {
+				return err
+			}
+			if assignment.Resources, err = models.GetAssignmentResources(tx, assignment.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.auditAssignment(instructorID, models.AuditAssignmentCreated, assignment.ID, auditDiff(nil, auditFields(assignment)))
+
 	return assignment, nil
 }
 
@@ -111,13 +198,19 @@ func (s *AssignmentService) GetAssignmentsByInstructor(instructorID uint) ([]mod
 	return assignments, nil
 }
 
-// UpdateAssignmentInput represents input for updating an assignment
+// UpdateAssignmentInput represents input for updating an assignment.
+// The schedule, late policy and reflection fields are left unchanged when nil;
+// a nil date inside a non-nil PublishAt or UnpublishAt clears it.
 type UpdateAssignmentInput struct {
-	Title       string
-	Description string
-	URL         string
-	Category    string
-	DueDate     *time.Time
+	Title              string
+	Description        string
+	URL                string
+	Category           string
+	DueDate            *time.Time
+	PublishAt          **time.Time
+	UnpublishAt        **time.Time
+	GracePeriodMinutes *int
+	MinReflectionWords *int
 }
 
 // UpdateAssignment updates an existing assignment
@@ -141,8 +234,69 @@ func (s *AssignmentService) UpdateAssignment(assignmentID uint, instructorID uin
 		return errors.New("URL is required")
 	}
 
+	publishAt, unpublishAt := assignment.PublishAt, assignment.UnpublishAt
+	if input.PublishAt != nil {
+		publishAt = *input.PublishAt
+	}
+	if input.UnpublishAt != nil {
+		unpublishAt = *input.UnpublishAt
+	}
+	if err := validateSchedule(publishAt, unpublishAt); err != nil {
+		return err
+	}
+
+	if input.GracePeriodMinutes != nil && *input.GracePeriodMinutes < 0 {
+		return errors.New("grace period cannot be negative")
+	}
+
+	if input.MinReflectionWords != nil && *input.MinReflectionWords < 0 {
+		return errors.New("reflection length cannot be negative")
+	}
+
+	before := auditFields(assignment)
+
 	// Update assignment
-	return assignment.UpdateAssignment(s.db, input.Title, input.Description, input.URL, input.Category, input.DueDate)
+	if err := assignment.UpdateAssignment(s.db, input.Title, input.Description, input.URL, input.Category, input.DueDate); err != nil {
+		return err
+	}
+
+	if input.PublishAt != nil || input.UnpublishAt != nil {
+		if err := assignment.UpdateSchedule(s.db, publishAt, unpublishAt); err != nil {
+			return err
+		}
+	}
+
+	// Pointing the assignment at another URL replaces its uploaded document
+	if assignment.FileID != nil && input.URL != models.UploadedFilePath(*assignment.FileID) {
+		if err := assignment.AttachFile(s.db, nil); err != nil {
+			return err
+		}
+	}
+
+	if input.MinReflectionWords != nil {
+		if err := assignment.UpdateReflectionRequirement(s.db, *input.MinReflectionWords); err != nil {
+			return err
+		}
+	}
+
+	if input.GracePeriodMinutes != nil {
+		if err := assignment.UpdateLatePolicy(s.db, *input.GracePeriodMinutes); err != nil {
+			return err
+		}
+	}
+
+	if changes := auditDiff(before, auditFields(assignment)); len(changes) > 0 {
+		s.auditAssignment(instructorID, models.AuditAssignmentUpdated, assignment.ID, changes)
+	}
+	return nil
+}
+
+// validateSchedule ensures the release window is well formed
+func validateSchedule(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return errors.New("unpublish date must be after publish date")
+	}
+	return nil
 }
 
 // DeleteAssignment deletes an assignment
@@ -158,7 +312,12 @@ func (s *AssignmentService) DeleteAssignment(assignmentID uint, instructorID uin
 	}
 
 	// Delete assignment (soft delete)
-	return assignment.DeleteAssignment(s.db)
+	if err := assignment.DeleteAssignment(s.db); err != nil {
+		return err
+	}
+
+	s.auditAssignment(instructorID, models.AuditAssignmentDeleted, assignment.ID, auditDiff(auditFields(assignment), nil))
+	return nil
 }
 
 // AssignToStudent assigns an assignment to a student
@@ -190,8 +349,17 @@ func (s *AssignmentService) AssignToStudent(assignmentID uint, studentID uint, i
 	}
 
 	// Create student assignment
-	_, err = models.CreateStudentAssignment(s.db, assignmentID, studentID)
-	return err
+	if _, err = models.CreateStudentAssignment(s.db, assignmentID, studentID); err != nil {
+		return err
+	}
+
+	s.auditStudents(instructorID, models.AuditStudentAssigned, assignmentID, []uint{studentID}, nil)
+
+	// The students are assigned either way, so a failed notice must not report the assignment as failed
+	if err := s.flagPriorCompletions(assignment, []uint{studentID}); err != nil {
+		log.Printf("failed to flag prior completions for assignment %d: %v", assignment.ID, err)
+	}
+	return nil
 }
 
 // AssignToMultipleStudents assigns an assignment to multiple students
@@ -230,7 +398,17 @@ func (s *AssignmentService) AssignToMultipleStudents(assignmentID uint, studentI
 	}
 
 	// Bulk create student assignments
-	return models.BulkCreateStudentAssignments(s.db, assignmentID, validStudentIDs)
+	if err := models.BulkCreateStudentAssignments(s.db, assignmentID, validStudentIDs); err != nil {
+		return err
+	}
+
+	s.auditStudents(instructorID, models.AuditStudentAssigned, assignmentID, validStudentIDs, nil)
+
+	// The students are assigned either way, so a failed notice must not report the assignment as failed
+	if err := s.flagPriorCompletions(assignment, validStudentIDs); err != nil {
+		log.Printf("failed to flag prior completions for assignment %d: %v", assignment.ID, err)
+	}
+	return nil
 }
 
 // RemoveStudentAssignment removes a student assignment
@@ -246,7 +424,81 @@ func (s *AssignmentService) RemoveStudentAssignment(assignmentID uint, studentID
 	}
 
 	// Remove student assignment
-	return models.RemoveStudentAssignment(s.db, assignmentID, studentID)
+	if err := models.RemoveStudentAssignment(s.db, assignmentID, studentID); err != nil {
+		return err
+	}
+
+	s.auditStudents(instructorID, models.AuditStudentRemoved, assignmentID, []uint{studentID}, nil)
+	return nil
+}
+
+// GrantExtension sets a per-student due date for an assignment; a nil due date removes the extension
+func (s *AssignmentService) GrantExtension(assignmentID uint, studentID uint, instructorID uint, dueDate *time.Time) (*models.StudentAssignment, error) {
+	studentAssignment, err := s.getOwnedStudentAssignment(assignmentID, studentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditTime(studentAssignment.DueDateOverride)
+	if err := studentAssignment.SetDueDateOverride(s.db, dueDate); err != nil {
+		return nil, err
+	}
+
+	if after := auditTime(dueDate); before != after {
+		changes := map[string]models.AuditChange{"due_date_override": {Before: before, After: after}}
+		s.auditStudents(instructorID, models.AuditExtensionChanged, assignmentID, []uint{studentID}, changes)
+	}
+
+	return studentAssignment, nil
+}
+
+// SetExcused excuses a student from an assignment's due date, or revokes the excuse
+func (s *AssignmentService) SetExcused(assignmentID uint, studentID uint, instructorID uint, excused bool) (*models.StudentAssignment, error) {
+	studentAssignment, err := s.getOwnedStudentAssignment(assignmentID, studentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := studentAssignment.Excused
+	if err := studentAssignment.SetExcused(s.db, excused); err != nil {
+		return nil, err
+	}
+
+	if before != excused {
+		changes := map[string]models.AuditChange{"excused": {Before: before, After: excused}}
+		s.auditStudents(instructorID, models.AuditExcuseChanged, assignmentID, []uint{studentID}, changes)
+	}
+
+	return studentAssignment, nil
+}
+
+// auditAssignment records an instructor's change to one of their assignments
+func (s *AssignmentService) auditAssignment(instructorID uint, action string, assignmentID uint, changes map[string]models.AuditChange) {
+	recordAudit(s.db, assignmentAuditEvent(instructorID, action, assignmentID, changes, s.clientIP))
+}
+
+// auditStudents records an instructor's change to students' place on one of their assignments, one event per student
+func (s *AssignmentService) auditStudents(instructorID uint, action string, assignmentID uint, studentIDs []uint, changes map[string]models.AuditChange) {
+	recordAudit(s.db, studentAuditEvents(instructorID, action, assignmentID, studentIDs, changes, s.clientIP)...)
+}
+
+// getOwnedStudentAssignment loads a student assignment after validating the instructor owns the assignment
+func (s *AssignmentService) getOwnedStudentAssignment(assignmentID uint, studentID uint, instructorID uint) (*models.StudentAssignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	studentAssignment, err := models.GetStudentAssignment(s.db, assignmentID, studentID)
+	if err != nil {
+		return nil, errors.New("assignment not assigned to this student")
+	}
+
+	return studentAssignment, nil
 }
 
 // GetAssignmentProgress gets progress statistics for an assignment
@@ -265,6 +517,11 @@ func (s *AssignmentService) GetAssignmentProgress(assignmentID uint, instructorI
 	return models.GetAssignmentProgress(s.db, assignmentID)
 }
 
+// GetInstructorProgress counts the student assignments in each status across all of an instructor's assignments
+func (s *AssignmentService) GetInstructorProgress(instructorID uint) (map[string]int, error) {
+	return models.GetInstructorStatusCounts(s.db, instructorID)
+}
+
 // GetAssignmentStudents gets all students assigned to an assignment
 func (s *AssignmentService) GetAssignmentStudents(assignmentID uint, instructorID uint) ([]models.StudentAssignment, error) {
 	// Validate assignment exists and instructor owns it
@@ -331,3 +588,156 @@ func (s *AssignmentService) GetAllStudents(instructorID uint) ([]models.User, er
 
 	return students, nil
 }
+
+// Duplicate match kinds
+const (
+	DuplicateMatchURL     = "url"
+	DuplicateMatchContent = "content"
+)
+
+// DuplicateAssignment describes another assignment of the same reading
+type DuplicateAssignment struct {
+	AssignmentID   uint   `json:"assignment_id"`
+	Title          string `json:"title"`
+	InstructorName string `json:"instructor_name"`
+	MatchedBy      string `json:"matched_by"`     // url or content
+	Link           string `json:"link,omitempty"` // set for the instructor's own assignments
+}
+
+// DuplicateReport lists the assignments sharing a reading and the assigned students who
+// already completed it elsewhere
+type DuplicateReport struct {
+	Duplicates       []DuplicateAssignment    `json:"duplicates"`
+	PriorCompletions []models.PriorCompletion `json:"prior_completions"`
+}
+
+// FindDuplicates returns the other assignments, from any instructor, with the same
+// normalized URL or content as the assignment
+func (s *AssignmentService) FindDuplicates(assignment *models.Assignment) ([]DuplicateAssignment, error) {
+	matches, err := models.FindDuplicateAssignments(s.db, assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]DuplicateAssignment, 0, len(matches))
+	for _, match := range matches {
+		duplicate := DuplicateAssignment{
+			AssignmentID:   match.ID,
+			Title:          match.Title,
+			InstructorName: match.CreatedBy.Username,
+			MatchedBy:      DuplicateMatchContent,
+		}
+		if assignment.NormalizedURL != "" && match.NormalizedURL == assignment.NormalizedURL {
+			duplicate.MatchedBy = DuplicateMatchURL
+		}
+		if match.CreatedByID == assignment.CreatedByID {
+			duplicate.Link = fmt.Sprintf("/instructor/assignments/%d/detail", match.ID)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, nil
+}
+
+// GetDuplicateReport returns the duplicates of an instructor's assignment and the
+// assigned students who already completed the same reading
+func (s *AssignmentService) GetDuplicateReport(assignmentID uint, instructorID uint) (*DuplicateReport, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	duplicates, err := s.FindDuplicates(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	studentAssignments, err := models.GetStudentAssignmentsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs := make([]uint, 0, len(studentAssignments))
+	for _, sa := range studentAssignments {
+		studentIDs = append(studentIDs, sa.StudentID)
+	}
+
+	completions, err := models.GetPriorCompletions(s.db, assignment, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DuplicateReport{Duplicates: duplicates, PriorCompletions: completions}, nil
+}
+
+// CheckDuplicateURLs reports existing assignments for each URL before they are created,
+// so imports of many readings can warn about repeats up front
+func (s *AssignmentService) CheckDuplicateURLs(instructorID uint, urls []string) (map[string][]DuplicateAssignment, error) {
+	results := make(map[string][]DuplicateAssignment)
+	for _, url := range urls {
+		candidate := &models.Assignment{URL: url, NormalizedURL: models.NormalizeURL(url), CreatedByID: instructorID}
+		if candidate.NormalizedURL == "" {
+			continue
+		}
+
+		duplicates, err := s.FindDuplicates(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			results[url] = duplicates
+		}
+	}
+
+	return results, nil
+}
+
+// flagPriorCompletions tells the instructor when newly assigned students already completed
+// the same reading under another assignment
+func (s *AssignmentService) flagPriorCompletions(assignment *models.Assignment, studentIDs []uint) error {
+	completions, err := models.GetPriorCompletions(s.db, assignment, studentIDs)
+	if err != nil || len(completions) == 0 {
+		return err
+	}
+
+	seen := make(map[uint]bool)
+	var names []string
+	for _, completion := range completions {
+		if !seen[completion.StudentID] {
+			seen[completion.StudentID] = true
+			names = append(names, completion.StudentName)
+		}
+	}
+
+	assignmentID := assignment.ID
+	message := fmt.Sprintf("📚 Already completed the reading for '%s' in another assignment: %s", assignment.Title, strings.Join(names, ", "))
+	_, err = models.CreateNotification(s.db, assignment.CreatedByID, models.NotificationPriorCompletion, message, &assignmentID)
+	return err
+}
+
+// authorizeAssignmentMember checks that the user takes part in an assignment: the instructor
+// who created it moderates, and students join once it is assigned to them and published
+func authorizeAssignmentMember(db *gorm.DB, assignmentID uint, user *models.User) (*models.Assignment, bool, error) {
+	assignment, err := models.GetAssignmentByID(db, assignmentID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if user.IsInstructor() {
+		if assignment.CreatedByID != user.ID {
+			return nil, false, errors.New("access denied")
+		}
+		return assignment, true, nil
+	}
+
+	if _, err := models.GetStudentAssignment(db, assignmentID, user.ID); err != nil {
+		return nil, false, errors.New("access denied")
+	}
+	if !assignment.IsPublished(time.Now()) {
+		return nil, false, errors.New("access denied")
+	}
+	return assignment, false, nil
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// ResourceInput represents one typed resource of an assignment; a zero ID creates a new resource
+type ResourceInput struct {
+	ID               uint
+	Type             string
+	Title            string
+	URL              string
+	EstimatedMinutes int
+	Optional         bool
+}
+
+// ResourceProgress is a resource together with the student's completion state
+type ResourceProgress struct {
+	models.AssignmentResource
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// validateResources checks resource types and required fields
+func validateResources(resources []ResourceInput) error {
+	for _, resource := range resources {
+		if !models.IsValidResourceType(resource.Type) {
+			return errors.New("resource type must be one of article, video, pdf, repository, book_chapter")
+		}
+
+		if resource.Title == "" || resource.URL == "" {
+			return errors.New("resource title and URL are required")
+		}
+
+		if resource.EstimatedMinutes < 0 {
+			return errors.New("resource duration cannot be negative")
+		}
+	}
+	return nil
+}
+
+// SetAssignmentResources replaces an assignment's resources. Resources keep their student
+// progress when their ID is included; omitted resources are removed.
+func (s *AssignmentService) SetAssignmentResources(assignmentID uint, instructorID uint, resources []ResourceInput) ([]models.AssignmentResource, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	if err := validateResources(resources); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		existing := make(map[uint]bool, len(assignment.Resources))
+		for _, resource := range assignment.Resources {
+			existing[resource.ID] = true
+		}
+
+		kept := make(map[uint]bool)
+		for position, input := range resources {
+			resource := models.AssignmentResource{
+				AssignmentID:     assignmentID,
+				Type:             input.Type,
+				Title:            input.Title,
+				URL:              input.URL,
+				EstimatedMinutes: input.EstimatedMinutes,
+				Optional:         input.Optional,
+				Position:         position,
+			}
+
+			if input.ID != 0 {
+				if !existing[input.ID] {
+					return errors.New("resource not found")
+				}
+				kept[input.ID] = true
+				err := tx.Model(&models.AssignmentResource{ID: input.ID}).Updates(map[string]interface{}{
+					"type":              resource.Type,
+					"title":             resource.Title,
+					"url":               resource.URL,
+					"estimated_minutes": resource.EstimatedMinutes,
+					"optional":          resource.Optional,
+					"position":          resource.Position,
+				}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Create(&resource).Error; err != nil {
+				return err
+			}
+		}
+
+		for id := range existing {
+			if kept[id] {
+				continue
+			}
+			if err := tx.Where("resource_id = ?", id).Delete(&models.StudentResourceProgress{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.AssignmentResource{}, id).Error; err != nil {
+				return err
+			}
+		}
+
+		// Changing which resources are required can complete unfinished student work.
+		// Work already completed stays completed even if a new required resource was added.
+		studentAssignments, err := models.GetStudentAssignmentsByAssignment(tx, assignmentID)
+		if err != nil {
+			return err
+		}
+		for i := range studentAssignments {
+			if studentAssignments[i].IsCompleted() {
+				continue
+			}
+			if err := rollUpResourceStatus(tx, &studentAssignments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentResources(s.db, assignmentID)
+}
+
+// GetResourceProgress returns an assignment's resources with the student's completion state
+func (s *StudentAssignmentService) GetResourceProgress(studentAssignmentID uint, studentID uint) ([]ResourceProgress, error) {
+	studentAssignment, err := s.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := models.GetAssignmentResources(s.db, studentAssignment.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := models.GetCompletedResourceIDs(s.db, studentAssignment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]ResourceProgress, 0, len(resources))
+	for _, resource := range resources {
+		item := ResourceProgress{AssignmentResource: resource}
+		if completedAt, ok := completed[resource.ID]; ok {
+			item.Completed = true
+			item.CompletedAt = &completedAt
+		}
+		progress = append(progress, item)
+	}
+
+	return progress, nil
+}
+
+// SetResourceCompleted marks a single resource done or not done and rolls the
+// assignment status up from the student's resource progress
+func (s *StudentAssignmentService) SetResourceCompleted(studentAssignmentID uint, resourceID uint, studentID uint, completed bool) (*models.StudentAssignment, error) {
+	studentAssignment, err := s.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource models.AssignmentResource
+	if err := s.db.Where("id = ? AND assignment_id = ?", resourceID, studentAssignment.AssignmentID).First(&resource).Error; err != nil {
+		return nil, errors.New("resource not found")
+	}
+
+	if completed {
+		if err := checkReadingListUnlocked(s.db, studentAssignment.AssignmentID, studentID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := models.SetResourceCompleted(tx, studentAssignment.ID, resource.ID, completed); err != nil {
+			return err
+		}
+		return rollUpResourceStatus(tx, studentAssignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return studentAssignment, nil
+}
+
+// rollUpResourceStatus derives a student assignment's status from its resource progress.
+// Assignments without resources are left alone.
+func rollUpResourceStatus(db *gorm.DB, studentAssignment *models.StudentAssignment) error {
+	resources, err := models.GetAssignmentResources(db, studentAssignment.AssignmentID)
+	if err != nil || len(resources) == 0 {
+		return err
+	}
+
+	completed, err := models.GetCompletedResourceIDs(db, studentAssignment.ID)
+	if err != nil {
+		return err
+	}
+
+	status := studentAssignment.Status
+	switch {
+	case models.ResourcesComplete(resources, completed):
+		status = models.StatusCompleted
+	case len(completed) > 0 || studentAssignment.Status == models.StatusCompleted:
+		status = models.StatusInProgress
+	}
+
+	if status == studentAssignment.Status {
+		return nil
+	}
+
+	err = studentAssignment.UpdateStatus(db, status)
+	if models.IsCompletionRequirementError(err) {
+		// Finishing the resources leaves the reflection or quiz before the assignment completes
+		status = models.StatusInProgress
+		if status == studentAssignment.Status {
+			return nil
+		}
+		err = studentAssignment.UpdateStatus(db, status)
+	}
+	if err != nil {
+		return err
+	}
+	studentAssignment.Status = status
+	evaluateAchievements(db, studentAssignment)
+	return nil
+}
+
+// createAssignmentResources adds the initial resources of a new assignment
+func createAssignmentResources(db *gorm.DB, assignmentID uint, resources []ResourceInput) error {
+	for position, input := range resources {
+		resource := models.AssignmentResource{
+			AssignmentID:     assignmentID,
+			Type:             input.Type,
+			Title:            input.Title,
+			URL:              input.URL,
+			EstimatedMinutes: input.EstimatedMinutes,
+			Optional:         input.Optional,
+			Position:         position,
+		}
+		if err := db.Create(&resource).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
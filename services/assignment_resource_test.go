@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestResourceCompletionRollsUp(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Concurrency Week",
+		Resources: []ResourceInput{
+			{Type: models.ResourceArticle, Title: "Blog Post", URL: "https://go.dev/blog/pipelines", EstimatedMinutes: 20},
+			{Type: models.ResourceVideo, Title: "Talk", URL: "https://example.com/talk", EstimatedMinutes: 30},
+			{Type: models.ResourceRepository, Title: "Examples", URL: "https://example.com/repo", Optional: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if assignment.URL != "https://go.dev/blog/pipelines" {
+		t.Errorf("Expected URL to default to first resource, got %s", assignment.URL)
+	}
+	if len(assignment.Resources) != 3 {
+		t.Fatalf("Expected 3 resources, got %d", len(assignment.Resources))
+	}
+
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to load student assignment: %v", err)
+	}
+
+	article, video, repo := assignment.Resources[0], assignment.Resources[1], assignment.Resources[2]
+
+	updated, err := studentService.SetResourceCompleted(sa.ID, article.ID, student.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to complete resource: %v", err)
+	}
+	if updated.Status != models.StatusInProgress {
+		t.Errorf("Expected in_progress after first resource, got %s", updated.Status)
+	}
+
+	// Completing directly is refused until the required resources are finished
+	err = studentService.MarkAsCompletedByID(sa.ID, student.ID)
+	if !errors.Is(err, models.ErrResourcesIncomplete) {
+		t.Errorf("Expected completion to wait for the video, got %v", err)
+	}
+
+	// The optional repository is not needed to finish the assignment
+	updated, err = studentService.SetResourceCompleted(sa.ID, video.ID, student.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to complete resource: %v", err)
+	}
+	if updated.Status != models.StatusCompleted {
+		t.Errorf("Expected completed after required resources, got %s", updated.Status)
+	}
+
+	updated, err = studentService.SetResourceCompleted(sa.ID, video.ID, student.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to unmark resource: %v", err)
+	}
+	if updated.Status != models.StatusInProgress {
+		t.Errorf("Expected in_progress after unmarking, got %s", updated.Status)
+	}
+
+	// Dropping the video from the assignment leaves only the finished article required
+	_, err = assignmentService.SetAssignmentResources(assignment.ID, instructor.ID, []ResourceInput{
+		{ID: article.ID, Type: article.Type, Title: article.Title, URL: article.URL},
+		{ID: repo.ID, Type: repo.Type, Title: repo.Title, URL: repo.URL, Optional: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update resources: %v", err)
+	}
+
+	progress, err := studentService.GetResourceProgress(sa.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get resource progress: %v", err)
+	}
+	if len(progress) != 2 || !progress[0].Completed {
+		t.Errorf("Expected article progress to be kept, got %+v", progress)
+	}
+
+	reloaded, _ := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if reloaded.Status != models.StatusCompleted {
+		t.Errorf("Expected status to roll up to completed after removing the video, got %s", reloaded.Status)
+	}
+
+	// Adding a required resource later does not reopen completed work
+	_, err = assignmentService.SetAssignmentResources(assignment.ID, instructor.ID, []ResourceInput{
+		{ID: article.ID, Type: article.Type, Title: article.Title, URL: article.URL},
+		{ID: repo.ID, Type: repo.Type, Title: repo.Title, URL: repo.URL, Optional: true},
+		{Type: models.ResourcePDF, Title: "Notes", URL: "https://example.com/notes.pdf"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update resources: %v", err)
+	}
+	reloaded, _ = models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if reloaded.Status != models.StatusCompleted || reloaded.CompletedAt == nil {
+		t.Errorf("Expected the completed assignment to stay completed, got %s", reloaded.Status)
+	}
+}
+
+func TestCreateAssignmentInvalidResource(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	_, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:     "Podcast",
+		Resources: []ResourceInput{{Type: "podcast", Title: "Episode", URL: "https://example.com/ep"}},
+	})
+	if err == nil {
+		t.Error("Expected error for unsupported resource type")
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 	"time"
 	"zipcodereader/models"
@@ -10,14 +11,14 @@ import (
 )
 
 // setupTestDB creates a test database for testing
-func setupTestDB(t *testing.T) *gorm.DB {
+func setupTestDB(t testing.TB) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 
 	// Auto-migrate models
-	err = db.AutoMigrate(&models.User{}, &models.Assignment{}, &models.StudentAssignment{})
+	err = db.AutoMigrate(models.All()...)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -26,7 +27,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 }
 
 // createTestUser creates a test user
-func createTestUser(t *testing.T, db *gorm.DB, username, role string) *models.User {
+func createTestUser(t testing.TB, db *gorm.DB, username, role string) *models.User {
 	user := &models.User{
 		Username: username,
 		Email:    username + "@example.com",
@@ -41,6 +42,24 @@ func createTestUser(t *testing.T, db *gorm.DB, username, role string) *models.Us
 	return user
 }
 
+// createScheduledStudentAssignment assigns a student a reading that is not published until tomorrow
+func createScheduledStudentAssignment(t testing.TB, db *gorm.DB, instructor, student *models.User) (*models.Assignment, *models.StudentAssignment) {
+	publishAt := time.Now().AddDate(0, 0, 1)
+	assignmentService := NewAssignmentService(db)
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Next Week", URL: "https://example.com/next-week", PublishAt: &publishAt})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+	return assignment, sa
+}
+
 func TestCreateAssignment(t *testing.T) {
 	db := setupTestDB(t)
 	service := NewAssignmentService(db)
@@ -344,6 +363,66 @@ func TestUpdateAssignment(t *testing.T) {
 	}
 }
 
+func TestUpdateAssignmentKeepsOmittedSettings(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	publishAt := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	unpublishAt := publishAt.AddDate(0, 0, 7)
+	assignment, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:              "Essay",
+		URL:                "https://example.com/essay",
+		PublishAt:          &publishAt,
+		UnpublishAt:        &unpublishAt,
+		GracePeriodMinutes: 30,
+		MinReflectionWords: 50,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	if err := service.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{Title: "Essay, revised", URL: "https://example.com/essay"}); err != nil {
+		t.Fatalf("Failed to update assignment: %v", err)
+	}
+	updated, err := models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get assignment: %v", err)
+	}
+	if updated.Title != "Essay, revised" || updated.GracePeriodMinutes != 30 || updated.MinReflectionWords != 50 {
+		t.Errorf("Expected omitted settings to be kept, got %+v", updated)
+	}
+	if updated.PublishAt == nil || !updated.PublishAt.Equal(publishAt) || updated.UnpublishAt == nil || !updated.UnpublishAt.Equal(unpublishAt) {
+		t.Errorf("Expected the release window to be kept, got %v to %v", updated.PublishAt, updated.UnpublishAt)
+	}
+
+	// Sent values are applied, and a nil date clears that end of the window
+	var cleared *time.Time
+	grace := 0
+	if err := service.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", PublishAt: &cleared, GracePeriodMinutes: &grace}); err != nil {
+		t.Fatalf("Failed to update assignment: %v", err)
+	}
+	updated, err = models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get assignment: %v", err)
+	}
+	if updated.PublishAt != nil || updated.UnpublishAt == nil || updated.GracePeriodMinutes != 0 || updated.MinReflectionWords != 50 {
+		t.Errorf("Expected only the sent settings to change, got %+v", updated)
+	}
+
+	// The window is validated against the dates it keeps
+	early := publishAt.AddDate(0, 0, -2)
+	earlyPtr := &early
+	if err := service.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", PublishAt: &earlyPtr}); err != nil {
+		t.Fatalf("Expected a publish date before the kept unpublish date to be accepted: %v", err)
+	}
+	late := unpublishAt.AddDate(0, 0, 1)
+	latePtr := &late
+	if err := service.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", PublishAt: &latePtr}); err == nil {
+		t.Error("Expected a publish date after the kept unpublish date to be rejected")
+	}
+}
+
 func TestDeleteAssignment(t *testing.T) {
 	db := setupTestDB(t)
 	service := NewAssignmentService(db)
@@ -552,3 +631,248 @@ func TestGetAllStudents(t *testing.T) {
 		}
 	}
 }
+
+func TestGrantExtension(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	dueDate := time.Now().Add(-24 * time.Hour)
+	assignment, _ := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:   "Test Assignment",
+		URL:     "https://example.com",
+		DueDate: &dueDate,
+	})
+	service.AssignToStudent(assignment.ID, student.ID, instructor.ID)
+
+	extended := time.Now().AddDate(0, 0, 3)
+	sa, err := service.GrantExtension(assignment.ID, student.ID, instructor.ID, &extended)
+	if err != nil {
+		t.Fatalf("Failed to grant extension: %v", err)
+	}
+	if sa.IsOverdue() {
+		t.Error("Expected extended assignment not to be overdue")
+	}
+
+	// Only the owning instructor may grant extensions
+	_, err = service.GrantExtension(assignment.ID, student.ID, otherInstructor.ID, &extended)
+	if err == nil {
+		t.Error("Expected error when another instructor grants an extension")
+	}
+
+	// Removing the extension restores the original due date
+	sa, err = service.GrantExtension(assignment.ID, student.ID, instructor.ID, nil)
+	if err != nil {
+		t.Fatalf("Failed to remove extension: %v", err)
+	}
+	if !sa.IsOverdue() {
+		t.Error("Expected assignment to be overdue after removing the extension")
+	}
+
+	sa, err = service.SetExcused(assignment.ID, student.ID, instructor.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to excuse student: %v", err)
+	}
+	if sa.IsOverdue() {
+		t.Error("Expected excused assignment not to be overdue")
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	coTeacher := createTestUser(t, db, "instructor2", "instructor")
+
+	original, err := service.CreateAssignment(coTeacher.ID, CreateAssignmentInput{
+		Title: "Effective Go",
+		URL:   "https://go.dev/doc/effective_go",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	own, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Style reading",
+		URL:   "http://www.go.dev/doc/effective_go/?utm_source=newsletter",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	again, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Go idioms",
+		URL:   "https://go.dev/doc/effective_go#names",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	duplicates, err := service.FindDuplicates(again)
+	if err != nil {
+		t.Fatalf("Failed to find duplicates: %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Fatalf("Expected 2 duplicates, got %d", len(duplicates))
+	}
+	if duplicates[0].AssignmentID != original.ID || duplicates[0].InstructorName != "instructor2" || duplicates[0].Link != "" {
+		t.Errorf("Expected co-teacher's assignment without a link, got %+v", duplicates[0])
+	}
+	if duplicates[1].AssignmentID != own.ID || duplicates[1].MatchedBy != DuplicateMatchURL || duplicates[1].Link == "" {
+		t.Errorf("Expected own assignment matched by URL with a link, got %+v", duplicates[1])
+	}
+
+	// The same article text at another URL is matched by content
+	other, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Mirror",
+		URL:   "https://mirror.example.com/effective-go",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	db.Model(&models.Assignment{}).Where("id IN ?", []uint{original.ID, other.ID}).Update("content_hash", "abc123")
+	other.ContentHash = "abc123"
+
+	duplicates, err = service.FindDuplicates(other)
+	if err != nil {
+		t.Fatalf("Failed to find duplicates: %v", err)
+	}
+	if len(duplicates) != 1 || duplicates[0].AssignmentID != original.ID || duplicates[0].MatchedBy != DuplicateMatchContent {
+		t.Errorf("Expected a content match with the original, got %+v", duplicates)
+	}
+
+	checked, err := service.CheckDuplicateURLs(instructor.ID, []string{"https://go.dev/doc/effective_go", "https://example.com/new"})
+	if err != nil {
+		t.Fatalf("Failed to check URLs: %v", err)
+	}
+	if len(checked) != 1 || len(checked["https://go.dev/doc/effective_go"]) != 3 {
+		t.Errorf("Expected only the known URL to be reported with 3 duplicates, got %+v", checked)
+	}
+}
+
+func TestPriorCompletionsAreFlagged(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	reader := createTestUser(t, db, "student1", "student")
+	newcomer := createTestUser(t, db, "student2", "student")
+
+	first, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Week 1", URL: "https://example.com/article"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := service.AssignToStudent(first.ID, reader.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, first.ID, reader.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+	if err := sa.MarkAsCompleted(db); err != nil {
+		t.Fatalf("Failed to complete assignment: %v", err)
+	}
+
+	second, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Week 5 review", URL: "https://www.example.com/article/"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := service.AssignToMultipleStudents(second.ID, []uint{reader.ID, newcomer.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	report, err := service.GetDuplicateReport(second.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get duplicate report: %v", err)
+	}
+	if len(report.Duplicates) != 1 || report.Duplicates[0].AssignmentID != first.ID {
+		t.Errorf("Expected the first assignment as duplicate, got %+v", report.Duplicates)
+	}
+	if len(report.PriorCompletions) != 1 || report.PriorCompletions[0].StudentID != reader.ID || report.PriorCompletions[0].AssignmentID != first.ID {
+		t.Errorf("Expected only student1's earlier completion, got %+v", report.PriorCompletions)
+	}
+
+	notifications, err := models.GetNotificationsByUser(db, instructor.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+	flagged := 0
+	for _, notification := range notifications {
+		if notification.Type == models.NotificationPriorCompletion {
+			flagged++
+			if !strings.Contains(notification.Message, "student1") || strings.Contains(notification.Message, "student2") {
+				t.Errorf("Expected only student1 to be flagged, got %q", notification.Message)
+			}
+		}
+	}
+	if flagged != 1 {
+		t.Errorf("Expected 1 prior completion notification, got %d", flagged)
+	}
+
+	if _, err := service.GetDuplicateReport(second.ID, newcomer.ID); err == nil {
+		t.Error("Expected other users to be denied the report")
+	}
+}
+
+func TestAssignSucceedsWhenPriorCompletionNoticeFails(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	reader := createTestUser(t, db, "student1", "student")
+
+	first, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Week 1", URL: "https://example.com/article"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := service.AssignToStudent(first.ID, reader.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, _ := models.GetStudentAssignment(db, first.ID, reader.ID)
+	if err := sa.MarkAsCompleted(db); err != nil {
+		t.Fatalf("Failed to complete assignment: %v", err)
+	}
+
+	// Without a notifications table the prior completion notice cannot be sent
+	if err := db.Migrator().DropTable(&models.Notification{}); err != nil {
+		t.Fatalf("Failed to drop notifications: %v", err)
+	}
+	second, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Week 5 review", URL: "https://example.com/article"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := service.AssignToMultipleStudents(second.ID, []uint{reader.ID}, instructor.ID); err != nil {
+		t.Errorf("Expected the assignment to succeed without the notice, got %v", err)
+	}
+	if _, err := models.GetStudentAssignment(db, second.ID, reader.ID); err != nil {
+		t.Errorf("Expected the student to be assigned, got %v", err)
+	}
+}
+
+func TestCreateAssignmentRollsBackOnFailure(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	// Without a resources table the assignment cannot be saved in full
+	if err := db.Migrator().DropTable(&models.AssignmentResource{}); err != nil {
+		t.Fatalf("Failed to drop resources: %v", err)
+	}
+	_, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:              "Pipelines",
+		GracePeriodMinutes: 30,
+		MinReflectionWords: 50,
+		Resources:          []ResourceInput{{Type: models.ResourceArticle, Title: "Blog Post", URL: "https://go.dev/blog/pipelines"}},
+	})
+	if err == nil {
+		t.Fatal("Expected the assignment to fail without a resources table")
+	}
+
+	var count int64
+	if err := db.Model(&models.Assignment{}).Count(&count).Error; err != nil {
+		t.Fatalf("Failed to count assignments: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected a failed assignment to leave nothing behind, got %d assignments", count)
+	}
+}
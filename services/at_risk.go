@@ -0,0 +1,415 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// Risk levels
+const (
+	RiskLevelHigh   = "high"
+	RiskLevelMedium = "medium"
+	RiskLevelLow    = "low"
+)
+
+// Risk factors
+const (
+	RiskFactorOverdue    = "overdue"
+	RiskFactorNotStarted = "not_started"
+	RiskFactorTrend      = "declining_trend"
+	RiskFactorInactivity = "inactivity"
+)
+
+const (
+	// riskOverdueLimit is the number of overdue readings that maxes out the overdue factor
+	riskOverdueLimit = 3
+	// riskInactiveDays is the number of days without activity that maxes out the inactivity factor
+	riskInactiveDays = 14
+	// riskTrendMinimum is the number of past-due readings needed before the trend is judged
+	riskTrendMinimum = 4
+	// riskDigestInterval is how often an instructor receives the at-risk digest
+	riskDigestInterval = 7 * 24 * time.Hour
+	// riskDigestSize is the number of students named in a digest
+	riskDigestSize = 5
+)
+
+// RiskService scores how likely each student is to fall behind on an instructor's readings
+type RiskService struct {
+	db *gorm.DB
+}
+
+// NewRiskService creates a new risk service
+func NewRiskService(db *gorm.DB) *RiskService {
+	return &RiskService{db: db}
+}
+
+// RiskReport ranks an instructor's students from most to least at risk
+type RiskReport struct {
+	Settings    models.RiskSettings `json:"settings"`
+	Students    []AtRiskStudent     `json:"students"`
+	HighCount   int                 `json:"high_risk_count"`
+	MediumCount int                 `json:"medium_risk_count"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// AtRiskStudent is a student's risk score with the factors behind it
+type AtRiskStudent struct {
+	StudentID       uint         `json:"student_id"`
+	StudentName     string       `json:"student_name"`
+	StudentEmail    string       `json:"student_email"`
+	Score           float64      `json:"score"` // 0 to 100
+	Level           string       `json:"level"`
+	AssignmentCount int          `json:"assignment_count"`
+	OverdueCount    int          `json:"overdue_count"`
+	NotStartedCount int          `json:"not_started_count"`
+	LastActivityAt  *time.Time   `json:"last_activity_at"`
+	Factors         []RiskFactor `json:"factors"`
+}
+
+// RiskFactor explains how much one factor adds to a student's score
+type RiskFactor struct {
+	Name        string  `json:"name"`
+	Weight      int     `json:"weight"`
+	Value       float64 `json:"value"`  // 0 to 1
+	Points      float64 `json:"points"` // share of the score out of 100
+	Explanation string  `json:"explanation"`
+}
+
+// RiskSettingsInput holds the weights and digest preference an instructor can change
+type RiskSettingsInput struct {
+	OverdueWeight    int  `json:"overdue_weight"`
+	NotStartedWeight int  `json:"not_started_weight"`
+	TrendWeight      int  `json:"trend_weight"`
+	InactivityWeight int  `json:"inactivity_weight"`
+	WeeklyDigest     bool `json:"weekly_digest"`
+}
+
+// Start sends the weekly digests that are due on the given interval until stop is closed
+func (s *RiskService) Start(interval time.Duration, stop <-chan struct{}) {
+	runEvery("at-risk digest", interval, stop, func() error {
+		_, err := s.SendDigests(time.Now())
+		return err
+	})
+}
+
+// GetSettings returns an instructor's risk settings, or the defaults if they never saved any
+func (s *RiskService) GetSettings(instructorID uint) (*models.RiskSettings, error) {
+	settings, err := models.GetRiskSettings(s.db, instructorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaults := models.DefaultRiskSettings(instructorID)
+		return &defaults, nil
+	}
+	return settings, err
+}
+
+// SaveSettings validates and stores an instructor's risk weights and digest preference
+func (s *RiskService) SaveSettings(instructorID uint, input RiskSettingsInput) (*models.RiskSettings, error) {
+	for _, weight := range []int{input.OverdueWeight, input.NotStartedWeight, input.TrendWeight, input.InactivityWeight} {
+		if weight < 0 || weight > 100 {
+			return nil, errors.New("invalid weight: weights must be between 0 and 100")
+		}
+	}
+
+	settings, err := s.GetSettings(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	settings.OverdueWeight = input.OverdueWeight
+	settings.NotStartedWeight = input.NotStartedWeight
+	settings.TrendWeight = input.TrendWeight
+	settings.InactivityWeight = input.InactivityWeight
+	settings.WeeklyDigest = input.WeeklyDigest
+	if settings.TotalWeight() == 0 {
+		return nil, errors.New("invalid weights: at least one weight must be above 0")
+	}
+
+	if err := models.SaveRiskSettings(s.db, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetAtRiskReport scores every student assigned one of the instructor's published readings
+func (s *RiskService) GetAtRiskReport(instructorID uint, now time.Time) (*RiskReport, error) {
+	settings, err := s.GetSettings(instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentAssignments, err := models.GetStudentAssignmentsByInstructor(s.db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	readingActivity, err := models.GetLastReadingActivity(s.db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Readings students cannot see yet do not count against them
+	byStudent := make(map[uint][]models.StudentAssignment)
+	var order []uint
+	for _, sa := range studentAssignments {
+		if !sa.Assignment.IsPublished(now) {
+			continue
+		}
+		if _, seen := byStudent[sa.StudentID]; !seen {
+			order = append(order, sa.StudentID)
+		}
+		byStudent[sa.StudentID] = append(byStudent[sa.StudentID], sa)
+	}
+
+	report := &RiskReport{Settings: *settings, Students: []AtRiskStudent{}, GeneratedAt: now}
+	for _, studentID := range order {
+		var lastReading *time.Time
+		if at, ok := readingActivity[studentID]; ok {
+			lastReading = &at
+		}
+		student := scoreStudent(byStudent[studentID], lastReading, settings, now)
+		switch student.Level {
+		case RiskLevelHigh:
+			report.HighCount++
+		case RiskLevelMedium:
+			report.MediumCount++
+		}
+		report.Students = append(report.Students, student)
+	}
+
+	sort.SliceStable(report.Students, func(i, j int) bool {
+		if report.Students[i].Score != report.Students[j].Score {
+			return report.Students[i].Score > report.Students[j].Score
+		}
+		return report.Students[i].StudentName < report.Students[j].StudentName
+	})
+
+	return report, nil
+}
+
+// SendDigests notifies every instructor who asked for the weekly digest and has not had one
+// in the past week, and returns the number of digests sent. Weeks with nobody at risk are
+// skipped quietly.
+func (s *RiskService) SendDigests(now time.Time) (int, error) {
+	due, err := models.GetRiskDigestsDue(s.db, now.Add(-riskDigestInterval))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range due {
+		report, err := s.GetAtRiskReport(due[i].InstructorID, now)
+		if err != nil {
+			return sent, err
+		}
+
+		if message := riskDigestMessage(report); message != "" {
+			if _, err := models.CreateNotification(s.db, due[i].InstructorID, models.NotificationAtRiskDigest, message, nil); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+
+		if err := due[i].MarkRiskDigestSent(s.db, now); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// riskDigestMessage names the students most at risk, or returns "" when nobody is
+func riskDigestMessage(report *RiskReport) string {
+	atRisk := report.HighCount + report.MediumCount
+	if atRisk == 0 {
+		return ""
+	}
+
+	var names []string
+	for _, student := range report.Students {
+		if student.Level == RiskLevelLow || len(names) == riskDigestSize {
+			break
+		}
+		names = append(names, fmt.Sprintf("%s (%.0f, %s)", student.StudentName, student.Score, student.Level))
+	}
+
+	noun := "students need"
+	if atRisk == 1 {
+		noun = "student needs"
+	}
+	message := fmt.Sprintf("⚠️ Weekly at-risk digest: %d %s attention: %s", atRisk, noun, strings.Join(names, ", "))
+	if more := atRisk - len(names); more > 0 {
+		message += fmt.Sprintf(" and %d more", more)
+	}
+	return message
+}
+
+// scoreStudent combines the risk factors of one student's readings into a weighted score
+func scoreStudent(studentAssignments []models.StudentAssignment, lastReading *time.Time, settings *models.RiskSettings, now time.Time) AtRiskStudent {
+	student := AtRiskStudent{AssignmentCount: len(studentAssignments), Factors: []RiskFactor{}}
+	if len(studentAssignments) > 0 {
+		student.StudentID = studentAssignments[0].StudentID
+		student.StudentName = studentAssignments[0].Student.Username
+		student.StudentEmail = studentAssignments[0].Student.Email
+	}
+
+	// Overdue readings
+	for i := range studentAssignments {
+		if studentAssignments[i].IsPastDue(now) && !studentAssignments[i].IsCompleted() {
+			student.OverdueCount++
+		}
+	}
+	factor := math.Min(float64(student.OverdueCount)/riskOverdueLimit, 1)
+	explanation := fmt.Sprintf("%d overdue reading%s", student.OverdueCount, plural(student.OverdueCount))
+	student.addFactor(RiskFactorOverdue, settings.OverdueWeight, settings.TotalWeight(), factor, explanation)
+
+	// Readings never started
+	counted := 0
+	for _, sa := range studentAssignments {
+		if sa.Excused {
+			continue
+		}
+		counted++
+		if sa.Status == models.StatusAssigned {
+			student.NotStartedCount++
+		}
+	}
+	factor = 0
+	if counted > 0 {
+		factor = float64(student.NotStartedCount) / float64(counted)
+	}
+	explanation = fmt.Sprintf("%d of %d readings not started", student.NotStartedCount, counted)
+	student.addFactor(RiskFactorNotStarted, settings.NotStartedWeight, settings.TotalWeight(), factor, explanation)
+
+	// Completion trend
+	factor, explanation = completionTrend(studentAssignments, now)
+	student.addFactor(RiskFactorTrend, settings.TrendWeight, settings.TotalWeight(), factor, explanation)
+
+	// Days since last activity
+	student.LastActivityAt = lastActivity(studentAssignments, lastReading)
+	factor, explanation = inactivity(studentAssignments, student.LastActivityAt, now)
+	student.addFactor(RiskFactorInactivity, settings.InactivityWeight, settings.TotalWeight(), factor, explanation)
+
+	for _, f := range student.Factors {
+		student.Score += f.Points
+	}
+	student.Score = math.Round(student.Score*10) / 10
+	switch {
+	case student.Score >= 60:
+		student.Level = RiskLevelHigh
+	case student.Score >= 30:
+		student.Level = RiskLevelMedium
+	default:
+		student.Level = RiskLevelLow
+	}
+
+	return student
+}
+
+// addFactor records a factor and the points it adds out of 100
+func (s *AtRiskStudent) addFactor(name string, weight, totalWeight int, value float64, explanation string) {
+	points := 0.0
+	if totalWeight > 0 {
+		points = math.Round(100*float64(weight)*value/float64(totalWeight)*10) / 10
+	}
+	s.Factors = append(s.Factors, RiskFactor{
+		Name:        name,
+		Weight:      weight,
+		Value:       math.Round(value*100) / 100,
+		Points:      points,
+		Explanation: explanation,
+	})
+}
+
+// completionTrend compares the on-time completion rate of the older half of a student's
+// past-due readings with the newer half; only a decline adds risk
+func completionTrend(studentAssignments []models.StudentAssignment, now time.Time) (float64, string) {
+	var due []models.StudentAssignment
+	for _, sa := range studentAssignments {
+		if sa.IsPastDue(now) {
+			due = append(due, sa)
+		}
+	}
+	if len(due) < riskTrendMinimum {
+		return 0, fmt.Sprintf("Not enough past-due readings to judge a trend (%d of %d)", len(due), riskTrendMinimum)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].EffectiveDueDate().Before(*due[j].EffectiveDueDate())
+	})
+	half := len(due) / 2
+	earlier := onTimeRate(due[:half])
+	recent := onTimeRate(due[half:])
+
+	explanation := fmt.Sprintf("Completed %.0f%% of earlier readings on time and %.0f%% of the %d most recent", earlier*100, recent*100, len(due)-half)
+	return math.Max(earlier-recent, 0), explanation
+}
+
+// onTimeRate returns the share of readings completed by their due date
+func onTimeRate(studentAssignments []models.StudentAssignment) float64 {
+	onTime := 0
+	for _, sa := range studentAssignments {
+		if sa.IsCompleted() && sa.CompletionTiming == models.TimingOnTime {
+			onTime++
+		}
+	}
+	return float64(onTime) / float64(len(studentAssignments))
+}
+
+// lastActivity returns the latest time the student completed, opened or read one of their readings
+func lastActivity(studentAssignments []models.StudentAssignment, lastReading *time.Time) *time.Time {
+	latest := lastReading
+	for _, sa := range studentAssignments {
+		for _, at := range []*time.Time{sa.CompletedAt, sa.LastOpenedAt} {
+			if at != nil && (latest == nil || at.After(*latest)) {
+				latest = at
+			}
+		}
+	}
+	return latest
+}
+
+// inactivity scores the days since the student's last activity. Students with nothing left
+// to read are not inactive, and students who never did anything count from their first reading.
+func inactivity(studentAssignments []models.StudentAssignment, last *time.Time, now time.Time) (float64, string) {
+	var pending int
+	var firstAssigned time.Time
+	for _, sa := range studentAssignments {
+		if !sa.IsCompleted() && !sa.Excused {
+			pending++
+		}
+		if firstAssigned.IsZero() || sa.CreatedAt.Before(firstAssigned) {
+			firstAssigned = sa.CreatedAt
+		}
+	}
+	if pending == 0 {
+		return 0, "No readings left to do"
+	}
+
+	since := firstAssigned
+	if last != nil {
+		since = *last
+	}
+	days := int(now.Sub(since).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	value := math.Min(float64(days)/riskInactiveDays, 1)
+
+	if last == nil {
+		return value, fmt.Sprintf("No activity since the first reading was assigned %d day%s ago", days, plural(days))
+	}
+	return value, fmt.Sprintf("Last active %d day%s ago", days, plural(days))
+}
+
+// plural returns "s" unless n is one
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestCompletionTrend(t *testing.T) {
+	now := time.Now()
+	var studentAssignments []models.StudentAssignment
+	for i := 0; i < 4; i++ {
+		due := now.AddDate(0, 0, -10+2*i)
+		sa := models.StudentAssignment{Status: models.StatusAssigned, Assignment: models.Assignment{DueDate: &due}}
+		// The two oldest readings were done on time, the two newest were not
+		if i < 2 {
+			sa.Status = models.StatusCompleted
+			sa.CompletionTiming = models.TimingOnTime
+		}
+		studentAssignments = append(studentAssignments, sa)
+	}
+
+	value, explanation := completionTrend(studentAssignments, now)
+	if value != 1 {
+		t.Errorf("Expected a full decline, got %v (%s)", value, explanation)
+	}
+
+	value, _ = completionTrend(studentAssignments[:3], now)
+	if value != 0 {
+		t.Errorf("Expected no trend with fewer than %d past-due readings, got %v", riskTrendMinimum, value)
+	}
+}
+
+func TestAtRiskReport(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewRiskService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	diligent := createTestUser(t, db, "diligent", "student")
+	behind := createTestUser(t, db, "behind", "student")
+
+	pastDue := time.Now().AddDate(0, 0, -3)
+	var studentAssignments []*models.StudentAssignment
+	for _, title := range []string{"Essay", "Paper", "Chapter"} {
+		assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: title, URL: "https://example.com/" + title, DueDate: &pastDue})
+		if err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{diligent.ID, behind.ID}, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign students: %v", err)
+		}
+		sa, _ := models.GetStudentAssignment(db, assignment.ID, diligent.ID)
+		studentAssignments = append(studentAssignments, sa)
+	}
+	for _, sa := range studentAssignments {
+		if err := sa.MarkAsCompleted(db); err != nil {
+			t.Fatalf("Failed to complete assignment: %v", err)
+		}
+	}
+
+	now := time.Now().AddDate(0, 0, 20)
+	report, err := service.GetAtRiskReport(instructor.ID, now)
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+	if len(report.Students) != 2 {
+		t.Fatalf("Expected 2 students, got %d", len(report.Students))
+	}
+	first := report.Students[0]
+	if first.StudentID != behind.ID || first.Level != RiskLevelHigh || first.OverdueCount != 3 {
+		t.Errorf("Expected the student with 3 overdue readings to rank first at high risk, got %+v", first)
+	}
+	if first.Score != 80 {
+		t.Errorf("Expected every factor but the trend to max out, got %v", first.Score)
+	}
+	if len(first.Factors) != 4 || first.Factors[0].Explanation != "3 overdue readings" {
+		t.Errorf("Expected explained factors, got %+v", first.Factors)
+	}
+	if report.Students[1].Score != 0 || report.Students[1].Level != RiskLevelLow {
+		t.Errorf("Expected the student who finished everything to be low risk, got %+v", report.Students[1])
+	}
+
+	// Weights are the instructor's to choose
+	if _, err := service.SaveSettings(instructor.ID, RiskSettingsInput{}); err == nil {
+		t.Error("Expected all-zero weights to be rejected")
+	}
+	if _, err := service.SaveSettings(instructor.ID, RiskSettingsInput{OverdueWeight: 101}); err == nil {
+		t.Error("Expected weights above 100 to be rejected")
+	}
+	if _, err := service.SaveSettings(instructor.ID, RiskSettingsInput{OverdueWeight: 1, TrendWeight: 1, WeeklyDigest: true}); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	report, _ = service.GetAtRiskReport(instructor.ID, now)
+	if report.Students[0].Score != 50 {
+		t.Errorf("Expected overdue to carry half the score, got %v", report.Students[0].Score)
+	}
+
+	// The digest goes out once a week
+	sent, err := service.SendDigests(now)
+	if err != nil || sent != 1 {
+		t.Fatalf("Expected 1 digest, got %d (%v)", sent, err)
+	}
+	if sent, _ := service.SendDigests(now.AddDate(0, 0, 1)); sent != 0 {
+		t.Errorf("Expected no second digest within a week, got %d", sent)
+	}
+	notifications, _ := models.GetNotificationsByUser(db, instructor.ID, false)
+	if len(notifications) != 1 || !strings.Contains(notifications[0].Message, "behind (50, medium)") {
+		t.Errorf("Expected a digest naming the student at risk, got %+v", notifications)
+	}
+}
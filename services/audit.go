@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"reflect"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultAuditLimit is the number of audit events returned when no limit is given
+	defaultAuditLimit = 100
+	// maxAuditLimit caps the number of audit events returned at once
+	maxAuditLimit = 1000
+)
+
+// AuditService records authentication events and serves the audit log
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// GetEvents returns the events in an owner's audit log matching the filter, newest first
+func (s *AuditService) GetEvents(ownerID uint, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		return nil, errors.New("invalid limit: use at most 1000 events")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, errors.New("invalid range: to must be after from")
+	}
+	return models.GetAuditEvents(s.db, ownerID, filter)
+}
+
+// ExportEvents returns every event in an owner's audit log matching the filter, newest first.
+// Unlike GetEvents it has no default limit, so an export is complete unless a limit is given.
+func (s *AuditService) ExportEvents(ownerID uint, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	if filter.Limit < 0 {
+		return nil, errors.New("invalid limit: cannot be negative")
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, errors.New("invalid range: to must be after from")
+	}
+	return models.GetAuditEvents(s.db, ownerID, filter)
+}
+
+// RecordLogin records a user signing in
+func (s *AuditService) RecordLogin(user *models.User, ip string) {
+	recordAudit(s.db, userAuditEvent(user, models.AuditLogin, ip, nil))
+}
+
+// RecordRegistration records a user signing up, with the role they were given
+func (s *AuditService) RecordRegistration(user *models.User, ip string) {
+	changes := map[string]models.AuditChange{"role": {Before: nil, After: user.Role}}
+	recordAudit(s.db, userAuditEvent(user, models.AuditRegistered, ip, changes))
+}
+
+// RecordFailedLogin records a failed sign in. It shows in the log of the user whose
+// username was tried, if there is one.
+func (s *AuditService) RecordFailedLogin(username string, ip string) {
+	event := models.AuditEvent{ActorName: username, Action: models.AuditLoginFailed, TargetType: models.AuditTargetUser, IP: ip}
+	if user, err := models.GetUserByUsername(s.db, username); err == nil {
+		event.OwnerID = &user.ID
+		event.TargetID = user.ID
+	}
+	recordAudit(s.db, event)
+}
+
+// userAuditEvent builds an event a user performed on their own account
+func userAuditEvent(user *models.User, action string, ip string, changes map[string]models.AuditChange) models.AuditEvent {
+	return models.AuditEvent{
+		ActorID:    &user.ID,
+		ActorName:  user.Username,
+		OwnerID:    &user.ID,
+		Action:     action,
+		TargetType: models.AuditTargetUser,
+		TargetID:   user.ID,
+		Changes:    changes,
+		IP:         ip,
+	}
+}
+
+// assignmentAuditEvent builds an event for an instructor's change to one of their assignments
+func assignmentAuditEvent(instructorID uint, action string, assignmentID uint, changes map[string]models.AuditChange, ip string) models.AuditEvent {
+	return models.AuditEvent{
+		ActorID:      &instructorID,
+		OwnerID:      &instructorID,
+		Action:       action,
+		TargetType:   models.AuditTargetAssignment,
+		TargetID:     assignmentID,
+		AssignmentID: &assignmentID,
+		Changes:      changes,
+		IP:           ip,
+	}
+}
+
+// studentAuditEvents builds one event per student for an instructor's change to their place on an assignment
+func studentAuditEvents(instructorID uint, action string, assignmentID uint, studentIDs []uint, changes map[string]models.AuditChange, ip string) []models.AuditEvent {
+	events := make([]models.AuditEvent, len(studentIDs))
+	for i, studentID := range studentIDs {
+		events[i] = models.AuditEvent{
+			ActorID:      &instructorID,
+			OwnerID:      &instructorID,
+			Action:       action,
+			TargetType:   models.AuditTargetUser,
+			TargetID:     studentID,
+			AssignmentID: &assignmentID,
+			Changes:      changes,
+			IP:           ip,
+		}
+	}
+	return events
+}
+
+// recordAudit appends events to the audit log, filling in actor names from actor IDs.
+// It runs after the audited action succeeded, so failures are logged rather than undoing it.
+func recordAudit(db *gorm.DB, events ...models.AuditEvent) {
+	names := make(map[uint]string)
+	for i := range events {
+		if events[i].ActorID == nil || events[i].ActorName != "" {
+			continue
+		}
+		actorID := *events[i].ActorID
+		if _, ok := names[actorID]; !ok {
+			if actor, err := models.GetUserByID(db, actorID); err == nil {
+				names[actorID] = actor.Username
+			}
+		}
+		events[i].ActorName = names[actorID]
+	}
+	if err := models.CreateAuditEvents(db, events); err != nil {
+		log.Printf("failed to record %d audit events (%s): %v", len(events), events[0].Action, err)
+	}
+}
+
+// auditFields captures the audited fields of an assignment. Times are formatted so
+// snapshots compare by value and read the same in the stored diff.
+func auditFields(assignment *models.Assignment) map[string]interface{} {
+	return map[string]interface{}{
+		"title":                assignment.Title,
+		"description":          assignment.Description,
+		"url":                  assignment.URL,
+		"category":             assignment.Category,
+		"due_date":             auditTime(assignment.DueDate),
+		"publish_at":           auditTime(assignment.PublishAt),
+		"unpublish_at":         auditTime(assignment.UnpublishAt),
+		"grace_period_minutes": assignment.GracePeriodMinutes,
+		"min_reflection_words": assignment.MinReflectionWords,
+	}
+}
+
+// auditDiff lists the fields whose values differ between two snapshots. A nil snapshot
+// stands for a record that did not exist, so every field of the other one is listed.
+func auditDiff(before, after map[string]interface{}) map[string]models.AuditChange {
+	changes := make(map[string]models.AuditChange)
+	for field, value := range after {
+		if before == nil || !reflect.DeepEqual(before[field], value) {
+			changes[field] = models.AuditChange{Before: before[field], After: value}
+		}
+	}
+	for field, value := range before {
+		if _, ok := after[field]; !ok {
+			changes[field] = models.AuditChange{Before: value}
+		}
+	}
+	return changes
+}
+
+// auditTime formats a time for the audit log; nil stays nil
+func auditTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestAuditAssignmentChanges(t *testing.T) {
+	db := setupTestDB(t)
+	auditService := NewAuditService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	other := createTestUser(t, db, "instructor2", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	service := NewAssignmentService(db).WithClientIP("203.0.113.7")
+
+	assignment, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	dueDate := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	if err := service.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", DueDate: &dueDate}); err != nil {
+		t.Fatalf("Failed to update assignment: %v", err)
+	}
+	if err := service.AssignToMultipleStudents(assignment.ID, []uint{student.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	extension := dueDate.AddDate(0, 0, 2)
+	if _, err := service.GrantExtension(assignment.ID, student.ID, instructor.ID, &extension); err != nil {
+		t.Fatalf("Failed to grant extension: %v", err)
+	}
+	if err := service.RemoveStudentAssignment(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to remove student: %v", err)
+	}
+	if err := service.DeleteAssignment(assignment.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete assignment: %v", err)
+	}
+
+	events, err := auditService.GetEvents(instructor.ID, models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	expected := []string{
+		models.AuditAssignmentDeleted,
+		models.AuditStudentRemoved,
+		models.AuditExtensionChanged,
+		models.AuditStudentAssigned,
+		models.AuditAssignmentUpdated,
+		models.AuditAssignmentCreated,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, action := range expected {
+		event := events[i]
+		if event.Action != action {
+			t.Errorf("Expected event %d to be %s, got %s", i, action, event.Action)
+		}
+		if event.ActorID == nil || *event.ActorID != instructor.ID || event.ActorName != "instructor1" || event.IP != "203.0.113.7" {
+			t.Errorf("Expected %s by instructor1 from 203.0.113.7, got actor %v %q from %q", action, event.ActorID, event.ActorName, event.IP)
+		}
+		if event.AssignmentID == nil || *event.AssignmentID != assignment.ID {
+			t.Errorf("Expected %s to name the assignment, got %v", action, event.AssignmentID)
+		}
+	}
+
+	// Only the due date changed in the update
+	update := events[4].Changes
+	if len(update) != 1 || update["due_date"].Before != nil || update["due_date"].After != "2026-11-02T17:00:00Z" {
+		t.Errorf("Expected only the due date in the diff, got %+v", update)
+	}
+	removal := events[1]
+	if removal.TargetType != models.AuditTargetUser || removal.TargetID != student.ID {
+		t.Errorf("Expected the removal to target the student, got %s %d", removal.TargetType, removal.TargetID)
+	}
+	if extensionChange := events[2].Changes["due_date_override"]; extensionChange.After != "2026-11-04T17:00:00Z" {
+		t.Errorf("Expected the new extension in the diff, got %+v", extensionChange)
+	}
+	if deleted := events[0].Changes["title"]; deleted.Before != "Essay" || deleted.After != nil {
+		t.Errorf("Expected the deleted assignment's title in the diff, got %+v", deleted)
+	}
+
+	// Filters narrow the log, and other instructors see none of it
+	studentID := student.ID
+	filtered, _ := auditService.GetEvents(instructor.ID, models.AuditFilter{TargetType: models.AuditTargetUser, TargetID: &studentID})
+	if len(filtered) != 3 {
+		t.Errorf("Expected 3 events about the student, got %d", len(filtered))
+	}
+	filtered, _ = auditService.GetEvents(instructor.ID, models.AuditFilter{Action: models.AuditStudentRemoved, Limit: 5})
+	if len(filtered) != 1 {
+		t.Errorf("Expected 1 removal, got %d", len(filtered))
+	}
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	filtered, _ = auditService.GetEvents(instructor.ID, models.AuditFilter{From: &tomorrow})
+	if len(filtered) != 0 {
+		t.Errorf("Expected no events from tomorrow on, got %d", len(filtered))
+	}
+	filtered, _ = auditService.GetEvents(other.ID, models.AuditFilter{})
+	if len(filtered) != 0 {
+		t.Errorf("Expected another instructor's log to be empty, got %d", len(filtered))
+	}
+
+	if _, err := auditService.GetEvents(instructor.ID, models.AuditFilter{Limit: maxAuditLimit + 1}); err == nil {
+		t.Error("Expected an oversized limit to be rejected")
+	}
+}
+
+func TestAuditEventsAreAppendOnly(t *testing.T) {
+	db := setupTestDB(t)
+	user := createTestUser(t, db, "instructor1", "instructor")
+	NewAuditService(db).RecordLogin(user, "198.51.100.1")
+
+	var event models.AuditEvent
+	if err := db.First(&event).Error; err != nil {
+		t.Fatalf("Failed to load event: %v", err)
+	}
+	if err := db.Model(&event).Update("ip", "127.0.0.1").Error; err == nil {
+		t.Error("Expected updating an audit event to fail")
+	}
+	if err := db.Delete(&event).Error; err == nil {
+		t.Error("Expected deleting an audit event to fail")
+	}
+	db.First(&event, event.ID)
+	if event.IP != "198.51.100.1" {
+		t.Errorf("Expected the event to be unchanged, got IP %q", event.IP)
+	}
+}
+
+func TestAuditAuthEvents(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAuditService(db)
+	user := createTestUser(t, db, "instructor1", "instructor")
+
+	service.RecordRegistration(user, "198.51.100.1")
+	service.RecordLogin(user, "198.51.100.1")
+	service.RecordFailedLogin("instructor1", "198.51.100.2")
+	service.RecordFailedLogin("nobody", "198.51.100.3")
+
+	events, err := service.GetEvents(user.ID, models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected the user's 3 events, got %d", len(events))
+	}
+	failed := events[0]
+	if failed.Action != models.AuditLoginFailed || failed.ActorID != nil || failed.ActorName != "instructor1" || failed.IP != "198.51.100.2" {
+		t.Errorf("Expected an anonymous failed login for instructor1, got %+v", failed)
+	}
+	if role := events[2].Changes["role"]; events[2].Action != models.AuditRegistered || role.After != "instructor" {
+		t.Errorf("Expected the registration to record the role, got %+v", events[2])
+	}
+
+	// The failed login for an unknown username is kept but shown to nobody
+	var count int64
+	db.Model(&models.AuditEvent{}).Where("actor_name = ? AND owner_id IS NULL", "nobody").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the unknown username's failed login to be recorded, got %d", count)
+	}
+}
+
+func TestAuditGeneratedAssignments(t *testing.T) {
+	db := setupTestDB(t)
+	auditService := NewAuditService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student1 := createTestUser(t, db, "student1", "student")
+	student2 := createTestUser(t, db, "student2", "student")
+
+	// A recurrence occurrence is created and assigned by the scheduler on the instructor's behalf
+	template, err := NewTemplateService(db).CreateTemplate(instructor.ID, TemplateInput{Name: "Monday Reading", Title: "Monday Reading", URL: "https://example.com/monday"})
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	recurrenceService := NewRecurrenceService(db)
+	if _, err := recurrenceService.CreateRecurrence(instructor.ID, CreateRecurrenceInput{
+		TemplateID: template.ID,
+		RRule:      "FREQ=WEEKLY;BYDAY=MO;COUNT=1",
+		StartsAt:   start,
+		StudentIDs: []uint{student1.ID, student2.ID},
+	}); err != nil {
+		t.Fatalf("Failed to create recurrence: %v", err)
+	}
+	if _, err := recurrenceService.ProcessRecurrences(start); err != nil {
+		t.Fatalf("Failed to process recurrences: %v", err)
+	}
+
+	events, err := auditService.GetEvents(instructor.ID, models.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected the occurrence and its 2 students in the log, got %d events", len(events))
+	}
+	created := events[2]
+	if created.Action != models.AuditAssignmentCreated || created.ActorName != "instructor1" || created.Changes["title"].After != "Monday Reading" {
+		t.Errorf("Expected the occurrence's creation by instructor1, got %+v", created)
+	}
+	for _, event := range events[:2] {
+		if event.Action != models.AuditStudentAssigned || event.AssignmentID == nil || *event.AssignmentID != created.TargetID {
+			t.Errorf("Expected the students to be assigned the occurrence, got %+v", event)
+		}
+	}
+
+	// Assigning a reading list enrolls the students in each of its readings
+	assignment, err := NewAssignmentService(db).CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	readingListService := NewReadingListService(db)
+	list, err := readingListService.CreateReadingList(instructor.ID, CreateReadingListInput{Title: "Essays", Items: []ReadingListItemInput{{AssignmentID: assignment.ID}}})
+	if err != nil {
+		t.Fatalf("Failed to create reading list: %v", err)
+	}
+	if err := readingListService.WithClientIP("203.0.113.7").AssignReadingList(list.ID, []uint{student1.ID}, nil, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign reading list: %v", err)
+	}
+
+	assignmentID := assignment.ID
+	events, _ = auditService.GetEvents(instructor.ID, models.AuditFilter{Action: models.AuditStudentAssigned, AssignmentID: &assignmentID})
+	if len(events) != 1 || events[0].TargetID != student1.ID || events[0].IP != "203.0.113.7" {
+		t.Errorf("Expected the reading list assignment to be recorded for student1, got %+v", events)
+	}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Cohort dimensions
+const (
+	CohortByCategory   = "category"
+	CohortByAssignment = "assignment"
+	CohortByWeek       = "week"   // week the reading was assigned
+	CohortByMonth      = "month"  // month the reading was assigned
+	CohortByGroup      = "group"  // the instructor's groups the student belongs to
+	CohortByCourse     = "course" // courses of the instructor's groups the student belongs to
+)
+
+// cohortDimension is the SQL that groups student assignments into cohorts
+type cohortDimension struct {
+	key      string
+	label    string
+	join     string // extra join the key and label read from; its one placeholder takes the instructor ID
+	assigned bool   // cohorts come from the student assignment, so unassigned readings have none
+}
+
+// A student in several of the instructor's groups, or in groups of several courses,
+// is counted in each of their cohorts.
+const (
+	cohortGroupsJoin = `LEFT JOIN (
+			SELECT group_members.user_id, groups.id, groups.name
+			FROM group_members JOIN groups ON groups.id = group_members.group_id AND groups.deleted_at IS NULL
+			WHERE groups.created_by_id = ?
+		) AS cohort_groups ON cohort_groups.user_id = student_assignments.student_id`
+	cohortCoursesJoin = `LEFT JOIN (
+			SELECT DISTINCT group_members.user_id, groups.course
+			FROM group_members JOIN groups ON groups.id = group_members.group_id AND groups.deleted_at IS NULL
+			WHERE groups.created_by_id = ? AND groups.course != ''
+		) AS cohort_courses ON cohort_courses.user_id = student_assignments.student_id`
+)
+
+var cohortDimensions = map[string]cohortDimension{
+	CohortByCategory:   {key: "COALESCE(NULLIF(assignments.category, ''), 'uncategorized')", label: "COALESCE(NULLIF(assignments.category, ''), 'uncategorized')"},
+	CohortByAssignment: {key: "CAST(assignments.id AS TEXT)", label: "assignments.title"},
+	CohortByWeek:       {key: "strftime('%Y-W%W', student_assignments.created_at)", label: "strftime('%Y-W%W', student_assignments.created_at)", assigned: true},
+	CohortByMonth:      {key: "strftime('%Y-%m', student_assignments.created_at)", label: "strftime('%Y-%m', student_assignments.created_at)", assigned: true},
+	CohortByGroup:      {key: "COALESCE(CAST(cohort_groups.id AS TEXT), 'none')", label: "COALESCE(cohort_groups.name, 'No group')", join: cohortGroupsJoin, assigned: true},
+	CohortByCourse:     {key: "COALESCE(cohort_courses.course, 'none')", label: "COALESCE(cohort_courses.course, 'No course')", join: cohortCoursesJoin, assigned: true},
+}
+
+// overallCohort puts every student assignment in a single cohort
+var overallCohort = cohortDimension{key: "'all'", label: "'All readings'"}
+
+// hoursToCompleteSQL is the time from assignment to completion in hours
+const hoursToCompleteSQL = "(julianday(student_assignments.completed_at) - julianday(student_assignments.created_at)) * 24"
+
+// CohortQuery selects how student assignments are grouped and which are included
+type CohortQuery struct {
+	Dimension string
+	Category  string     // only readings in this category
+	From      *time.Time // only readings assigned at or after this time
+	To        *time.Time // only readings assigned before this time
+}
+
+// CohortAnalytics compares completion across cohorts of an instructor's readings
+type CohortAnalytics struct {
+	Dimension string        `json:"dimension"`
+	Category  string        `json:"category,omitempty"`
+	From      *time.Time    `json:"from"`
+	To        *time.Time    `json:"to"`
+	Overall   CohortStats   `json:"overall"`
+	Cohorts   []CohortStats `json:"cohorts"`
+}
+
+// CohortStats holds the completion statistics of one cohort
+type CohortStats struct {
+	Key                string          `json:"key"`
+	Label              string          `json:"label"`
+	Assignments        int             `json:"assignments"`
+	Students           int             `json:"students"`
+	StudentAssignments int             `json:"student_assignments"`
+	Completed          int             `json:"completed"`
+	CompletionRate     float64         `json:"completion_rate"`
+	OnTime             int             `json:"on_time"`
+	Late               int             `json:"late"`
+	OnTimeRate         float64         `json:"on_time_rate"` // share of timed completions that were on time
+	AverageHours       *float64        `json:"average_time_to_complete_hours"`
+	TimeToComplete     HourPercentiles `json:"time_to_complete_hours"`
+}
+
+// HourPercentiles is the distribution of time-to-complete in hours, nil without completions
+type HourPercentiles struct {
+	P25    *float64 `json:"p25"`
+	Median *float64 `json:"median"`
+	P75    *float64 `json:"p75"`
+	P90    *float64 `json:"p90"`
+}
+
+// cohortCounts is a row of the cohort totals query
+type cohortCounts struct {
+	Cohort             string
+	Label              string
+	Assignments        int
+	Students           int
+	StudentAssignments int
+	Completed          int
+	OnTime             int
+	Late               int
+	AverageHours       *float64
+}
+
+// cohortPercentiles is a row of the time-to-complete percentiles query
+type cohortPercentiles struct {
+	Cohort string
+	P25    *float64
+	Median *float64
+	P75    *float64
+	P90    *float64
+}
+
+// GetCohortAnalytics groups an instructor's student assignments by the requested dimension
+// and compares completion, timeliness and time-to-complete across the cohorts
+func (s *ProgressTrackingService) GetCohortAnalytics(instructorID uint, query CohortQuery) (*CohortAnalytics, error) {
+	if query.Dimension == "" {
+		query.Dimension = CohortByCategory
+	}
+	dimension, ok := cohortDimensions[query.Dimension]
+	if !ok {
+		return nil, fmt.Errorf("invalid dimension %q: use category, assignment, week, month, group or course", query.Dimension)
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, errors.New("invalid date range: from must be before to")
+	}
+
+	cohorts, err := s.cohortStats(instructorID, dimension, query)
+	if err != nil {
+		return nil, err
+	}
+	overall, err := s.cohortStats(instructorID, overallCohort, query)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &CohortAnalytics{
+		Dimension: query.Dimension,
+		Category:  query.Category,
+		From:      query.From,
+		To:        query.To,
+		Overall:   CohortStats{Key: "all", Label: "All readings"},
+		Cohorts:   cohorts,
+	}
+	if len(overall) > 0 {
+		analytics.Overall = overall[0]
+	}
+	return analytics, nil
+}
+
+// cohortStats runs the totals and percentile queries for one grouping
+func (s *ProgressTrackingService) cohortStats(instructorID uint, dimension cohortDimension, query CohortQuery) ([]CohortStats, error) {
+	where, args := cohortFilter(instructorID, dimension, query)
+
+	var counts []cohortCounts
+	err := s.db.Raw(fmt.Sprintf(`
+		SELECT %[1]s AS cohort, MAX(%[2]s) AS label,
+			COUNT(DISTINCT assignments.id) AS assignments,
+			COUNT(DISTINCT student_assignments.student_id) AS students,
+			COUNT(student_assignments.id) AS student_assignments,
+			SUM(CASE WHEN student_assignments.status = 'completed' THEN 1 ELSE 0 END) AS completed,
+			SUM(CASE WHEN student_assignments.status = 'completed' AND student_assignments.completion_timing = 'on_time' THEN 1 ELSE 0 END) AS on_time,
+			SUM(CASE WHEN student_assignments.status = 'completed' AND student_assignments.completion_timing = 'late' THEN 1 ELSE 0 END) AS late,
+			AVG(CASE WHEN student_assignments.status = 'completed' AND student_assignments.completed_at IS NOT NULL THEN %[3]s END) AS average_hours
+		FROM assignments
+		LEFT JOIN student_assignments ON student_assignments.assignment_id = assignments.id AND student_assignments.deleted_at IS NULL
+		%[5]s
+		WHERE %[4]s
+		GROUP BY cohort
+		ORDER BY label, cohort`, dimension.key, dimension.label, hoursToCompleteSQL, where, dimension.join), args...).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Nearest-rank percentiles: the smallest time at or above the given share of completions
+	var percentiles []cohortPercentiles
+	err = s.db.Raw(fmt.Sprintf(`
+		SELECT cohort,
+			MIN(CASE WHEN position >= 0.25 * total THEN hours END) AS p25,
+			MIN(CASE WHEN position >= 0.5 * total THEN hours END) AS median,
+			MIN(CASE WHEN position >= 0.75 * total THEN hours END) AS p75,
+			MIN(CASE WHEN position >= 0.9 * total THEN hours END) AS p90
+		FROM (
+			SELECT %[1]s AS cohort, %[2]s AS hours,
+				ROW_NUMBER() OVER (PARTITION BY %[1]s ORDER BY %[2]s) AS position,
+				COUNT(*) OVER (PARTITION BY %[1]s) AS total
+			FROM assignments
+			JOIN student_assignments ON student_assignments.assignment_id = assignments.id AND student_assignments.deleted_at IS NULL
+			%[4]s
+			WHERE %[3]s AND student_assignments.status = 'completed' AND student_assignments.completed_at IS NOT NULL
+		)
+		GROUP BY cohort`, dimension.key, hoursToCompleteSQL, where, dimension.join), args...).
+		Scan(&percentiles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byCohort := make(map[string]cohortPercentiles, len(percentiles))
+	for _, row := range percentiles {
+		byCohort[row.Cohort] = row
+	}
+
+	stats := make([]CohortStats, 0, len(counts))
+	for _, row := range counts {
+		cohort := CohortStats{
+			Key:                row.Cohort,
+			Label:              row.Label,
+			Assignments:        row.Assignments,
+			Students:           row.Students,
+			StudentAssignments: row.StudentAssignments,
+			Completed:          row.Completed,
+			OnTime:             row.OnTime,
+			Late:               row.Late,
+			AverageHours:       roundHours(row.AverageHours),
+		}
+		if row.StudentAssignments > 0 {
+			cohort.CompletionRate = float64(row.Completed) / float64(row.StudentAssignments) * 100
+		}
+		if timed := row.OnTime + row.Late; timed > 0 {
+			cohort.OnTimeRate = float64(row.OnTime) / float64(timed) * 100
+		}
+		if p, ok := byCohort[row.Cohort]; ok {
+			cohort.TimeToComplete = HourPercentiles{
+				P25:    roundHours(p.P25),
+				Median: roundHours(p.Median),
+				P75:    roundHours(p.P75),
+				P90:    roundHours(p.P90),
+			}
+		}
+		stats = append(stats, cohort)
+	}
+	return stats, nil
+}
+
+// cohortFilter builds the WHERE clause shared by the cohort queries. Its arguments start with
+// those of the dimension's join, which comes before the WHERE clause.
+func cohortFilter(instructorID uint, dimension cohortDimension, query CohortQuery) (string, []interface{}) {
+	conditions := []string{"assignments.created_by_id = ?", "assignments.deleted_at IS NULL"}
+	args := []interface{}{instructorID}
+	if dimension.join != "" {
+		args = append([]interface{}{instructorID}, args...)
+	}
+
+	if dimension.assigned || query.From != nil || query.To != nil {
+		conditions = append(conditions, "student_assignments.id IS NOT NULL")
+	}
+	if query.Category != "" {
+		conditions = append(conditions, "assignments.category = ?")
+		args = append(args, query.Category)
+	}
+	if query.From != nil {
+		conditions = append(conditions, "student_assignments.created_at >= ?")
+		args = append(args, *query.From)
+	}
+	if query.To != nil {
+		conditions = append(conditions, "student_assignments.created_at < ?")
+		args = append(args, *query.To)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// roundHours rounds a time in hours to one decimal
+func roundHours(hours *float64) *float64 {
+	if hours == nil {
+		return nil
+	}
+	rounded := math.Round(*hours*10) / 10
+	return &rounded
+}
+
+// categoryBreakdown summarizes each category of an instructor's readings for the progress summary
+func (s *ProgressTrackingService) categoryBreakdown(instructorID uint) (map[string]CategoryStats, error) {
+	cohorts, err := s.cohortStats(instructorID, cohortDimensions[CohortByCategory], CohortQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := make(map[string]CategoryStats, len(cohorts))
+	for _, cohort := range cohorts {
+		stats := CategoryStats{AssignmentCount: cohort.Assignments, CompletionRate: cohort.CompletionRate}
+		if cohort.AverageHours != nil {
+			stats.AverageTimeToComplete = int(*cohort.AverageHours)
+		}
+		breakdown[cohort.Key] = stats
+	}
+	return breakdown, nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestCohortAnalytics(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewProgressTrackingService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	var students []uint
+	for _, name := range []string{"s1", "s2", "s3", "s4"} {
+		students = append(students, createTestUser(t, db, name, "student").ID)
+	}
+
+	essays, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", Category: "essays"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	chapter, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Chapter", URL: "https://example.com/chapter", Category: "books"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(essays.ID, students, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(chapter.ID, students[:1], instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	// Essays assigned in March and finished after 10, 20 and 40 hours; one student has not finished
+	assigned := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i, hours := range []int{10, 20, 40} {
+		completed := assigned.Add(time.Duration(hours) * time.Hour)
+		timing := models.TimingOnTime
+		if hours == 40 {
+			timing = models.TimingLate
+		}
+		db.Model(&models.StudentAssignment{}).
+			Where("assignment_id = ? AND student_id = ?", essays.ID, students[i]).
+			Updates(map[string]interface{}{"status": models.StatusCompleted, "created_at": assigned, "completed_at": completed, "completion_timing": timing})
+	}
+	// The unfinished student reopened a late reading before reopening cleared its timing
+	db.Model(&models.StudentAssignment{}).
+		Where("assignment_id = ? AND student_id = ?", essays.ID, students[3]).
+		Updates(map[string]interface{}{"created_at": assigned, "completion_timing": models.TimingLate})
+	db.Model(&models.StudentAssignment{}).Where("assignment_id = ?", chapter.ID).Update("created_at", assigned.AddDate(0, 1, 0))
+
+	analytics, err := service.GetCohortAnalytics(instructor.ID, CohortQuery{Dimension: CohortByCategory})
+	if err != nil {
+		t.Fatalf("Failed to get cohort analytics: %v", err)
+	}
+	if len(analytics.Cohorts) != 2 {
+		t.Fatalf("Expected 2 category cohorts, got %d", len(analytics.Cohorts))
+	}
+	essayStats := analytics.Cohorts[1]
+	if essayStats.Key != "essays" || essayStats.StudentAssignments != 4 || essayStats.Completed != 3 {
+		t.Fatalf("Unexpected essay cohort: %+v", essayStats)
+	}
+	if essayStats.CompletionRate != 75 {
+		t.Errorf("Expected a 75%% completion rate, got %v", essayStats.CompletionRate)
+	}
+	if essayStats.OnTime != 2 || essayStats.Late != 1 {
+		t.Errorf("Expected 2 on time and 1 late, got %d and %d", essayStats.OnTime, essayStats.Late)
+	}
+	if essayStats.OnTimeRate < 66.6 || essayStats.OnTimeRate > 66.7 {
+		t.Errorf("Expected two thirds on time, got %v", essayStats.OnTimeRate)
+	}
+	median := essayStats.TimeToComplete.Median
+	if median == nil || *median != 20 {
+		t.Errorf("Expected a median of 20 hours, got %v", median)
+	}
+	if p90 := essayStats.TimeToComplete.P90; p90 == nil || *p90 != 40 {
+		t.Errorf("Expected a 90th percentile of 40 hours, got %v", p90)
+	}
+	if analytics.Cohorts[0].TimeToComplete.Median != nil {
+		t.Error("Expected no percentiles for a cohort without completions")
+	}
+
+	// Every student assignment counts equally, so the overall rate is 3 of 5
+	if analytics.Overall.StudentAssignments != 5 || analytics.Overall.CompletionRate != 60 {
+		t.Errorf("Unexpected overall cohort: %+v", analytics.Overall)
+	}
+	summary, err := service.GetInstructorProgressSummary(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	if summary.CategoryBreakdown["essays"].CompletionRate != 75 || summary.CategoryBreakdown["essays"].AverageTimeToComplete != 23 {
+		t.Errorf("Expected the summary to use the pooled category statistics, got %+v", summary.CategoryBreakdown["essays"])
+	}
+
+	// Date cohorts and ranges use the date the reading was assigned
+	analytics, err = service.GetCohortAnalytics(instructor.ID, CohortQuery{Dimension: CohortByMonth})
+	if err != nil {
+		t.Fatalf("Failed to get monthly analytics: %v", err)
+	}
+	if len(analytics.Cohorts) != 2 || analytics.Cohorts[0].Key != "2026-03" || analytics.Cohorts[1].Key != "2026-04" {
+		t.Errorf("Expected March and April cohorts, got %+v", analytics.Cohorts)
+	}
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	analytics, err = service.GetCohortAnalytics(instructor.ID, CohortQuery{Dimension: CohortByAssignment, From: &from})
+	if err != nil {
+		t.Fatalf("Failed to get ranged analytics: %v", err)
+	}
+	if len(analytics.Cohorts) != 1 || analytics.Cohorts[0].Label != "Chapter" {
+		t.Errorf("Expected only the chapter assigned in April, got %+v", analytics.Cohorts)
+	}
+
+	if _, err := service.GetCohortAnalytics(instructor.ID, CohortQuery{Dimension: "school"}); err == nil {
+		t.Error("Expected an unknown dimension to be rejected")
+	}
+}
+
+func TestCohortAnalyticsByGroupAndCourse(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewProgressTrackingService(db)
+	assignmentService := NewAssignmentService(db)
+	groupService := NewGroupService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+
+	var students []uint
+	for _, name := range []string{"s1", "s2", "s3", "s4"} {
+		students = append(students, createTestUser(t, db, name, "student").ID)
+	}
+
+	// s2 is in two Go groups and s4 is only in another instructor's group
+	groups := []GroupInput{
+		{Name: "Go Mornings", Course: "Go 101", StudentIDs: students[0:2]},
+		{Name: "Go Evenings", Course: "Go 101", StudentIDs: students[1:3]},
+		{Name: "Rust", Course: "Rust 101", StudentIDs: students[2:3]},
+	}
+	for _, input := range groups {
+		if _, err := groupService.CreateGroup(instructor.ID, input); err != nil {
+			t.Fatalf("Failed to create group: %v", err)
+		}
+	}
+	if _, err := groupService.CreateGroup(otherInstructor.ID, GroupInput{Name: "Elsewhere", Course: "Go 101", StudentIDs: students[3:]}); err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	withdrawn, err := groupService.CreateGroup(instructor.ID, GroupInput{Name: "Withdrawn", Course: "Go 101", StudentIDs: students[3:]})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if err := groupService.DeleteGroup(withdrawn.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete group: %v", err)
+	}
+
+	essay, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if _, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Unassigned", URL: "https://example.com/unassigned"}); err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(essay.ID, students, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	for _, studentID := range students[:2] {
+		sa, err := models.GetStudentAssignment(db, essay.ID, studentID)
+		if err != nil {
+			t.Fatalf("Failed to get student assignment: %v", err)
+		}
+		if err := sa.MarkAsCompleted(db); err != nil {
+			t.Fatalf("Failed to complete assignment: %v", err)
+		}
+	}
+
+	cohorts := func(dimension string) map[string]CohortStats {
+		analytics, err := service.GetCohortAnalytics(instructor.ID, CohortQuery{Dimension: dimension})
+		if err != nil {
+			t.Fatalf("Failed to get %s cohorts: %v", dimension, err)
+		}
+		if analytics.Overall.StudentAssignments != 4 {
+			t.Errorf("Expected the overall cohort to count each reading once, got %+v", analytics.Overall)
+		}
+		byLabel := make(map[string]CohortStats, len(analytics.Cohorts))
+		for _, cohort := range analytics.Cohorts {
+			byLabel[cohort.Label] = cohort
+		}
+		return byLabel
+	}
+
+	byGroup := cohorts(CohortByGroup)
+	expected := map[string][2]int{"Go Mornings": {2, 2}, "Go Evenings": {2, 1}, "Rust": {1, 0}, "No group": {1, 0}}
+	if len(byGroup) != len(expected) {
+		t.Errorf("Expected %d group cohorts, got %+v", len(expected), byGroup)
+	}
+	for label, counts := range expected {
+		if cohort := byGroup[label]; cohort.StudentAssignments != counts[0] || cohort.Completed != counts[1] {
+			t.Errorf("Expected %s to have %d readings and %d completed, got %+v", label, counts[0], counts[1], cohort)
+		}
+	}
+
+	byCourse := cohorts(CohortByCourse)
+	expected = map[string][2]int{"Go 101": {3, 2}, "Rust 101": {1, 0}, "No course": {1, 0}}
+	if len(byCourse) != len(expected) {
+		t.Errorf("Expected %d course cohorts, got %+v", len(expected), byCourse)
+	}
+	for label, counts := range expected {
+		if cohort := byCourse[label]; cohort.StudentAssignments != counts[0] || cohort.Completed != counts[1] {
+			t.Errorf("Expected %s to have %d readings and %d completed, got %+v", label, counts[0], counts[1], cohort)
+		}
+	}
+	if median := byCourse["Go 101"].TimeToComplete.Median; median == nil {
+		t.Errorf("Expected time-to-complete percentiles per course, got %+v", byCourse["Go 101"])
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+)
+
+const (
+	// wordsPerMinute is the average adult silent reading speed for non-fiction
+	wordsPerMinute = 238
+	// extractionBatchSize limits how many assignments one pipeline run analyzes
+	extractionBatchSize = 50
+	// maxExtractionRedirects limits how many redirects an extraction fetch follows
+	maxExtractionRedirects = 5
+)
+
+// ContentExtractionOptions configures fetching of assignment pages for reader-mode extraction
+type ContentExtractionOptions struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+}
+
+// ContentExtractionService extracts the main article text of assignment pages to estimate reading time
+type ContentExtractionService struct {
+	db           *gorm.DB
+	client       *http.Client
+	guard        *addressGuard
+	maxBodyBytes int64
+}
+
+// NewContentExtractionService creates a new content extraction service
+func NewContentExtractionService(db *gorm.DB, options ContentExtractionOptions) *ContentExtractionService {
+	s := &ContentExtractionService{
+		db:           db,
+		guard:        &addressGuard{},
+		maxBodyBytes: options.MaxBodyBytes,
+	}
+
+	s.client = newGuardedClient(s.guard, options.Timeout, func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxExtractionRedirects {
+			return errors.New("too many redirects")
+		}
+		return validateFetchURL(req.URL)
+	})
+
+	return s
+}
+
+// EstimateReadingMinutes converts a word count into whole minutes of reading
+func EstimateReadingMinutes(wordCount int) int {
+	if wordCount <= 0 {
+		return 0
+	}
+	return (wordCount + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// Start analyzes new and changed assignment URLs on the given interval until stop is closed
+func (s *ContentExtractionService) Start(interval time.Duration, stop <-chan struct{}) {
+	runEvery("content extraction", interval, stop, func() error {
+		_, err := s.ProcessPending()
+		return err
+	})
+}
+
+// ProcessPending analyzes assignments whose URL has not been extracted yet and returns
+// the number processed. Pages that cannot be read are recorded with no words so they
+// are not fetched again until the URL changes or an instructor asks for it.
+func (s *ContentExtractionService) ProcessPending() (int, error) {
+	assignments, err := models.GetAssignmentsNeedingExtraction(s.db, extractionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range assignments {
+		if err := s.extract(&assignments[i]); err != nil {
+			log.Printf("content extraction for assignment %d failed: %v", assignments[i].ID, err)
+		}
+	}
+
+	return len(assignments), nil
+}
+
+// ExtractAssignment re-analyzes an assignment's page on demand for its instructor
+func (s *ContentExtractionService) ExtractAssignment(assignmentID uint, instructorID uint) (*models.Assignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	// Uploaded documents are not HTML pages; their reading time is set by hand
+	if assignment.FileID != nil {
+		return nil, errors.New("uploaded files cannot be analyzed")
+	}
+
+	if err := s.extract(assignment); err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentByID(s.db, assignmentID)
+}
+
+// SetEstimateOverride sets an assignment's reading time by hand; nil restores the computed estimate
+func (s *ContentExtractionService) SetEstimateOverride(assignmentID uint, instructorID uint, minutes *int) (*models.Assignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	if minutes != nil && *minutes < 0 {
+		return nil, errors.New("estimated minutes cannot be negative")
+	}
+
+	if err := assignment.SetEstimateOverride(s.db, minutes, EstimateReadingMinutes(assignment.WordCount)); err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentByID(s.db, assignmentID)
+}
+
+// extract fetches an assignment's page, counts the words of its main text and stores the estimate.
+// The attempt is recorded even when the page cannot be read.
+func (s *ContentExtractionService) extract(assignment *models.Assignment) error {
+	text, fetchErr := s.fetchMainText(assignment.URL)
+	wordCount := len(strings.Fields(text))
+
+	// The same article served from different URLs shares a content hash
+	contentHash := ""
+	if wordCount > 0 {
+		sum := sha256.Sum256([]byte(text))
+		contentHash = hex.EncodeToString(sum[:])
+	}
+
+	if err := assignment.UpdateReadingEstimate(s.db, assignment.URL, contentHash, wordCount, EstimateReadingMinutes(wordCount), time.Now()); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// fetchMainText downloads an HTML page and returns its main article text
+func (s *ContentExtractionService) fetchMainText(rawURL string) (string, error) {
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", errors.New("invalid URL")
+	}
+	if err := validateFetchURL(target); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return "", errors.New("invalid URL")
+	}
+	req.Header.Set("User-Agent", "ZipCodeReader-Reader/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return "", ErrBlockedAddress
+		}
+		return "", fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("failed to fetch page: status %d", resp.StatusCode)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return "", errors.New("only HTML pages can be analyzed")
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, s.maxBodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	return extractMainText(doc), nil
+}
+
+// extractMainText finds the element holding the article and returns its text. It prefers
+// <article> and <main>, then the element whose paragraphs hold the most text, and finally
+// the whole body. Scripts, navigation and other page chrome are ignored.
+func extractMainText(doc *html.Node) string {
+	body := findArchiveElement(doc, "body")
+	if body == nil {
+		return ""
+	}
+
+	content := largestElement(body, "article")
+	if content == nil {
+		content = largestElement(body, "main")
+	}
+	if content == nil {
+		content = densestParagraphParent(body)
+	}
+	if content == nil {
+		content = body
+	}
+
+	var text strings.Builder
+	collectReadableText(content, &text)
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// largestElement returns the element with the given tag that holds the most text
+func largestElement(root *html.Node, tag string) *html.Node {
+	var best *html.Node
+	bestLength := 0
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && droppedArchiveElements[n.Data] {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == tag {
+			if length := readableTextLength(n); length > bestLength {
+				best, bestLength = n, length
+			}
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return best
+}
+
+// densestParagraphParent returns the element whose direct <p> children hold the most text
+func densestParagraphParent(root *html.Node) *html.Node {
+	scores := make(map[*html.Node]int)
+	var best *html.Node
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && droppedArchiveElements[n.Data] {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "p" && n.Parent != nil {
+			scores[n.Parent] += readableTextLength(n)
+			if best == nil || scores[n.Parent] > scores[best] {
+				best = n.Parent
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return best
+}
+
+// readableTextLength returns the length of an element's visible text
+func readableTextLength(n *html.Node) int {
+	var text strings.Builder
+	collectReadableText(n, &text)
+	return len(strings.TrimSpace(text.String()))
+}
+
+// collectReadableText appends the visible text below a node, skipping page chrome
+func collectReadableText(n *html.Node, text *strings.Builder) {
+	switch n.Type {
+	case html.TextNode:
+		text.WriteString(n.Data)
+		text.WriteByte(' ')
+		return
+	case html.ElementNode:
+		if droppedArchiveElements[n.Data] || n.Data == "header" {
+			return
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		collectReadableText(child, text)
+	}
+}
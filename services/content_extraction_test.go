@@ -0,0 +1,228 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"zipcodereader/models"
+
+	"golang.org/x/net/html"
+)
+
+func newTestContentExtractionService(t *testing.T) *ContentExtractionService {
+	service := NewContentExtractionService(setupTestDB(t), ContentExtractionOptions{
+		Timeout:      2 * time.Second,
+		MaxBodyBytes: 1 << 20,
+	})
+	// httptest servers listen on loopback
+	service.guard.allowPrivateNetworks = true
+	return service
+}
+
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("word ", n))
+}
+
+func TestEstimateReadingMinutes(t *testing.T) {
+	tests := []struct {
+		words   int
+		minutes int
+	}{
+		{0, 0},
+		{1, 1},
+		{238, 1},
+		{239, 2},
+		{2380, 10},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateReadingMinutes(tt.words); got != tt.minutes {
+			t.Errorf("EstimateReadingMinutes(%d) = %d, want %d", tt.words, got, tt.minutes)
+		}
+	}
+}
+
+func TestExtractMainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  string
+		words int
+	}{
+		{
+			name: "article preferred over page chrome",
+			page: `<html><body><nav>Home About Contact</nav>
+				<article><h1>Title here</h1><p>` + words(20) + `</p><script>var ignored = 1;</script></article>
+				<aside>Related links list</aside><footer>Copyright notice</footer></body></html>`,
+			words: 22,
+		},
+		{
+			name: "densest paragraphs without article markup",
+			page: `<html><body><div class="menu"><p>Short menu</p></div>
+				<div class="post"><p>` + words(30) + `</p><p>` + words(30) + `</p></div>
+				<div class="comments"><p>` + words(10) + `</p></div></body></html>`,
+			words: 60,
+		},
+		{
+			name:  "whole body as a last resort",
+			page:  `<html><body><div>` + words(12) + `</div><noscript>Enable JavaScript</noscript></body></html>`,
+			words: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatalf("Failed to parse page: %v", err)
+			}
+
+			text := extractMainText(doc)
+			if got := len(strings.Fields(text)); got != tt.words {
+				t.Errorf("Expected %d words, got %d: %q", tt.words, got, text)
+			}
+		})
+	}
+}
+
+func TestProcessPendingExtraction(t *testing.T) {
+	pageWords := 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><article><p>%s</p></article></body></html>`, words(pageWords))
+	}))
+	defer server.Close()
+
+	service := newTestContentExtractionService(t)
+	db := service.db
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Long Read",
+		URL:   server.URL + "/long",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	processed, err := service.ProcessPending()
+	if err != nil {
+		t.Fatalf("Failed to process extraction: %v", err)
+	}
+	if processed != 1 {
+		t.Errorf("Expected 1 assignment processed, got %d", processed)
+	}
+
+	updated, err := models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get assignment: %v", err)
+	}
+	if updated.WordCount != 1000 || updated.EstimatedMinutes != 5 {
+		t.Errorf("Expected 1000 words and 5 minutes, got %d words and %d minutes", updated.WordCount, updated.EstimatedMinutes)
+	}
+
+	// Already analyzed URLs are not fetched again
+	if processed, _ := service.ProcessPending(); processed != 0 {
+		t.Errorf("Expected nothing left to process, got %d", processed)
+	}
+
+	// An instructor override survives re-extraction after the URL changes
+	override := 45
+	if _, err := service.SetEstimateOverride(assignment.ID, instructor.ID, &override); err != nil {
+		t.Fatalf("Failed to override estimate: %v", err)
+	}
+	pageWords = 476
+	err = assignmentService.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{
+		Title: "Long Read",
+		URL:   server.URL + "/shorter",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update assignment: %v", err)
+	}
+	if processed, _ := service.ProcessPending(); processed != 1 {
+		t.Errorf("Expected changed URL to be processed again, got %d", processed)
+	}
+
+	updated, _ = models.GetAssignmentByID(db, assignment.ID)
+	if updated.WordCount != 476 || updated.EstimatedMinutes != 45 || !updated.EstimateOverridden {
+		t.Errorf("Expected new word count with the override kept, got %d words, %d minutes, overridden=%v",
+			updated.WordCount, updated.EstimatedMinutes, updated.EstimateOverridden)
+	}
+
+	// Clearing the override restores the computed estimate
+	updated, err = service.SetEstimateOverride(assignment.ID, instructor.ID, nil)
+	if err != nil {
+		t.Fatalf("Failed to clear override: %v", err)
+	}
+	if updated.EstimatedMinutes != 2 || updated.EstimateOverridden {
+		t.Errorf("Expected computed estimate of 2 minutes, got %d (overridden=%v)", updated.EstimatedMinutes, updated.EstimateOverridden)
+	}
+
+	other := createTestUser(t, db, "instructor2", "instructor")
+	if _, err := service.SetEstimateOverride(assignment.ID, other.ID, &override); err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected access denied for another instructor, got %v", err)
+	}
+}
+
+func TestReadingWorkloadThisWeek(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	dueDateService := NewDueDateNotificationService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	soon := time.Now().AddDate(0, 0, 3)
+	later := time.Now().AddDate(0, 0, 20)
+	readings := []struct {
+		title   string
+		due     *time.Time
+		minutes int
+	}{
+		{"Due Soon", &soon, 20},
+		{"Also Due Soon", &soon, 15},
+		{"Due Later", &later, 60},
+	}
+
+	for _, reading := range readings {
+		assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+			Title:   reading.title,
+			URL:     "https://example.com/" + strings.ReplaceAll(reading.title, " ", "-"),
+			DueDate: reading.due,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		minutes := reading.minutes
+		if _, err := NewContentExtractionService(db, ContentExtractionOptions{}).SetEstimateOverride(assignment.ID, instructor.ID, &minutes); err != nil {
+			t.Fatalf("Failed to set estimate: %v", err)
+		}
+		if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign student: %v", err)
+		}
+		if reading.title == "Also Due Soon" {
+			if err := studentService.MarkAsCompleted(assignment.ID, student.ID); err != nil {
+				t.Fatalf("Failed to complete assignment: %v", err)
+			}
+		}
+	}
+
+	summary, err := dueDateService.GetDueDateSummary(student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get due date summary: %v", err)
+	}
+	if summary.WorkloadMinutesThisWeek != 20 {
+		t.Errorf("Expected 20 minutes of reading due this week, got %d", summary.WorkloadMinutesThisWeek)
+	}
+
+	stats, err := studentService.GetDashboardStats(student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get dashboard stats: %v", err)
+	}
+	if stats["workload_minutes_this_week"] != 20 {
+		t.Errorf("Expected 20 minutes of workload in dashboard stats, got %d", stats["workload_minutes_this_week"])
+	}
+}
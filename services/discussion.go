@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// maxPostLength limits the size of a discussion post in bytes
+const maxPostLength = 20000
+
+// DiscussionService handles the discussion threads on assignments
+type DiscussionService struct {
+	db *gorm.DB
+}
+
+// NewDiscussionService creates a new discussion service
+func NewDiscussionService(db *gorm.DB) *DiscussionService {
+	return &DiscussionService{db: db}
+}
+
+// CreatePostInput represents a new thread, or a reply when ParentID is set
+type CreatePostInput struct {
+	ParentID *uint
+	Content  string
+}
+
+// GetThreads returns an assignment's discussion as threads of nested replies. Pinned
+// threads come first, then the newest; pinned replies come before the rest in the order
+// they were written. Students see hidden posts without their content.
+func (s *DiscussionService) GetThreads(assignmentID uint, user *models.User) ([]models.DiscussionPost, error) {
+	_, moderator, err := authorizeAssignmentMember(s.db, assignmentID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := models.GetDiscussionPosts(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.DiscussionPost)
+	var roots []models.DiscussionPost
+	for _, post := range posts {
+		preparePost(&post, moderator)
+		if post.ParentID == nil {
+			roots = append(roots, post)
+		} else {
+			children[*post.ParentID] = append(children[*post.ParentID], post)
+		}
+	}
+
+	var build func(post models.DiscussionPost) (models.DiscussionPost, bool)
+	build = func(post models.DiscussionPost) (models.DiscussionPost, bool) {
+		for _, child := range children[post.ID] {
+			if reply, keep := build(child); keep {
+				post.Replies = append(post.Replies, reply)
+			}
+		}
+		sort.SliceStable(post.Replies, func(i, j int) bool {
+			return post.Replies[i].Pinned && !post.Replies[j].Pinned
+		})
+		// A deleted post only stays to hold the replies written to it
+		return post, !post.Deleted || len(post.Replies) > 0
+	}
+
+	threads := []models.DiscussionPost{}
+	for _, root := range roots {
+		if thread, keep := build(root); keep {
+			threads = append(threads, thread)
+		}
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Pinned != threads[j].Pinned {
+			return threads[i].Pinned
+		}
+		return threads[i].CreatedAt.After(threads[j].CreatedAt)
+	})
+
+	return threads, nil
+}
+
+// CreatePost starts a thread or replies to a post and notifies the thread's participants
+func (s *DiscussionService) CreatePost(assignmentID uint, user *models.User, input CreatePostInput) (*models.DiscussionPost, error) {
+	assignment, moderator, err := authorizeAssignmentMember(s.db, assignmentID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := validatePostContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.DiscussionPost{
+		AssignmentID: assignmentID,
+		AuthorID:     user.ID,
+		Content:      content,
+		ContentHTML:  renderMarkdown(content),
+	}
+
+	if input.ParentID != nil {
+		parent, err := models.GetDiscussionPost(s.db, *input.ParentID)
+		if err != nil || parent.AssignmentID != assignmentID || (parent.Hidden && !moderator) {
+			return nil, errors.New("post not found")
+		}
+		threadID := parent.RootID()
+		post.ParentID = &parent.ID
+		post.ThreadID = &threadID
+	}
+
+	if err := models.CreateDiscussionPost(s.db, post); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyParticipants(assignment, post, user); err != nil {
+		log.Printf("failed to send discussion notifications for post %d: %v", post.ID, err)
+	}
+
+	post.Author = *user
+	preparePost(post, moderator)
+	return post, nil
+}
+
+// EditPost changes the content of the user's own post, keeping the previous version
+func (s *DiscussionService) EditPost(postID uint, user *models.User, content string) (*models.DiscussionPost, error) {
+	post, moderator, err := s.getPost(postID, user)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != user.ID {
+		return nil, errors.New("access denied")
+	}
+
+	content, err = validatePostContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.EditDiscussionPost(s.db, post, user.ID, content, renderMarkdown(content)); err != nil {
+		return nil, err
+	}
+
+	preparePost(post, moderator)
+	return post, nil
+}
+
+// DeletePost removes a post; authors may delete their own and instructors any on their assignment
+func (s *DiscussionService) DeletePost(postID uint, user *models.User) error {
+	post, moderator, err := s.getPost(postID, user)
+	if err != nil {
+		return err
+	}
+	if post.AuthorID != user.ID && !moderator {
+		return errors.New("access denied")
+	}
+	return models.DeleteDiscussionPost(s.db, post)
+}
+
+// GetPostHistory returns the earlier versions of a post to its author or the instructor
+func (s *DiscussionService) GetPostHistory(postID uint, user *models.User) ([]models.DiscussionRevision, error) {
+	post, moderator, err := s.getPost(postID, user)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != user.ID && !moderator {
+		return nil, errors.New("access denied")
+	}
+	return models.GetDiscussionRevisions(s.db, post.ID)
+}
+
+// PinPost pins or unpins a post; a pinned reply is shown as the answer to its thread
+func (s *DiscussionService) PinPost(postID uint, user *models.User, pinned bool) (*models.DiscussionPost, error) {
+	post, moderator, err := s.getPost(postID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !moderator {
+		return nil, errors.New("access denied")
+	}
+
+	wasPinned := post.Pinned
+	if err := models.SetDiscussionPostPinned(s.db, post, pinned); err != nil {
+		return nil, err
+	}
+
+	if pinned && !wasPinned && post.ThreadID != nil {
+		if err := s.notifyAnswer(post); err != nil {
+			log.Printf("failed to send answer notification for post %d: %v", post.ID, err)
+		}
+	}
+
+	preparePost(post, moderator)
+	return post, nil
+}
+
+// HidePost hides a post from students or shows it again
+func (s *DiscussionService) HidePost(postID uint, user *models.User, hidden bool) (*models.DiscussionPost, error) {
+	post, moderator, err := s.getPost(postID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !moderator {
+		return nil, errors.New("access denied")
+	}
+
+	if err := models.SetDiscussionPostHidden(s.db, post, hidden, user.ID); err != nil {
+		return nil, err
+	}
+
+	preparePost(post, moderator)
+	return post, nil
+}
+
+// getPost loads a post the user can see and reports whether they moderate its discussion
+func (s *DiscussionService) getPost(postID uint, user *models.User) (*models.DiscussionPost, bool, error) {
+	post, err := models.GetDiscussionPost(s.db, postID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, moderator, err := authorizeAssignmentMember(s.db, post.AssignmentID, user)
+	if err != nil {
+		return nil, false, err
+	}
+	if post.Hidden && !moderator && post.AuthorID != user.ID {
+		return nil, false, errors.New("post not found")
+	}
+	return post, moderator, nil
+}
+
+// notifyParticipants tells the instructor about a new thread, and everyone who posted in a
+// thread about a new reply
+func (s *DiscussionService) notifyParticipants(assignment *models.Assignment, post *models.DiscussionPost, author *models.User) error {
+	assignmentID := assignment.ID
+
+	if post.ThreadID == nil {
+		if assignment.CreatedByID == author.ID {
+			return nil
+		}
+		message := fmt.Sprintf("💬 %s started a discussion on '%s'", author.Username, assignment.Title)
+		_, err := models.CreateNotification(s.db, assignment.CreatedByID, models.NotificationDiscussionPost, message, &assignmentID)
+		return err
+	}
+
+	participants, err := models.GetDiscussionParticipants(s.db, *post.ThreadID)
+	if err != nil {
+		return err
+	}
+	recipients := append(participants, assignment.CreatedByID)
+
+	notified := map[uint]bool{author.ID: true}
+	message := fmt.Sprintf("💬 %s replied to a discussion on '%s'", author.Username, assignment.Title)
+	for _, userID := range recipients {
+		if notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		if _, err := models.CreateNotification(s.db, userID, models.NotificationDiscussionReply, message, &assignmentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyAnswer tells the author of a thread that the instructor pinned an answer to it
+func (s *DiscussionService) notifyAnswer(post *models.DiscussionPost) error {
+	thread, err := models.GetDiscussionPost(s.db, *post.ThreadID)
+	if err != nil {
+		return err
+	}
+	assignment, err := models.GetAssignmentByID(s.db, post.AssignmentID)
+	if err != nil {
+		return err
+	}
+
+	assignmentID := assignment.ID
+	message := fmt.Sprintf("📌 Your question on '%s' has a pinned answer", assignment.Title)
+	_, err = models.CreateNotification(s.db, thread.AuthorID, models.NotificationDiscussionAnswer, message, &assignmentID)
+	return err
+}
+
+// preparePost fills in the author fields and removes content the viewer may not read
+func preparePost(post *models.DiscussionPost, moderator bool) {
+	post.AuthorName = post.Author.Username
+	post.AuthorRole = post.Author.Role
+	post.Deleted = post.DeletedAt.Valid
+	if post.Deleted || (post.Hidden && !moderator) {
+		post.Content = ""
+		post.ContentHTML = ""
+	}
+}
+
+// validatePostContent trims a post and checks its length
+func validatePostContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("post cannot be empty")
+	}
+	if len(content) > maxPostLength {
+		return "", errors.New("post is too long")
+	}
+	return content, nil
+}
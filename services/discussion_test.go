@@ -0,0 +1,164 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestDiscussionThreads(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewDiscussionService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	classmate := createTestUser(t, db, "student2", "student")
+	outsider := createTestUser(t, db, "student3", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{student.ID, classmate.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	question, err := service.CreatePost(assignment.ID, student, CreatePostInput{Content: "Why use **channels**? <script>x</script>"})
+	if err != nil {
+		t.Fatalf("Failed to start thread: %v", err)
+	}
+	if !strings.Contains(question.ContentHTML, "<strong>channels</strong>") || strings.Contains(question.ContentHTML, "<script>") {
+		t.Errorf("Expected sanitized rendering, got %q", question.ContentHTML)
+	}
+
+	if _, err := service.CreatePost(assignment.ID, outsider, CreatePostInput{Content: "Can I join?"}); err == nil {
+		t.Error("Expected students without the assignment to be denied")
+	}
+	if _, err := service.GetThreads(assignment.ID, otherInstructor); err == nil {
+		t.Error("Expected other instructors to be denied")
+	}
+	if _, err := service.CreatePost(assignment.ID, student, CreatePostInput{Content: "   "}); err == nil {
+		t.Error("Expected empty posts to be rejected")
+	}
+
+	reply, err := service.CreatePost(assignment.ID, classmate, CreatePostInput{ParentID: &question.ID, Content: "To share memory by communicating"})
+	if err != nil {
+		t.Fatalf("Failed to reply: %v", err)
+	}
+	answer, err := service.CreatePost(assignment.ID, instructor, CreatePostInput{ParentID: &reply.ID, Content: "Exactly, see the Go blog"})
+	if err != nil {
+		t.Fatalf("Failed to reply to reply: %v", err)
+	}
+	if answer.ThreadID == nil || *answer.ThreadID != question.ID {
+		t.Errorf("Expected nested replies to belong to the thread, got %+v", answer.ThreadID)
+	}
+
+	// The instructor hears about the new thread and its first reply; the asker hears about both replies
+	instructorNotes, _ := models.GetNotificationsByUser(db, instructor.ID, true)
+	studentNotes, _ := models.GetNotificationsByUser(db, student.ID, true)
+	classmateNotes, _ := models.GetNotificationsByUser(db, classmate.ID, true)
+	if len(instructorNotes) != 2 || len(studentNotes) != 2 || len(classmateNotes) != 1 {
+		t.Errorf("Expected 2/2/1 notifications, got %d/%d/%d", len(instructorNotes), len(studentNotes), len(classmateNotes))
+	}
+
+	if _, err := service.PinPost(answer.ID, student, true); err == nil {
+		t.Error("Expected students to be unable to pin")
+	}
+	if _, err := service.PinPost(answer.ID, instructor, true); err != nil {
+		t.Fatalf("Failed to pin answer: %v", err)
+	}
+	studentNotes, _ = models.GetNotificationsByUser(db, student.ID, true)
+	if len(studentNotes) != 3 || studentNotes[0].Type != models.NotificationDiscussionAnswer {
+		t.Errorf("Expected the asker to hear about the pinned answer, got %+v", studentNotes)
+	}
+
+	threads, err := service.GetThreads(assignment.ID, classmate)
+	if err != nil {
+		t.Fatalf("Failed to get threads: %v", err)
+	}
+	if len(threads) != 1 || len(threads[0].Replies) != 1 || len(threads[0].Replies[0].Replies) != 1 {
+		t.Fatalf("Expected one thread with a nested reply, got %+v", threads)
+	}
+	if !threads[0].Replies[0].Replies[0].Pinned || threads[0].AuthorName != "student1" {
+		t.Errorf("Expected a pinned answer and author names, got %+v", threads[0])
+	}
+}
+
+func TestDiscussionEditingAndModeration(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewDiscussionService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	classmate := createTestUser(t, db, "student2", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{student.ID, classmate.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+
+	post, err := service.CreatePost(assignment.ID, student, CreatePostInput{Content: "First draft"})
+	if err != nil {
+		t.Fatalf("Failed to create post: %v", err)
+	}
+	reply, err := service.CreatePost(assignment.ID, classmate, CreatePostInput{ParentID: &post.ID, Content: "Off topic"})
+	if err != nil {
+		t.Fatalf("Failed to reply: %v", err)
+	}
+
+	if _, err := service.EditPost(post.ID, classmate, "Not mine"); err == nil {
+		t.Error("Expected other students to be unable to edit")
+	}
+	edited, err := service.EditPost(post.ID, student, "Second draft")
+	if err != nil {
+		t.Fatalf("Failed to edit post: %v", err)
+	}
+	if edited.Content != "Second draft" || edited.EditedAt == nil {
+		t.Errorf("Expected the edit to be recorded, got %+v", edited)
+	}
+	history, err := service.GetPostHistory(post.ID, instructor)
+	if err != nil || len(history) != 1 || history[0].Content != "First draft" {
+		t.Errorf("Expected the earlier version in the history, got %+v (%v)", history, err)
+	}
+
+	if _, err := service.HidePost(reply.ID, classmate, true); err == nil {
+		t.Error("Expected students to be unable to hide posts")
+	}
+	if _, err := service.HidePost(reply.ID, instructor, true); err != nil {
+		t.Fatalf("Failed to hide reply: %v", err)
+	}
+	threads, _ := service.GetThreads(assignment.ID, student)
+	if hidden := threads[0].Replies[0]; !hidden.Hidden || hidden.Content != "" {
+		t.Errorf("Expected students to see the hidden reply without content, got %+v", hidden)
+	}
+	threads, _ = service.GetThreads(assignment.ID, instructor)
+	if hidden := threads[0].Replies[0]; hidden.Content != "Off topic" {
+		t.Errorf("Expected the instructor to still read hidden posts, got %+v", hidden)
+	}
+	if _, err := service.CreatePost(assignment.ID, student, CreatePostInput{ParentID: &reply.ID, Content: "Reply"}); err == nil {
+		t.Error("Expected students to be unable to reply to hidden posts")
+	}
+
+	if err := service.DeletePost(post.ID, classmate); err == nil {
+		t.Error("Expected other students to be unable to delete")
+	}
+	if err := service.DeletePost(post.ID, student); err != nil {
+		t.Fatalf("Failed to delete post: %v", err)
+	}
+	threads, _ = service.GetThreads(assignment.ID, student)
+	if len(threads) != 1 || !threads[0].Deleted || threads[0].Content != "" || len(threads[0].Replies) != 1 {
+		t.Errorf("Expected the deleted thread to stay as a placeholder for its reply, got %+v", threads)
+	}
+
+	if err := service.DeletePost(reply.ID, instructor); err != nil {
+		t.Fatalf("Failed to delete reply as instructor: %v", err)
+	}
+	threads, _ = service.GetThreads(assignment.ID, student)
+	if len(threads) != 0 {
+		t.Errorf("Expected a deleted thread without replies to disappear, got %+v", threads)
+	}
+}
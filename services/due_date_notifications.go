@@ -20,29 +20,31 @@ func NewDueDateNotificationService(db *gorm.DB) *DueDateNotificationService {
 
 // DueDateAlert represents a due date alert
 type DueDateAlert struct {
-	StudentID       uint      `json:"student_id"`
-	StudentName     string    `json:"student_name"`
-	StudentEmail    string    `json:"student_email"`
-	AssignmentID    uint      `json:"assignment_id"`
-	AssignmentTitle string    `json:"assignment_title"`
-	AssignmentURL   string    `json:"assignment_url"`
-	DueDate         time.Time `json:"due_date"`
-	DaysUntilDue    int       `json:"days_until_due"`
-	Status          string    `json:"status"`
-	AlertType       string    `json:"alert_type"` // "upcoming", "overdue", "due_today"
-	Priority        string    `json:"priority"`   // "low", "medium", "high", "critical"
+	StudentID        uint      `json:"student_id"`
+	StudentName      string    `json:"student_name"`
+	StudentEmail     string    `json:"student_email"`
+	AssignmentID     uint      `json:"assignment_id"`
+	AssignmentTitle  string    `json:"assignment_title"`
+	AssignmentURL    string    `json:"assignment_url"`
+	DueDate          time.Time `json:"due_date"`
+	DaysUntilDue     int       `json:"days_until_due"`
+	EstimatedMinutes int       `json:"estimated_minutes"`
+	Status           string    `json:"status"`
+	AlertType        string    `json:"alert_type"` // "upcoming", "overdue", "due_today"
+	Priority         string    `json:"priority"`   // "low", "medium", "high", "critical"
 }
 
 // DueDateSummary provides summary of due date information
 type DueDateSummary struct {
-	TotalUpcoming  int            `json:"total_upcoming"`
-	DueToday       int            `json:"due_today"`
-	DueTomorrow    int            `json:"due_tomorrow"`
-	DueThisWeek    int            `json:"due_this_week"`
-	Overdue        int            `json:"overdue"`
-	UpcomingAlerts []DueDateAlert `json:"upcoming_alerts"`
-	OverdueAlerts  []DueDateAlert `json:"overdue_alerts"`
-	DueTodayAlerts []DueDateAlert `json:"due_today_alerts"`
+	TotalUpcoming           int            `json:"total_upcoming"`
+	DueToday                int            `json:"due_today"`
+	DueTomorrow             int            `json:"due_tomorrow"`
+	DueThisWeek             int            `json:"due_this_week"`
+	WorkloadMinutesThisWeek int            `json:"workload_minutes_this_week"` // estimated reading time of work due within 7 days
+	Overdue                 int            `json:"overdue"`
+	UpcomingAlerts          []DueDateAlert `json:"upcoming_alerts"`
+	OverdueAlerts           []DueDateAlert `json:"overdue_alerts"`
+	DueTodayAlerts          []DueDateAlert `json:"due_today_alerts"`
 }
 
 // GetUpcomingDueDateAlerts retrieves upcoming due date alerts for a student
@@ -55,14 +57,16 @@ func (s *DueDateNotificationService) GetUpcomingDueDateAlerts(studentID uint, da
 	cutoffDate := time.Now().AddDate(0, 0, daysAhead)
 
 	type AlertResult struct {
-		StudentID       uint      `json:"student_id"`
-		StudentName     string    `json:"student_name"`
-		StudentEmail    string    `json:"student_email"`
-		AssignmentID    uint      `json:"assignment_id"`
-		AssignmentTitle string    `json:"assignment_title"`
-		AssignmentURL   string    `json:"assignment_url"`
-		DueDate         time.Time `json:"due_date"`
-		Status          string    `json:"status"`
+		StudentID         uint       `json:"student_id"`
+		StudentName       string     `json:"student_name"`
+		StudentEmail      string     `json:"student_email"`
+		AssignmentID      uint       `json:"assignment_id"`
+		AssignmentTitle   string     `json:"assignment_title"`
+		AssignmentURL     string     `json:"assignment_url"`
+		AssignmentDueDate *time.Time `json:"assignment_due_date"`
+		DueDateOverride   *time.Time `json:"due_date_override"`
+		Status            string     `json:"status"`
+		EstimatedMinutes  int        `json:"estimated_minutes"`
 	}
 
 	var results []AlertResult
@@ -70,12 +74,14 @@ func (s *DueDateNotificationService) GetUpcomingDueDateAlerts(studentID uint, da
 	err := s.db.Table("student_assignments").
 		Select("student_assignments.student_id, users.username as student_name, users.email as student_email, "+
 			"assignments.id as assignment_id, assignments.title as assignment_title, assignments.url as assignment_url, "+
-			"assignments.due_date, student_assignments.status").
+			"assignments.due_date as assignment_due_date, student_assignments.due_date_override, student_assignments.status, "+
+			"assignments.estimated_minutes").
 		Joins("JOIN users ON users.id = student_assignments.student_id").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
-		Where("student_assignments.student_id = ? AND assignments.due_date IS NOT NULL AND assignments.due_date >= ? AND assignments.due_date <= ? AND student_assignments.status != ?",
-			studentID, time.Now(), cutoffDate, models.StatusCompleted).
-		Order("assignments.due_date ASC").
+		Scopes(models.PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND "+models.EffectiveDueDateSQL+" IS NOT NULL AND "+models.EffectiveDueDateSQL+" >= ? AND "+models.EffectiveDueDateSQL+" <= ? AND student_assignments.status != ? AND student_assignments.excused = ?",
+			studentID, time.Now(), cutoffDate, models.StatusCompleted, false).
+		Order(models.EffectiveDueDateSQL + " ASC").
 		Find(&results).Error
 
 	if err != nil {
@@ -83,7 +89,8 @@ func (s *DueDateNotificationService) GetUpcomingDueDateAlerts(studentID uint, da
 	}
 
 	for _, result := range results {
-		daysUntil := int(result.DueDate.Sub(time.Now()).Hours() / 24)
+		dueDate := effectiveDueDate(result.AssignmentDueDate, result.DueDateOverride)
+		daysUntil := int(dueDate.Sub(time.Now()).Hours() / 24)
 
 		alertType := "upcoming"
 		priority := "low"
@@ -99,17 +106,18 @@ func (s *DueDateNotificationService) GetUpcomingDueDateAlerts(studentID uint, da
 		}
 
 		alerts = append(alerts, DueDateAlert{
-			StudentID:       result.StudentID,
-			StudentName:     result.StudentName,
-			StudentEmail:    result.StudentEmail,
-			AssignmentID:    result.AssignmentID,
-			AssignmentTitle: result.AssignmentTitle,
-			AssignmentURL:   result.AssignmentURL,
-			DueDate:         result.DueDate,
-			DaysUntilDue:    daysUntil,
-			Status:          result.Status,
-			AlertType:       alertType,
-			Priority:        priority,
+			StudentID:        result.StudentID,
+			StudentName:      result.StudentName,
+			StudentEmail:     result.StudentEmail,
+			AssignmentID:     result.AssignmentID,
+			AssignmentTitle:  result.AssignmentTitle,
+			AssignmentURL:    result.AssignmentURL,
+			DueDate:          dueDate,
+			DaysUntilDue:     daysUntil,
+			EstimatedMinutes: result.EstimatedMinutes,
+			Status:           result.Status,
+			AlertType:        alertType,
+			Priority:         priority,
 		})
 	}
 
@@ -121,14 +129,16 @@ func (s *DueDateNotificationService) GetOverdueDueDateAlerts(studentID uint) ([]
 	var alerts []DueDateAlert
 
 	type AlertResult struct {
-		StudentID       uint      `json:"student_id"`
-		StudentName     string    `json:"student_name"`
-		StudentEmail    string    `json:"student_email"`
-		AssignmentID    uint      `json:"assignment_id"`
-		AssignmentTitle string    `json:"assignment_title"`
-		AssignmentURL   string    `json:"assignment_url"`
-		DueDate         time.Time `json:"due_date"`
-		Status          string    `json:"status"`
+		StudentID         uint       `json:"student_id"`
+		StudentName       string     `json:"student_name"`
+		StudentEmail      string     `json:"student_email"`
+		AssignmentID      uint       `json:"assignment_id"`
+		AssignmentTitle   string     `json:"assignment_title"`
+		AssignmentURL     string     `json:"assignment_url"`
+		AssignmentDueDate *time.Time `json:"assignment_due_date"`
+		DueDateOverride   *time.Time `json:"due_date_override"`
+		Status            string     `json:"status"`
+		GracePeriod       int        `json:"grace_period_minutes"`
 	}
 
 	var results []AlertResult
@@ -136,12 +146,13 @@ func (s *DueDateNotificationService) GetOverdueDueDateAlerts(studentID uint) ([]
 	err := s.db.Table("student_assignments").
 		Select("student_assignments.student_id, users.username as student_name, users.email as student_email, "+
 			"assignments.id as assignment_id, assignments.title as assignment_title, assignments.url as assignment_url, "+
-			"assignments.due_date, student_assignments.status").
+			"assignments.due_date as assignment_due_date, student_assignments.due_date_override, student_assignments.status, assignments.grace_period_minutes as grace_period").
 		Joins("JOIN users ON users.id = student_assignments.student_id").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
-		Where("student_assignments.student_id = ? AND assignments.due_date IS NOT NULL AND assignments.due_date < ? AND student_assignments.status != ?",
-			studentID, time.Now(), models.StatusCompleted).
-		Order("assignments.due_date ASC").
+		Scopes(models.PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND "+models.EffectiveDueDateSQL+" IS NOT NULL AND "+models.EffectiveDueDateSQL+" < ? AND student_assignments.status != ? AND student_assignments.excused = ?",
+			studentID, time.Now(), models.StatusCompleted, false).
+		Order(models.EffectiveDueDateSQL + " ASC").
 		Find(&results).Error
 
 	if err != nil {
@@ -149,7 +160,14 @@ func (s *DueDateNotificationService) GetOverdueDueDateAlerts(studentID uint) ([]
 	}
 
 	for _, result := range results {
-		daysPastDue := int(time.Now().Sub(result.DueDate).Hours() / 24)
+		dueDate := effectiveDueDate(result.AssignmentDueDate, result.DueDateOverride)
+
+		// Still within the grace period, so not overdue yet
+		if time.Now().Before(dueDate.Add(time.Duration(result.GracePeriod) * time.Minute)) {
+			continue
+		}
+
+		daysPastDue := int(time.Now().Sub(dueDate).Hours() / 24)
 
 		priority := "high"
 		if daysPastDue > 7 {
@@ -163,7 +181,7 @@ func (s *DueDateNotificationService) GetOverdueDueDateAlerts(studentID uint) ([]
 			AssignmentID:    result.AssignmentID,
 			AssignmentTitle: result.AssignmentTitle,
 			AssignmentURL:   result.AssignmentURL,
-			DueDate:         result.DueDate,
+			DueDate:         dueDate,
 			DaysUntilDue:    -daysPastDue, // Negative for overdue
 			Status:          result.Status,
 			AlertType:       "overdue",
@@ -174,6 +192,15 @@ func (s *DueDateNotificationService) GetOverdueDueDateAlerts(studentID uint) ([]
 	return alerts, nil
 }
 
+// effectiveDueDate returns the per-student override when present, otherwise the assignment due date.
+// Queries only return rows where at least one of them is set.
+func effectiveDueDate(assignmentDueDate, override *time.Time) time.Time {
+	if override != nil {
+		return *override
+	}
+	return *assignmentDueDate
+}
+
 // GetDueDateSummary provides a comprehensive summary of due date information for a student
 func (s *DueDateNotificationService) GetDueDateSummary(studentID uint) (*DueDateSummary, error) {
 	summary := &DueDateSummary{}
@@ -204,6 +231,7 @@ func (s *DueDateNotificationService) GetDueDateSummary(studentID uint) (*DueDate
 
 		if alert.DaysUntilDue <= 7 {
 			dueThisWeek++
+			summary.WorkloadMinutesThisWeek += alert.EstimatedMinutes
 		}
 	}
 
@@ -237,40 +265,58 @@ func (s *DueDateNotificationService) GetInstructorDueDateOverview(instructorID u
 	var upcomingDeadlines []map[string]interface{}
 	var overdueList []map[string]interface{}
 
+	// Count the students of every assignment with a due date in one grouped query, and load
+	// only the unfinished readings of past-due assignments, which may be overdue
+	now := time.Now()
+	var dueIDs, pastDueIDs []uint
+	for _, assignment := range assignments {
+		if assignment.DueDate == nil {
+			continue
+		}
+		dueIDs = append(dueIDs, assignment.ID)
+		if assignment.DueDate.Before(now) {
+			pastDueIDs = append(pastDueIDs, assignment.ID)
+		}
+	}
+	counts, err := models.GetAssignmentCompletionCounts(s.db, dueIDs)
+	if err != nil {
+		return nil, err
+	}
+	unfinished, err := models.GetUnfinishedStudentAssignmentStatuses(s.db, pastDueIDs)
+	if err != nil {
+		return nil, err
+	}
+	unfinishedByAssignment := make(map[uint][]models.StudentAssignment, len(pastDueIDs))
+	for _, sa := range unfinished {
+		unfinishedByAssignment[sa.AssignmentID] = append(unfinishedByAssignment[sa.AssignmentID], sa)
+	}
+
 	for _, assignment := range assignments {
 		if assignment.DueDate != nil {
 			assignmentsWithDueDates++
+			count := counts[assignment.ID]
 
 			// Check if upcoming (within 7 days)
-			if assignment.DueDate.After(time.Now()) && assignment.DueDate.Before(time.Now().AddDate(0, 0, 7)) {
+			if assignment.DueDate.After(now) && assignment.DueDate.Before(now.AddDate(0, 0, 7)) {
 				upcomingDueDates++
 
-				// Get student count for this assignment
-				studentAssignments, _ := models.GetStudentAssignmentsByAssignment(s.db, assignment.ID)
-				incompleteCount := 0
-				for _, sa := range studentAssignments {
-					if sa.Status != models.StatusCompleted {
-						incompleteCount++
-					}
-				}
-
 				upcomingDeadlines = append(upcomingDeadlines, map[string]interface{}{
 					"assignment_id":    assignment.ID,
 					"title":            assignment.Title,
 					"due_date":         assignment.DueDate,
-					"days_until_due":   int(assignment.DueDate.Sub(time.Now()).Hours() / 24),
-					"incomplete_count": incompleteCount,
-					"total_students":   len(studentAssignments),
+					"days_until_due":   int(assignment.DueDate.Sub(now).Hours() / 24),
+					"incomplete_count": count.Incomplete,
+					"total_students":   count.Total,
 				})
 			}
 
 			// Check if overdue
-			if assignment.DueDate.Before(time.Now()) {
-				// Get student count for this assignment
-				studentAssignments, _ := models.GetStudentAssignmentsByAssignment(s.db, assignment.ID)
+			if assignment.DueDate.Before(now) {
+				// Count students past their effective due date (extensions, excuses and grace period applied)
 				incompleteCount := 0
-				for _, sa := range studentAssignments {
-					if sa.Status != models.StatusCompleted {
+				for _, sa := range unfinishedByAssignment[assignment.ID] {
+					sa.Assignment = assignment
+					if sa.IsOverdue() {
 						incompleteCount++
 					}
 				}
@@ -281,9 +327,9 @@ func (s *DueDateNotificationService) GetInstructorDueDateOverview(instructorID u
 						"assignment_id":    assignment.ID,
 						"title":            assignment.Title,
 						"due_date":         assignment.DueDate,
-						"days_overdue":     int(time.Now().Sub(*assignment.DueDate).Hours() / 24),
+						"days_overdue":     int(now.Sub(*assignment.DueDate).Hours() / 24),
 						"incomplete_count": incompleteCount,
-						"total_students":   len(studentAssignments),
+						"total_students":   count.Total,
 					})
 				}
 			}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestGetInstructorDueDateOverview(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewDueDateNotificationService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	students := []uint{
+		createTestUser(t, db, "student1", "student").ID,
+		createTestUser(t, db, "student2", "student").ID,
+		createTestUser(t, db, "student3", "student").ID,
+	}
+
+	now := time.Now()
+	create := func(title string, dueDate *time.Time) *models.Assignment {
+		assignment := &models.Assignment{Title: title, URL: "https://example.com/" + title, DueDate: dueDate, CreatedByID: instructor.ID}
+		if err := db.Create(assignment).Error; err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		if err := assignmentService.AssignToMultipleStudents(assignment.ID, students, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign students: %v", err)
+		}
+		return assignment
+	}
+	update := func(assignmentID, studentID uint, updates map[string]interface{}) {
+		db.Model(&models.StudentAssignment{}).Where("assignment_id = ? AND student_id = ?", assignmentID, studentID).Updates(updates)
+	}
+
+	upcomingDue := now.AddDate(0, 0, 2)
+	pastDue := now.AddDate(0, 0, -2)
+	upcoming := create("Upcoming", &upcomingDue)
+	overdue := create("Overdue", &pastDue)
+	extended := create("Extended", &pastDue)
+	create("Undated", nil)
+
+	update(upcoming.ID, students[0], map[string]interface{}{"status": models.StatusCompleted, "completed_at": now})
+	// One student finished and one is excused, leaving one overdue
+	update(overdue.ID, students[0], map[string]interface{}{"status": models.StatusCompleted, "completed_at": now})
+	update(overdue.ID, students[1], map[string]interface{}{"excused": true})
+	// Everyone left on this reading has an extension
+	extension := now.AddDate(0, 0, 3)
+	update(extended.ID, students[0], map[string]interface{}{"status": models.StatusCompleted, "completed_at": now})
+	update(extended.ID, students[1], map[string]interface{}{"status": models.StatusCompleted, "completed_at": now})
+	update(extended.ID, students[2], map[string]interface{}{"due_date_override": extension})
+
+	overview, err := service.GetInstructorDueDateOverview(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get overview: %v", err)
+	}
+	if overview["total_assignments"] != 4 || overview["assignments_with_due_dates"] != 3 {
+		t.Errorf("Expected 4 assignments, 3 with due dates, got %v and %v", overview["total_assignments"], overview["assignments_with_due_dates"])
+	}
+
+	deadlines := overview["upcoming_deadlines"].([]map[string]interface{})
+	if len(deadlines) != 1 || deadlines[0]["incomplete_count"] != 2 || deadlines[0]["total_students"] != 3 {
+		t.Errorf("Expected one upcoming deadline with 2 of 3 students left, got %v", deadlines)
+	}
+
+	overdueList := overview["overdue_list"].([]map[string]interface{})
+	if overview["overdue_assignments"] != 1 || len(overdueList) != 1 {
+		t.Fatalf("Expected only the reading without extensions to be overdue, got %v", overdueList)
+	}
+	if overdueList[0]["assignment_id"] != overdue.ID || overdueList[0]["incomplete_count"] != 1 || overdueList[0]["total_students"] != 3 {
+		t.Errorf("Expected 1 of 3 students overdue, got %v", overdueList[0])
+	}
+}
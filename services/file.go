@@ -0,0 +1,232 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zipcodereader/models"
+	"zipcodereader/storage"
+
+	"github.com/gabriel-vasile/mimetype"
+	"gorm.io/gorm"
+)
+
+// ErrFileTooLarge is returned when an upload exceeds the size limit
+var ErrFileTooLarge = errors.New("file exceeds the upload size limit")
+
+// allowedUploadTypes lists the document types instructors may upload
+var allowedUploadTypes = []string{
+	"application/pdf",
+	"application/epub+zip",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"application/vnd.oasis.opendocument.text",
+	"text/plain",
+}
+
+// FileService stores uploaded assignment documents and controls who may download them
+type FileService struct {
+	db       *gorm.DB
+	storage  storage.Storage
+	maxBytes int64
+}
+
+// NewFileService creates a new file service
+func NewFileService(db *gorm.DB, store storage.Storage, maxBytes int64) *FileService {
+	return &FileService{db: db, storage: store, maxBytes: maxBytes}
+}
+
+// UploadFile stores a document for an instructor. The content type is sniffed from the
+// bytes and identical content is stored only once.
+func (s *FileService) UploadFile(ownerID uint, fileName string, content io.Reader) (*models.UploadedFile, error) {
+	// Validate owner exists and has instructor role
+	var owner models.User
+	if err := s.db.First(&owner, ownerID).Error; err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	if !owner.IsInstructor() {
+		return nil, errors.New("user is not an instructor")
+	}
+
+	fileName = filepath.Base(strings.TrimSpace(fileName))
+	if fileName == "" || fileName == "." || fileName == string(filepath.Separator) {
+		return nil, errors.New("file name is required")
+	}
+
+	// Spool to disk while hashing so large uploads are not held in memory
+	tmp, err := os.CreateTemp("", "zipcodereader-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(content, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxBytes {
+		return nil, ErrFileTooLarge
+	}
+	if size == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	detected, err := mimetype.DetectReader(tmp)
+	if err != nil {
+		return nil, err
+	}
+	contentType, ok := allowedUploadType(detected)
+	if !ok {
+		return nil, errors.New("unsupported file type " + detected.String())
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	key := "files/" + hash
+
+	existing, err := models.CountUploadedFilesByKey(s.db, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == 0 {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := s.storage.Put(key, tmp, size, contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	file := &models.UploadedFile{
+		OwnerID:     ownerID,
+		FileName:    fileName,
+		ContentType: contentType,
+		SizeBytes:   size,
+		SHA256:      hash,
+		StorageKey:  key,
+	}
+	if err := s.db.Create(file).Error; err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// GetFiles returns an instructor's uploads
+func (s *FileService) GetFiles(ownerID uint) ([]models.UploadedFile, error) {
+	return models.GetUploadedFilesByOwner(s.db, ownerID)
+}
+
+// OpenFile returns a file and its content when the user owns it or is assigned a released
+// assignment that uses it
+func (s *FileService) OpenFile(fileID uint, user *models.User) (*models.UploadedFile, io.ReadCloser, error) {
+	file, err := models.GetUploadedFileByID(s.db, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allowed := file.OwnerID == user.ID
+	if !allowed && user.IsStudent() {
+		allowed, err = models.StudentHasFileAccess(s.db, file.ID, user.ID, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if !allowed {
+		return nil, nil, errors.New("access denied")
+	}
+
+	content, err := s.storage.Get(file.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, content, nil
+}
+
+// DeleteFile removes an upload that no assignment uses; the stored object goes with its last upload
+func (s *FileService) DeleteFile(fileID uint, ownerID uint) error {
+	file, err := models.GetUploadedFileByID(s.db, fileID)
+	if err != nil {
+		return err
+	}
+
+	if file.OwnerID != ownerID {
+		return errors.New("access denied")
+	}
+
+	inUse, err := models.CountAssignmentsUsingFile(s.db, file.ID)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return errors.New("file is attached to an assignment")
+	}
+
+	if err := s.db.Delete(file).Error; err != nil {
+		return err
+	}
+
+	remaining, err := models.CountUploadedFilesByKey(s.db, file.StorageKey)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return s.storage.Delete(file.StorageKey)
+	}
+	return nil
+}
+
+// AttachFile makes an uploaded file the reading of an assignment
+func (s *FileService) AttachFile(assignmentID uint, instructorID uint, fileID uint) (*models.Assignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	file, err := checkFileOwner(s.db, fileID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := assignment.AttachFile(s.db, file); err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentByID(s.db, assignmentID)
+}
+
+// checkFileOwner returns the file when an instructor may attach it
+func checkFileOwner(db *gorm.DB, fileID uint, instructorID uint) (*models.UploadedFile, error) {
+	file, err := models.GetUploadedFileByID(db, fileID)
+	if err != nil || file.OwnerID != instructorID {
+		return nil, errors.New("file not found")
+	}
+	return file, nil
+}
+
+// allowedUploadType returns the canonical type of a sniffed document when uploads allow it.
+// Parent types are not consulted so that, for example, HTML is not accepted as plain text.
+func allowedUploadType(detected *mimetype.MIME) (string, bool) {
+	for _, allowed := range allowedUploadTypes {
+		if detected.Is(allowed) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"zipcodereader/models"
+	"zipcodereader/storage"
+
+	"gorm.io/gorm"
+)
+
+// testPDF is enough of a PDF for content sniffing
+var testPDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+func newTestFileService(t *testing.T, maxBytes int64) (*FileService, *gorm.DB) {
+	db := setupTestDB(t)
+	return NewFileService(db, storage.NewLocalStorage(t.TempDir()), maxBytes), db
+}
+
+func TestUploadFileSniffsAndHashes(t *testing.T) {
+	service, db := newTestFileService(t, 1<<20)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	file, err := service.UploadFile(instructor.ID, "../../reading.txt", bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+
+	if file.ContentType != "application/pdf" {
+		t.Errorf("Expected content type to be sniffed as application/pdf, got %s", file.ContentType)
+	}
+	if file.FileName != "reading.txt" {
+		t.Errorf("Expected directory components to be stripped, got %s", file.FileName)
+	}
+	if file.SizeBytes != int64(len(testPDF)) {
+		t.Errorf("Expected size %d, got %d", len(testPDF), file.SizeBytes)
+	}
+	if len(file.SHA256) != 64 || file.StorageKey != "files/"+file.SHA256 {
+		t.Errorf("Expected content-addressed storage key, got hash %q key %q", file.SHA256, file.StorageKey)
+	}
+
+	// Identical content shares one stored object
+	again, err := service.UploadFile(instructor.ID, "copy.pdf", bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("Failed to upload duplicate: %v", err)
+	}
+	if again.ID == file.ID || again.StorageKey != file.StorageKey {
+		t.Errorf("Expected a new upload record sharing the stored object")
+	}
+
+	files, err := service.GetFiles(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected 2 uploads, got %d", len(files))
+	}
+}
+
+func TestUploadFileRejectsUnsupportedTypes(t *testing.T) {
+	service, db := newTestFileService(t, 1<<20)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	for name, content := range map[string][]byte{
+		"page.pdf":    []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>"),
+		"program.pdf": append([]byte("MZ\x90\x00\x03\x00\x00\x00"), make([]byte, 128)...),
+	} {
+		if _, err := service.UploadFile(instructor.ID, name, bytes.NewReader(content)); err == nil || !strings.Contains(err.Error(), "unsupported file type") {
+			t.Errorf("Expected %s to be rejected as unsupported, got %v", name, err)
+		}
+	}
+
+	if _, err := service.UploadFile(instructor.ID, "empty.pdf", bytes.NewReader(nil)); err == nil {
+		t.Error("Expected empty upload to be rejected")
+	}
+
+	if _, err := service.UploadFile(student.ID, "reading.pdf", bytes.NewReader(testPDF)); err == nil {
+		t.Error("Expected students to be unable to upload files")
+	}
+}
+
+func TestUploadFileEnforcesSizeLimit(t *testing.T) {
+	service, db := newTestFileService(t, int64(len(testPDF))-1)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	_, err := service.UploadFile(instructor.ID, "reading.pdf", bytes.NewReader(testPDF))
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected ErrFileTooLarge, got %v", err)
+	}
+
+	var total int64
+	db.Model(&models.UploadedFile{}).Count(&total)
+	if total != 0 {
+		t.Errorf("Expected no upload to be recorded, got %d", total)
+	}
+}
+
+func TestOpenFileAccess(t *testing.T) {
+	service, db := newTestFileService(t, 1<<20)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	assigned := createTestUser(t, db, "student1", "student")
+	unassigned := createTestUser(t, db, "student2", "student")
+
+	file, err := service.UploadFile(instructor.ID, "reading.pdf", bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+
+	assignmentService := NewAssignmentService(db)
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:  "Chapter 1",
+		FileID: &file.ID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if assignment.URL != models.UploadedFilePath(file.ID) {
+		t.Errorf("Expected assignment URL %s, got %s", models.UploadedFilePath(file.ID), assignment.URL)
+	}
+	if err := assignmentService.AssignToStudent(assignment.ID, assigned.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+
+	for _, user := range []*models.User{instructor, assigned} {
+		_, content, err := service.OpenFile(file.ID, user)
+		if err != nil {
+			t.Fatalf("Expected %s to open the file, got %v", user.Username, err)
+		}
+		data, _ := io.ReadAll(content)
+		content.Close()
+		if !bytes.Equal(data, testPDF) {
+			t.Errorf("Expected %s to read the uploaded content", user.Username)
+		}
+	}
+
+	for _, user := range []*models.User{otherInstructor, unassigned} {
+		if _, _, err := service.OpenFile(file.ID, user); err == nil || err.Error() != "access denied" {
+			t.Errorf("Expected %s to be denied, got %v", user.Username, err)
+		}
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	service, db := newTestFileService(t, 1<<20)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+
+	file, err := service.UploadFile(instructor.ID, "reading.pdf", bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+	duplicate, err := service.UploadFile(instructor.ID, "copy.pdf", bytes.NewReader(testPDF))
+	if err != nil {
+		t.Fatalf("Failed to upload duplicate: %v", err)
+	}
+
+	assignmentService := NewAssignmentService(db)
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Chapter 1",
+		URL:   "https://example.com/chapter-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	if _, err := service.AttachFile(assignment.ID, otherInstructor.ID, file.ID); err == nil {
+		t.Error("Expected other instructor to be unable to attach the file")
+	}
+	if _, err := service.AttachFile(assignment.ID, instructor.ID, file.ID); err != nil {
+		t.Fatalf("Failed to attach file: %v", err)
+	}
+
+	if err := service.DeleteFile(file.ID, otherInstructor.ID); err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+	if err := service.DeleteFile(file.ID, instructor.ID); err == nil {
+		t.Error("Expected attached file to be kept")
+	}
+
+	// Removing the duplicate keeps the shared object for the attached upload
+	if err := service.DeleteFile(duplicate.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete duplicate: %v", err)
+	}
+	_, content, err := service.OpenFile(file.ID, instructor)
+	if err != nil {
+		t.Fatalf("Expected shared content to remain, got %v", err)
+	}
+	content.Close()
+
+	// Pointing the assignment back at a URL detaches the file
+	if err := assignmentService.UpdateAssignment(assignment.ID, instructor.ID, UpdateAssignmentInput{
+		Title: "Chapter 1",
+		URL:   "https://example.com/chapter-1",
+	}); err != nil {
+		t.Fatalf("Failed to update assignment: %v", err)
+	}
+	if err := service.DeleteFile(file.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete detached file: %v", err)
+	}
+	if _, err := service.storage.Get(file.StorageKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected stored object to be removed with its last upload, got %v", err)
+	}
+}
@@ -0,0 +1,642 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAssignmentPoints limits the point value of an assignment
+	maxAssignmentPoints = 1000
+	// maxGradingCriteria limits the length of a grading rubric
+	maxGradingCriteria = 20
+	// maxGradeFeedback limits the length of grading feedback in bytes
+	maxGradeFeedback = 10000
+	// maxCategoryWeight limits the weight of a gradebook category
+	maxCategoryWeight = 1000
+	// uncategorized names the gradebook category of assignments without one
+	uncategorized = "Uncategorized"
+)
+
+// GradingService handles points, rubrics, grades and the gradebook
+type GradingService struct {
+	db                       *gorm.DB
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewGradingService creates a new grading service
+func NewGradingService(db *gorm.DB) *GradingService {
+	return &GradingService{
+		db:                       db,
+		studentAssignmentService: NewStudentAssignmentService(db),
+	}
+}
+
+// RubricInput represents an instructor's grading rubric
+type RubricInput struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Criteria    []models.RubricCriterion `json:"criteria"`
+}
+
+// GradingPolicyInput represents the point value, rubric and late penalty rules of an assignment
+type GradingPolicyInput struct {
+	Points            int   `json:"points"`
+	RubricID          *uint `json:"rubric_id"`
+	LatePenaltyPerDay int   `json:"late_penalty_per_day"`
+	MaxLatePenalty    int   `json:"max_late_penalty"`
+}
+
+// GradeInput represents an instructor's score for a student. Assignments scored with a rubric
+// take a score per criterion; the others take a single score out of the assignment's points.
+type GradeInput struct {
+	Score           *float64  `json:"score"`
+	CriterionScores []float64 `json:"criterion_scores"`
+	Feedback        string    `json:"feedback"`
+}
+
+// GradeView is a grade with the late penalty applied to it
+type GradeView struct {
+	StudentAssignmentID uint           `json:"student_assignment_id"`
+	StudentID           uint           `json:"student_id"`
+	StudentName         string         `json:"student_name"`
+	Status              string         `json:"status"`
+	CompletedAt         *time.Time     `json:"completed_at"`
+	Timing              string         `json:"timing"`
+	Points              int            `json:"points"`
+	Rubric              *models.Rubric `json:"rubric,omitempty"`
+	Grade               *models.Grade  `json:"grade"` // nil until graded
+	LatePenaltyPercent  int            `json:"late_penalty_percent"`
+	Score               *float64       `json:"score"` // after the late penalty; nil until graded
+}
+
+// Gradebook lists every student's scores on an instructor's graded assignments with their
+// point totals and weighted category averages
+type Gradebook struct {
+	Assignments []GradebookAssignment `json:"assignments"`
+	Categories  []GradebookCategory   `json:"categories"`
+	Students    []GradebookStudent    `json:"students"`
+}
+
+// GradebookAssignment is a column of the gradebook
+type GradebookAssignment struct {
+	ID       uint       `json:"id"`
+	Title    string     `json:"title"`
+	Category string     `json:"category"`
+	Points   int        `json:"points"`
+	DueDate  *time.Time `json:"due_date"`
+}
+
+// GradebookCategory is an assignment category and its weight. Categories the instructor has
+// not weighted count with weight 1.
+type GradebookCategory struct {
+	Name       string  `json:"name"`
+	Weight     float64 `json:"weight"`
+	Configured bool    `json:"configured"`
+}
+
+// GradebookStudent is a row of the gradebook. Totals and averages only count graded work.
+type GradebookStudent struct {
+	StudentID        uint                    `json:"student_id"`
+	StudentName      string                  `json:"student_name"`
+	Entries          map[uint]GradebookEntry `json:"entries"` // keyed by assignment ID
+	Earned           float64                 `json:"earned"`
+	Possible         int                     `json:"possible"`
+	Percent          *float64                `json:"percent"`
+	CategoryPercents map[string]*float64     `json:"category_percents"` // only categories with graded work
+	WeightedPercent  *float64                `json:"weighted_percent"`
+}
+
+// GradebookEntry is a student's standing on one assignment
+type GradebookEntry struct {
+	StudentAssignmentID uint     `json:"student_assignment_id"`
+	Status              string   `json:"status"`
+	Timing              string   `json:"timing"`
+	Excused             bool     `json:"excused"`
+	LatePenaltyPercent  int      `json:"late_penalty_percent"`
+	Score               *float64 `json:"score"` // after the late penalty; nil until graded
+}
+
+// GetRubrics returns an instructor's rubrics
+func (s *GradingService) GetRubrics(instructorID uint) ([]models.Rubric, error) {
+	return models.GetRubricsByInstructor(s.db, instructorID)
+}
+
+// CreateRubric stores a new rubric for an instructor
+func (s *GradingService) CreateRubric(instructorID uint, input RubricInput) (*models.Rubric, error) {
+	rubric, err := buildRubric(input)
+	if err != nil {
+		return nil, err
+	}
+	rubric.CreatedByID = instructorID
+
+	if err := models.CreateRubric(s.db, rubric); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+// UpdateRubric changes one of an instructor's rubrics. Its criteria are fixed once
+// students have been graded with it.
+func (s *GradingService) UpdateRubric(rubricID uint, instructorID uint, input RubricInput) (*models.Rubric, error) {
+	rubric, err := s.checkRubricOwner(rubricID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := buildRubric(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Equal(rubric.Criteria, updated.Criteria) {
+		graded, err := models.CountGradesByRubric(s.db, rubric.ID)
+		if err != nil {
+			return nil, err
+		}
+		if graded > 0 {
+			return nil, errors.New("rubric criteria cannot change once students have been graded with it")
+		}
+	}
+
+	rubric.Name = updated.Name
+	rubric.Description = updated.Description
+	rubric.Criteria = updated.Criteria
+	if err := models.SaveRubric(s.db, rubric); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+// DeleteRubric removes one of an instructor's rubrics if no grades depend on it
+func (s *GradingService) DeleteRubric(rubricID uint, instructorID uint) error {
+	rubric, err := s.checkRubricOwner(rubricID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	graded, err := models.CountGradesByRubric(s.db, rubric.ID)
+	if err != nil {
+		return err
+	}
+	if graded > 0 {
+		return errors.New("rubric cannot be deleted once students have been graded with it")
+	}
+	return models.DeleteRubric(s.db, rubric)
+}
+
+// SetGradingPolicy sets the point value, rubric and late penalty rules of an instructor's
+// assignment. The points and rubric are fixed once students have been graded.
+func (s *GradingService) SetGradingPolicy(assignmentID uint, instructorID uint, input GradingPolicyInput) (*models.Assignment, error) {
+	assignment, err := s.checkAssignmentOwner(assignmentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Points < 0 || input.Points > maxAssignmentPoints {
+		return nil, fmt.Errorf("points must be between 0 and %d", maxAssignmentPoints)
+	}
+	if input.LatePenaltyPerDay < 0 || input.LatePenaltyPerDay > 100 {
+		return nil, errors.New("late penalty per day must be between 0 and 100 percent")
+	}
+	if input.MaxLatePenalty < 0 || input.MaxLatePenalty > 100 {
+		return nil, errors.New("maximum late penalty must be between 0 and 100 percent")
+	}
+	if input.RubricID != nil {
+		if input.Points == 0 {
+			return nil, errors.New("an assignment scored with a rubric must have points")
+		}
+		if _, err := s.checkRubricOwner(*input.RubricID, instructorID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Points != assignment.Points || !sameRubric(input.RubricID, assignment.RubricID) {
+		graded, err := models.CountGradesByAssignment(s.db, assignmentID)
+		if err != nil {
+			return nil, err
+		}
+		if graded > 0 {
+			return nil, errors.New("points and rubric cannot change once students have been graded")
+		}
+	}
+
+	if err := assignment.UpdateGradingPolicy(s.db, input.Points, input.RubricID, input.LatePenaltyPerDay, input.MaxLatePenalty); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// GetAssignmentGrades returns every student's grade on an instructor's assignment
+func (s *GradingService) GetAssignmentGrades(assignmentID uint, instructorID uint) ([]GradeView, error) {
+	assignment, err := s.checkAssignmentOwner(assignmentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	rubric, err := s.assignmentRubric(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	studentAssignments, err := models.GetStudentAssignmentsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(studentAssignments))
+	for i, sa := range studentAssignments {
+		ids[i] = sa.ID
+	}
+	grades, err := models.GetGradesByStudentAssignments(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]GradeView, 0, len(studentAssignments))
+	for i := range studentAssignments {
+		sa := &studentAssignments[i]
+		sa.Assignment = *assignment
+		var grade *models.Grade
+		if g, ok := grades[sa.ID]; ok {
+			grade = &g
+		}
+		views = append(views, gradeView(sa, rubric, grade))
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].StudentName < views[j].StudentName
+	})
+	return views, nil
+}
+
+// GradeStudent scores a student's work on an instructor's assignment
+func (s *GradingService) GradeStudent(assignmentID uint, studentID uint, instructorID uint, input GradeInput) (*GradeView, error) {
+	assignment, err := s.checkAssignmentOwner(assignmentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.Points <= 0 {
+		return nil, errors.New("assignment has no point value")
+	}
+
+	sa, err := models.GetStudentAssignment(s.db, assignmentID, studentID)
+	if err != nil {
+		return nil, errors.New("student assignment not found")
+	}
+
+	rubric, err := s.assignmentRubric(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	grade := &models.Grade{
+		StudentAssignmentID: sa.ID,
+		GradedByID:          instructorID,
+		GradedAt:            time.Now(),
+	}
+	if rubric != nil {
+		if grade.RawScore, err = scoreRubric(rubric, input.CriterionScores, assignment.Points); err != nil {
+			return nil, err
+		}
+		grade.CriterionScores = input.CriterionScores
+	} else {
+		if input.Score == nil {
+			return nil, errors.New("score is required")
+		}
+		if *input.Score < 0 || *input.Score > float64(assignment.Points) {
+			return nil, fmt.Errorf("score must be between 0 and %d", assignment.Points)
+		}
+		grade.RawScore = roundHundredths(*input.Score)
+	}
+
+	grade.Feedback = strings.TrimSpace(input.Feedback)
+	if len(grade.Feedback) > maxGradeFeedback {
+		return nil, errors.New("feedback is too long")
+	}
+
+	if err := models.SaveGrade(s.db, grade); err != nil {
+		return nil, err
+	}
+	if grade, err = models.GetGrade(s.db, sa.ID); err != nil {
+		return nil, err
+	}
+
+	sa.Assignment = *assignment
+	view := gradeView(sa, rubric, grade)
+	return &view, nil
+}
+
+// DeleteGrade removes a student's grade on an instructor's assignment
+func (s *GradingService) DeleteGrade(assignmentID uint, studentID uint, instructorID uint) error {
+	if _, err := s.checkAssignmentOwner(assignmentID, instructorID); err != nil {
+		return err
+	}
+
+	sa, err := models.GetStudentAssignment(s.db, assignmentID, studentID)
+	if err != nil {
+		return errors.New("student assignment not found")
+	}
+	return models.DeleteGrade(s.db, sa.ID)
+}
+
+// GetStudentGrade returns a student's grade and feedback on one of their assignments
+func (s *GradingService) GetStudentGrade(studentAssignmentID uint, studentID uint) (*GradeView, error) {
+	sa, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, errors.New("assignment not found")
+	}
+	if sa.Assignment.Points <= 0 {
+		return nil, errors.New("assignment is not graded")
+	}
+
+	rubric, err := s.assignmentRubric(&sa.Assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	grade, err := models.GetGrade(s.db, sa.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		grade = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	view := gradeView(sa, rubric, grade)
+	return &view, nil
+}
+
+// SetCategoryWeights replaces the weights an instructor gives assignment categories in the gradebook
+func (s *GradingService) SetCategoryWeights(instructorID uint, weights map[string]float64) (map[string]float64, error) {
+	cleaned := make(map[string]float64, len(weights))
+	for category, weight := range weights {
+		if weight < 0 || weight > maxCategoryWeight {
+			return nil, fmt.Errorf("weight for %q must be between 0 and %d", category, maxCategoryWeight)
+		}
+		cleaned[categoryName(category)] = weight
+	}
+
+	if err := models.SetCategoryWeights(s.db, instructorID, cleaned); err != nil {
+		return nil, err
+	}
+	return cleaned, nil
+}
+
+// GetGradebook builds the gradebook of an instructor's assignments that carry points
+func (s *GradingService) GetGradebook(instructorID uint) (*Gradebook, error) {
+	assignments, err := models.GetGradedAssignmentsByInstructor(s.db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	weights, err := models.GetCategoryWeights(s.db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	gradebook := &Gradebook{
+		Assignments: make([]GradebookAssignment, 0, len(assignments)),
+		Categories:  []GradebookCategory{},
+		Students:    []GradebookStudent{},
+	}
+	byID := make(map[uint]*models.Assignment, len(assignments))
+	ids := make([]uint, 0, len(assignments))
+	seenCategories := make(map[string]bool)
+	for i := range assignments {
+		assignment := &assignments[i]
+		byID[assignment.ID] = assignment
+		ids = append(ids, assignment.ID)
+
+		category := categoryName(assignment.Category)
+		gradebook.Assignments = append(gradebook.Assignments, GradebookAssignment{
+			ID:       assignment.ID,
+			Title:    assignment.Title,
+			Category: category,
+			Points:   assignment.Points,
+			DueDate:  assignment.DueDate,
+		})
+		if !seenCategories[category] {
+			seenCategories[category] = true
+			weight, configured := weights[category]
+			if !configured {
+				weight = 1
+			}
+			gradebook.Categories = append(gradebook.Categories, GradebookCategory{Name: category, Weight: weight, Configured: configured})
+		}
+	}
+	sort.Slice(gradebook.Categories, func(i, j int) bool {
+		return gradebook.Categories[i].Name < gradebook.Categories[j].Name
+	})
+
+	studentAssignments, err := models.GetStudentAssignmentsByAssignments(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	saIDs := make([]uint, len(studentAssignments))
+	for i, sa := range studentAssignments {
+		saIDs[i] = sa.ID
+	}
+	grades, err := models.GetGradesByStudentAssignments(s.db, saIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[uint]*GradebookStudent)
+	categoryTotals := make(map[uint]map[string][2]float64) // student ID to category to earned and possible points
+	for i := range studentAssignments {
+		sa := &studentAssignments[i]
+		assignment := byID[sa.AssignmentID]
+		sa.Assignment = *assignment
+
+		row, ok := rows[sa.StudentID]
+		if !ok {
+			row = &GradebookStudent{
+				StudentID:        sa.StudentID,
+				StudentName:      sa.Student.Username,
+				Entries:          make(map[uint]GradebookEntry),
+				CategoryPercents: make(map[string]*float64),
+			}
+			rows[sa.StudentID] = row
+			categoryTotals[sa.StudentID] = make(map[string][2]float64)
+		}
+
+		entry := GradebookEntry{
+			StudentAssignmentID: sa.ID,
+			Status:              sa.Status,
+			Timing:              sa.CompletionTiming,
+			Excused:             sa.Excused,
+			LatePenaltyPercent:  sa.LatePenaltyPercent(),
+		}
+		if grade, ok := grades[sa.ID]; ok {
+			score := models.ApplyLatePenalty(grade.RawScore, entry.LatePenaltyPercent)
+			entry.Score = &score
+
+			row.Earned += score
+			row.Possible += assignment.Points
+			category := categoryName(assignment.Category)
+			totals := categoryTotals[sa.StudentID][category]
+			categoryTotals[sa.StudentID][category] = [2]float64{totals[0] + score, totals[1] + float64(assignment.Points)}
+		}
+		row.Entries[assignment.ID] = entry
+	}
+
+	for studentID, row := range rows {
+		row.Earned = roundHundredths(row.Earned)
+		if row.Possible > 0 {
+			percent := roundHundredths(row.Earned / float64(row.Possible) * 100)
+			row.Percent = &percent
+		}
+
+		var weighted, totalWeight float64
+		for _, category := range gradebook.Categories {
+			totals, ok := categoryTotals[studentID][category.Name]
+			if !ok || totals[1] == 0 {
+				continue
+			}
+			percent := roundHundredths(totals[0] / totals[1] * 100)
+			row.CategoryPercents[category.Name] = &percent
+			weighted += percent * category.Weight
+			totalWeight += category.Weight
+		}
+		if totalWeight > 0 {
+			percent := roundHundredths(weighted / totalWeight)
+			row.WeightedPercent = &percent
+		}
+
+		gradebook.Students = append(gradebook.Students, *row)
+	}
+	sort.Slice(gradebook.Students, func(i, j int) bool {
+		return gradebook.Students[i].StudentName < gradebook.Students[j].StudentName
+	})
+
+	return gradebook, nil
+}
+
+// checkAssignmentOwner loads an assignment and ensures the instructor created it
+func (s *GradingService) checkAssignmentOwner(assignmentID uint, instructorID uint) (*models.Assignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+	return assignment, nil
+}
+
+// checkRubricOwner loads a rubric and ensures the instructor created it
+func (s *GradingService) checkRubricOwner(rubricID uint, instructorID uint) (*models.Rubric, error) {
+	rubric, err := models.GetRubricByID(s.db, rubricID)
+	if err != nil {
+		return nil, errors.New("rubric not found")
+	}
+	if rubric.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+	return rubric, nil
+}
+
+// assignmentRubric loads the rubric an assignment is scored with, if any
+func (s *GradingService) assignmentRubric(assignment *models.Assignment) (*models.Rubric, error) {
+	if assignment.RubricID == nil {
+		return nil, nil
+	}
+	return models.GetRubricByID(s.db, *assignment.RubricID)
+}
+
+// gradeView applies the late penalty to a student assignment's grade
+func gradeView(sa *models.StudentAssignment, rubric *models.Rubric, grade *models.Grade) GradeView {
+	view := GradeView{
+		StudentAssignmentID: sa.ID,
+		StudentID:           sa.StudentID,
+		StudentName:         sa.Student.Username,
+		Status:              sa.Status,
+		CompletedAt:         sa.CompletedAt,
+		Timing:              sa.CompletionTiming,
+		Points:              sa.Assignment.Points,
+		Rubric:              rubric,
+		Grade:               grade,
+		LatePenaltyPercent:  sa.LatePenaltyPercent(),
+	}
+	if grade != nil {
+		score := models.ApplyLatePenalty(grade.RawScore, view.LatePenaltyPercent)
+		view.Score = &score
+	}
+	return view
+}
+
+// scoreRubric checks a score for each rubric criterion and scales their sum to the assignment's points
+func scoreRubric(rubric *models.Rubric, scores []float64, points int) (float64, error) {
+	if len(scores) != len(rubric.Criteria) {
+		return 0, fmt.Errorf("expected %d criterion scores, got %d", len(rubric.Criteria), len(scores))
+	}
+
+	var earned float64
+	for i, criterion := range rubric.Criteria {
+		if scores[i] < 0 || scores[i] > float64(criterion.Points) {
+			return 0, fmt.Errorf("score for %q must be between 0 and %d", criterion.Name, criterion.Points)
+		}
+		earned += scores[i]
+	}
+	return roundHundredths(earned / float64(rubric.TotalPoints()) * float64(points)), nil
+}
+
+// buildRubric validates an instructor's input and converts it to a rubric
+func buildRubric(input RubricInput) (*models.Rubric, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("rubric name is required")
+	}
+	if len(input.Criteria) == 0 {
+		return nil, errors.New("rubric must have at least one criterion")
+	}
+	if len(input.Criteria) > maxGradingCriteria {
+		return nil, fmt.Errorf("a rubric may have at most %d criteria", maxGradingCriteria)
+	}
+
+	criteria := make([]models.RubricCriterion, 0, len(input.Criteria))
+	for i, criterion := range input.Criteria {
+		criterion.Name = strings.TrimSpace(criterion.Name)
+		criterion.Description = strings.TrimSpace(criterion.Description)
+		if criterion.Name == "" {
+			return nil, fmt.Errorf("rubric criterion %d must have a name", i+1)
+		}
+		if criterion.Points < 1 || criterion.Points > 100 {
+			return nil, fmt.Errorf("points for %q must be between 1 and 100", criterion.Name)
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	return &models.Rubric{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Criteria:    criteria,
+	}, nil
+}
+
+// sameRubric reports whether two optional rubric IDs refer to the same rubric
+func sameRubric(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// categoryName returns the gradebook category of an assignment category
+func categoryName(category string) string {
+	category = strings.TrimSpace(category)
+	if category == "" {
+		return uncategorized
+	}
+	return category
+}
+
+// roundHundredths rounds a score or percentage to two decimal places
+func roundHundredths(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestLatePenaltyPercent(t *testing.T) {
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	sa := &models.StudentAssignment{
+		Assignment: models.Assignment{DueDate: &due, GracePeriodMinutes: 60, LatePenaltyPerDay: 10, MaxLatePenalty: 25},
+	}
+
+	cases := []struct {
+		name      string
+		completed time.Duration
+		want      int
+	}{
+		{"on time", -time.Hour, 0},
+		{"within grace period", 30 * time.Minute, 0},
+		{"first started day", 2 * time.Hour, 10},
+		{"second started day", 26 * time.Hour, 20},
+		{"capped", 10 * 24 * time.Hour, 25},
+	}
+	for _, tc := range cases {
+		completedAt := due.Add(tc.completed)
+		sa.CompletedAt = &completedAt
+		if got := sa.LatePenaltyPercent(); got != tc.want {
+			t.Errorf("%s: expected %d%% penalty, got %d%%", tc.name, tc.want, got)
+		}
+	}
+
+	sa.Excused = true
+	if got := sa.LatePenaltyPercent(); got != 0 {
+		t.Errorf("Expected excused work to carry no penalty, got %d%%", got)
+	}
+
+	if got := models.ApplyLatePenalty(8.5, 20); got != 6.8 {
+		t.Errorf("Expected 8.5 less 20%% to be 6.8, got %v", got)
+	}
+}
+
+func TestGradingWorkflow(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewGradingService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	alice := createTestUser(t, db, "alice", "student")
+	bob := createTestUser(t, db, "bob", "student")
+
+	due := time.Now().Add(-72 * time.Hour)
+	essay, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay", Category: "Writing", DueDate: &due})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	quiz, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Quiz", URL: "https://example.com/quiz", Category: "Quizzes"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	for _, assignment := range []*models.Assignment{essay, quiz} {
+		if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{alice.ID, bob.ID}, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign students: %v", err)
+		}
+	}
+
+	// Rubric validation
+	if _, err := service.CreateRubric(instructor.ID, RubricInput{Name: "Essay"}); err == nil {
+		t.Error("Expected a rubric without criteria to be rejected")
+	}
+	if _, err := service.CreateRubric(instructor.ID, RubricInput{Name: "Essay", Criteria: []models.RubricCriterion{{Name: "Argument", Points: 0}}}); err == nil {
+		t.Error("Expected a criterion without points to be rejected")
+	}
+	rubric, err := service.CreateRubric(instructor.ID, RubricInput{
+		Name:     " Essay ",
+		Criteria: []models.RubricCriterion{{Name: "Argument", Points: 6}, {Name: "Evidence", Points: 4}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create rubric: %v", err)
+	}
+	if rubric.Name != "Essay" || rubric.TotalPoints() != 10 {
+		t.Errorf("Expected a trimmed 10 point rubric, got %q worth %d", rubric.Name, rubric.TotalPoints())
+	}
+
+	// Grading policy
+	if _, err := service.GradeStudent(essay.ID, alice.ID, instructor.ID, GradeInput{CriterionScores: []float64{6, 4}}); err == nil {
+		t.Error("Expected grading to require a point value")
+	}
+	if _, err := service.SetGradingPolicy(essay.ID, instructor.ID, GradingPolicyInput{RubricID: &rubric.ID}); err == nil {
+		t.Error("Expected a rubric to require points")
+	}
+	if _, err := service.SetGradingPolicy(essay.ID, otherInstructor.ID, GradingPolicyInput{Points: 20}); err == nil {
+		t.Error("Expected other instructors to be unable to set the grading policy")
+	}
+	if _, err := service.SetGradingPolicy(essay.ID, instructor.ID, GradingPolicyInput{Points: 20, RubricID: &rubric.ID, LatePenaltyPerDay: 10, MaxLatePenalty: 15}); err != nil {
+		t.Fatalf("Failed to set grading policy: %v", err)
+	}
+	if _, err := service.SetGradingPolicy(quiz.ID, instructor.ID, GradingPolicyInput{Points: 10}); err != nil {
+		t.Fatalf("Failed to set grading policy: %v", err)
+	}
+
+	// Alice finishes a day and a half late; Bob finishes late too but has an extension
+	completedAt := due.Add(36 * time.Hour)
+	for _, student := range []*models.User{alice, bob} {
+		sa, err := models.GetStudentAssignment(db, essay.ID, student.ID)
+		if err != nil {
+			t.Fatalf("Failed to get student assignment: %v", err)
+		}
+		if err := db.Model(sa).Updates(map[string]interface{}{"status": "completed", "completed_at": completedAt}).Error; err != nil {
+			t.Fatalf("Failed to complete assignment: %v", err)
+		}
+		if student == bob {
+			extended := time.Now()
+			if err := sa.SetDueDateOverride(db, &extended); err != nil {
+				t.Fatalf("Failed to grant extension: %v", err)
+			}
+		}
+	}
+
+	if _, err := service.GradeStudent(essay.ID, alice.ID, instructor.ID, GradeInput{CriterionScores: []float64{6}}); err == nil {
+		t.Error("Expected a score for every criterion")
+	}
+	if _, err := service.GradeStudent(essay.ID, alice.ID, instructor.ID, GradeInput{CriterionScores: []float64{7, 4}}); err == nil {
+		t.Error("Expected criterion scores above their points to be rejected")
+	}
+	view, err := service.GradeStudent(essay.ID, alice.ID, instructor.ID, GradeInput{CriterionScores: []float64{6, 2}, Feedback: " Needs more sources "})
+	if err != nil {
+		t.Fatalf("Failed to grade student: %v", err)
+	}
+	// 8/10 scales to 16/20; two started days late is 20%, capped at 15%
+	if view.Grade.RawScore != 16 || view.LatePenaltyPercent != 15 || view.Score == nil || *view.Score != 13.6 {
+		t.Errorf("Expected 16 points less 15%%, got %+v", view)
+	}
+	if view.Grade.Feedback != "Needs more sources" {
+		t.Errorf("Expected trimmed feedback, got %q", view.Grade.Feedback)
+	}
+	view, err = service.GradeStudent(essay.ID, bob.ID, instructor.ID, GradeInput{CriterionScores: []float64{5, 4}})
+	if err != nil {
+		t.Fatalf("Failed to grade student: %v", err)
+	}
+	if view.LatePenaltyPercent != 0 || *view.Score != 18 {
+		t.Errorf("Expected the extension to waive the late penalty, got %+v", view)
+	}
+
+	score := 11.0
+	if _, err := service.GradeStudent(quiz.ID, alice.ID, instructor.ID, GradeInput{Score: &score}); err == nil {
+		t.Error("Expected a score above the assignment's points to be rejected")
+	}
+	if _, err := service.GradeStudent(quiz.ID, alice.ID, instructor.ID, GradeInput{}); err == nil {
+		t.Error("Expected a score to be required without a rubric")
+	}
+	score = 5
+	if _, err := service.GradeStudent(quiz.ID, alice.ID, instructor.ID, GradeInput{Score: &score}); err != nil {
+		t.Fatalf("Failed to grade student: %v", err)
+	}
+
+	// Locks once graded
+	if _, err := service.SetGradingPolicy(essay.ID, instructor.ID, GradingPolicyInput{Points: 30, RubricID: &rubric.ID}); err == nil {
+		t.Error("Expected points to be locked once students are graded")
+	}
+	if _, err := service.SetGradingPolicy(essay.ID, instructor.ID, GradingPolicyInput{Points: 20, RubricID: &rubric.ID, LatePenaltyPerDay: 5}); err != nil {
+		t.Errorf("Expected late penalty rules to stay editable, got %v", err)
+	}
+	if _, err := service.UpdateRubric(rubric.ID, instructor.ID, RubricInput{Name: "Essay", Criteria: []models.RubricCriterion{{Name: "Argument", Points: 10}}}); err == nil {
+		t.Error("Expected rubric criteria to be locked once students are graded")
+	}
+	if _, err := service.UpdateRubric(rubric.ID, instructor.ID, RubricInput{Name: "Essay v2", Criteria: rubric.Criteria}); err != nil {
+		t.Errorf("Expected the rubric name to stay editable, got %v", err)
+	}
+	if err := service.DeleteRubric(rubric.ID, instructor.ID); err == nil {
+		t.Error("Expected a rubric in use to be undeletable")
+	}
+
+	// Gradebook with Writing weighted three times Quizzes
+	if _, err := service.SetCategoryWeights(instructor.ID, map[string]float64{"Writing": 3, "Quizzes": 1}); err != nil {
+		t.Fatalf("Failed to set weights: %v", err)
+	}
+	gradebook, err := service.GetGradebook(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get gradebook: %v", err)
+	}
+	if len(gradebook.Assignments) != 2 || len(gradebook.Categories) != 2 || len(gradebook.Students) != 2 {
+		t.Fatalf("Expected 2 assignments, categories and students, got %+v", gradebook)
+	}
+	row := gradebook.Students[0]
+	if row.StudentName != "alice" {
+		t.Fatalf("Expected students sorted by name, got %q first", row.StudentName)
+	}
+	// The penalty is now 5% a day, so Alice's essay is 16 less 10%
+	if row.Earned != 19.4 || row.Possible != 30 {
+		t.Errorf("Expected 19.4 of 30 points, got %v of %d", row.Earned, row.Possible)
+	}
+	writing, quizzes := row.CategoryPercents["Writing"], row.CategoryPercents["Quizzes"]
+	if writing == nil || *writing != 72 || quizzes == nil || *quizzes != 50 {
+		t.Errorf("Expected 72%% writing and 50%% quizzes, got %v and %v", writing, quizzes)
+	}
+	if row.WeightedPercent == nil || *row.WeightedPercent != 66.5 {
+		t.Errorf("Expected a weighted average of 66.5%%, got %v", row.WeightedPercent)
+	}
+	bobRow := gradebook.Students[1]
+	if bobRow.Possible != 20 || bobRow.WeightedPercent == nil || *bobRow.WeightedPercent != 90 {
+		t.Errorf("Expected ungraded work to be left out of Bob's average, got %+v", bobRow)
+	}
+
+	// Students see their own grades only
+	sa, _ := models.GetStudentAssignment(db, essay.ID, alice.ID)
+	if _, err := service.GetStudentGrade(sa.ID, bob.ID); err == nil {
+		t.Error("Expected students to be unable to see another student's grade")
+	}
+	studentView, err := service.GetStudentGrade(sa.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student grade: %v", err)
+	}
+	if studentView.Score == nil || *studentView.Score != 14.4 || studentView.Grade.Feedback != "Needs more sources" {
+		t.Errorf("Expected Alice to see her penalized score and feedback, got %+v", studentView)
+	}
+
+	if err := service.DeleteGrade(essay.ID, alice.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete grade: %v", err)
+	}
+	studentView, err = service.GetStudentGrade(sa.ID, alice.ID)
+	if err != nil || studentView.Grade != nil || studentView.Score != nil {
+		t.Errorf("Expected the grade to be removed, got %+v (%v)", studentView, err)
+	}
+}
+
+func TestStudentGradeHiddenBeforeRelease(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewGradingService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	assignment, sa := createScheduledStudentAssignment(t, db, instructor, student)
+	if err := db.Model(assignment).Update("points", 10).Error; err != nil {
+		t.Fatalf("Failed to grade assignment: %v", err)
+	}
+
+	if _, err := service.GetStudentGrade(sa.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a scheduled assignment's grade to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// GroupService handles business logic for student groups
+type GroupService struct {
+	db *gorm.DB
+}
+
+// NewGroupService creates a new group service
+func NewGroupService(db *gorm.DB) *GroupService {
+	return &GroupService{db: db}
+}
+
+// GroupInput represents input for creating or updating a group
+type GroupInput struct {
+	Name        string
+	Description string
+	Course      string
+	StudentIDs  []uint
+}
+
+// CreateGroup creates a group of students for an instructor
+func (s *GroupService) CreateGroup(instructorID uint, input GroupInput) (*models.Group, error) {
+	var instructor models.User
+	if err := s.db.First(&instructor, instructorID).Error; err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	if !instructor.IsInstructor() {
+		return nil, errors.New("user is not an instructor")
+	}
+
+	studentIDs, err := s.validateGroupInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.Group{
+		Name:        input.Name,
+		Description: input.Description,
+		Course:      input.Course,
+		CreatedByID: instructorID,
+		Members:     models.NewGroupMembers(studentIDs),
+	}
+	if err := models.CreateGroup(s.db, group); err != nil {
+		return nil, err
+	}
+
+	return models.GetGroupByID(s.db, group.ID)
+}
+
+// GetGroup retrieves a group owned by the instructor
+func (s *GroupService) GetGroup(groupID uint, instructorID uint) (*models.Group, error) {
+	group, err := models.GetGroupByID(s.db, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	return group, nil
+}
+
+// GetGroups retrieves all groups for an instructor
+func (s *GroupService) GetGroups(instructorID uint) ([]models.Group, error) {
+	return models.GetGroupsByInstructor(s.db, instructorID)
+}
+
+// UpdateGroup renames a group and replaces its members
+func (s *GroupService) UpdateGroup(groupID uint, instructorID uint, input GroupInput) (*models.Group, error) {
+	group, err := s.GetGroup(groupID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs, err := s.validateGroupInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := group.Update(s.db, input.Name, input.Description, input.Course, studentIDs); err != nil {
+		return nil, err
+	}
+
+	return models.GetGroupByID(s.db, group.ID)
+}
+
+// DeleteGroup deletes a group owned by the instructor
+func (s *GroupService) DeleteGroup(groupID uint, instructorID uint) error {
+	group, err := s.GetGroup(groupID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	return group.Delete(s.db)
+}
+
+// validateGroupInput checks the group's name and members and returns the distinct student IDs
+func (s *GroupService) validateGroupInput(input GroupInput) ([]uint, error) {
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	studentIDs := uniqueIDs(input.StudentIDs)
+	if len(studentIDs) == 0 {
+		return studentIDs, nil
+	}
+
+	var count int64
+	if err := s.db.Model(&models.User{}).Where("id IN ? AND role = ?", studentIDs, "student").Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if int(count) != len(studentIDs) {
+		return nil, errors.New("some students not found or not valid students")
+	}
+
+	return studentIDs, nil
+}
+
+// groupStudentIDs returns the current members of the instructor's groups
+func groupStudentIDs(db *gorm.DB, groupIDs []uint, instructorID uint) ([]uint, error) {
+	groupIDs = uniqueIDs(groupIDs)
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	var count int64
+	if err := db.Model(&models.Group{}).Where("id IN ? AND created_by_id = ?", groupIDs, instructorID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if int(count) != len(groupIDs) {
+		return nil, errors.New("some groups not found")
+	}
+
+	return models.GetGroupMemberIDs(db, groupIDs)
+}
+
+// uniqueIDs returns the IDs in their original order without duplicates
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestGroupMembership(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewGroupService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	alice := createTestUser(t, db, "alice", "student")
+	bob := createTestUser(t, db, "bob", "student")
+	carol := createTestUser(t, db, "carol", "student")
+
+	if _, err := service.CreateGroup(instructor.ID, GroupInput{StudentIDs: []uint{alice.ID}}); err == nil {
+		t.Error("Expected a group without a name to be rejected")
+	}
+	if _, err := service.CreateGroup(instructor.ID, GroupInput{Name: "Cohort A", StudentIDs: []uint{alice.ID, otherInstructor.ID}}); err == nil {
+		t.Error("Expected non-students to be rejected as members")
+	}
+	if _, err := service.CreateGroup(alice.ID, GroupInput{Name: "Study Buddies"}); err == nil {
+		t.Error("Expected students to be unable to create groups")
+	}
+
+	group, err := service.CreateGroup(instructor.ID, GroupInput{Name: "Cohort A", StudentIDs: []uint{alice.ID, bob.ID, bob.ID}})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if len(group.Members) != 2 || group.Members[0].User.Username == "" {
+		t.Errorf("Expected two members with their users loaded, got %+v", group.Members)
+	}
+	joined := map[uint]models.GroupMember{}
+	for _, member := range group.Members {
+		joined[member.UserID] = member
+	}
+
+	if _, err := service.GetGroup(group.ID, otherInstructor.ID); err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected other instructors to be denied, got %v", err)
+	}
+
+	// Bob leaves and Carol joins; Alice keeps her original membership
+	updated, err := service.UpdateGroup(group.ID, instructor.ID, GroupInput{Name: "Cohort A (Spring)", StudentIDs: []uint{alice.ID, carol.ID}})
+	if err != nil {
+		t.Fatalf("Failed to update group: %v", err)
+	}
+	members, err := models.GetGroupMemberIDs(db, []uint{group.ID})
+	if err != nil {
+		t.Fatalf("Failed to get members: %v", err)
+	}
+	if updated.Name != "Cohort A (Spring)" || len(members) != 2 || members[0] != alice.ID || members[1] != carol.ID {
+		t.Errorf("Expected Alice and Carol in the renamed group, got %q with %v", updated.Name, members)
+	}
+	for _, member := range updated.Members {
+		if member.UserID == alice.ID && member.ID != joined[alice.ID].ID {
+			t.Errorf("Expected Alice's membership to be kept, got %+v", member)
+		}
+	}
+
+	groups, err := service.GetGroups(instructor.ID)
+	if err != nil || len(groups) != 1 {
+		t.Errorf("Expected one group for the instructor, got %d (%v)", len(groups), err)
+	}
+
+	if err := service.DeleteGroup(group.ID, otherInstructor.ID); err == nil {
+		t.Error("Expected other instructors to be unable to delete the group")
+	}
+	if err := service.DeleteGroup(group.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete group: %v", err)
+	}
+	if groups, _ := service.GetGroups(instructor.ID); len(groups) != 0 {
+		t.Errorf("Expected the group to be gone, got %+v", groups)
+	}
+}
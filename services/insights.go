@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// insightHeatmapDays is the number of days, ending today, shown in the activity heatmap
+	insightHeatmapDays = 365
+	// insightHistoryWeeks is the number of weeks, ending this week, in the weekly history
+	insightHistoryWeeks = 12
+	// insightTrendWeeks is the number of earlier weeks this week is compared with
+	insightTrendWeeks = 4
+	// insightRecentDays is the window of the recent on-time rate
+	insightRecentDays = 30
+	// maxWeeklyGoal caps the readings a student can aim to complete in a week
+	maxWeeklyGoal = 50
+)
+
+// InsightsService builds a student's personal progress analytics
+type InsightsService struct {
+	db *gorm.DB
+}
+
+// NewInsightsService creates a new insights service
+func NewInsightsService(db *gorm.DB) *InsightsService {
+	return &InsightsService{db: db}
+}
+
+// ProgressInsights is a student's activity streaks, weekly goal, heatmap and trends
+type ProgressInsights struct {
+	CurrentStreak int                 `json:"current_streak_days"` // still alive if the student has not been active yet today
+	LongestStreak int                 `json:"longest_streak_days"` // within the heatmap window
+	ActiveToday   bool                `json:"active_today"`
+	Goal          *WeeklyGoalProgress `json:"weekly_goal"` // nil until the student sets a goal
+	Heatmap       []ActivityDay       `json:"heatmap"`
+	Weeks         []WeekSummary       `json:"weeks"`
+	Trend         InsightTrend        `json:"trend"`
+}
+
+// ActivityDay is the progress a student made on one day
+type ActivityDay struct {
+	Date          string `json:"date"` // YYYY-MM-DD in the student's time zone
+	Completions   int    `json:"completions"`
+	ActiveMinutes int    `json:"active_minutes"`
+	activeSeconds int
+}
+
+// WeekSummary is the progress a student made in one week, starting on Monday
+type WeekSummary struct {
+	Start         string `json:"start"`
+	Completions   int    `json:"completions"`
+	ActiveMinutes int    `json:"active_minutes"`
+	GoalMet       *bool  `json:"goal_met,omitempty"`
+}
+
+// WeeklyGoalProgress tracks this week's completions against the student's goal
+type WeeklyGoalProgress struct {
+	Target    int  `json:"target"`
+	Completed int  `json:"completed"`
+	Remaining int  `json:"remaining"`
+	Met       bool `json:"met"`
+	WeeksMet  int  `json:"weeks_met"` // earlier weeks in the history that met the goal
+}
+
+// InsightTrend compares this week and the past month with the student's own history
+type InsightTrend struct {
+	ThisWeekCompletions      int      `json:"this_week_completions"`
+	AverageWeeklyCompletions float64  `json:"average_weekly_completions"` // over the previous four weeks
+	ThisWeekMinutes          int      `json:"this_week_active_minutes"`
+	AverageWeeklyMinutes     float64  `json:"average_weekly_active_minutes"`
+	RecentOnTimeRate         *float64 `json:"recent_on_time_rate"` // completions in the last 30 days
+	OverallOnTimeRate        *float64 `json:"overall_on_time_rate"`
+}
+
+// GetInsights builds a student's progress analytics, counting days in the given time zone.
+// A day counts toward a streak when the student completed a reading or recorded reading time.
+func (s *InsightsService) GetInsights(studentID uint, now time.Time, loc *time.Location) (*ProgressInsights, error) {
+	today := startOfDay(now.In(loc))
+	first := today.AddDate(0, 0, -(insightHeatmapDays - 1))
+
+	completed, err := models.GetStudentAssignmentsByStatus(s.db, studentID, models.StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := models.GetReadingSessionsByStudent(s.db, studentID, first)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]*ActivityDay, insightHeatmapDays)
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		days[key] = &ActivityDay{Date: key}
+	}
+
+	var recentOnTime, recentTimed, onTime, timed int
+	recentSince := today.AddDate(0, 0, -insightRecentDays)
+	for _, sa := range completed {
+		if sa.CompletedAt == nil {
+			continue
+		}
+		if day, ok := days[sa.CompletedAt.In(loc).Format("2006-01-02")]; ok {
+			day.Completions++
+		}
+		if sa.CompletionTiming != models.TimingOnTime && sa.CompletionTiming != models.TimingLate {
+			continue
+		}
+		timed++
+		recent := !sa.CompletedAt.Before(recentSince)
+		if recent {
+			recentTimed++
+		}
+		if sa.CompletionTiming == models.TimingOnTime {
+			onTime++
+			if recent {
+				recentOnTime++
+			}
+		}
+	}
+	for _, session := range sessions {
+		if day, ok := days[session.StartedAt.In(loc).Format("2006-01-02")]; ok {
+			day.activeSeconds += session.ActiveSeconds
+		}
+	}
+
+	insights := &ProgressInsights{Heatmap: make([]ActivityDay, 0, len(days))}
+	streak := 0
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		day := days[d.Format("2006-01-02")]
+		day.ActiveMinutes = models.ReadingTime{ActiveSeconds: day.activeSeconds}.ActiveMinutes()
+		insights.Heatmap = append(insights.Heatmap, *day)
+
+		if day.Completions > 0 || day.activeSeconds > 0 {
+			streak++
+		} else {
+			streak = 0
+		}
+		if streak > insights.LongestStreak {
+			insights.LongestStreak = streak
+		}
+	}
+
+	// Today still counts as part of a streak until it is over
+	last := len(insights.Heatmap) - 1
+	insights.ActiveToday = isActiveDay(insights.Heatmap[last])
+	if !insights.ActiveToday {
+		last--
+	}
+	for i := last; i >= 0 && isActiveDay(insights.Heatmap[i]); i-- {
+		insights.CurrentStreak++
+	}
+
+	goal, err := models.GetReadingGoal(s.db, studentID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	thisWeek := startOfWeek(today)
+	for w := insightHistoryWeeks - 1; w >= 0; w-- {
+		start := thisWeek.AddDate(0, 0, -7*w)
+		week := WeekSummary{Start: start.Format("2006-01-02")}
+		seconds := 0
+		for d := 0; d < 7; d++ {
+			if day, ok := days[start.AddDate(0, 0, d).Format("2006-01-02")]; ok {
+				week.Completions += day.Completions
+				seconds += day.activeSeconds
+			}
+		}
+		week.ActiveMinutes = models.ReadingTime{ActiveSeconds: seconds}.ActiveMinutes()
+		if goal != nil {
+			met := week.Completions >= goal.WeeklyCompletions
+			week.GoalMet = &met
+		}
+		insights.Weeks = append(insights.Weeks, week)
+	}
+
+	current := insights.Weeks[len(insights.Weeks)-1]
+	if goal != nil {
+		progress := &WeeklyGoalProgress{
+			Target:    goal.WeeklyCompletions,
+			Completed: current.Completions,
+			Met:       current.Completions >= goal.WeeklyCompletions,
+		}
+		if !progress.Met {
+			progress.Remaining = goal.WeeklyCompletions - current.Completions
+		}
+		for _, week := range insights.Weeks[:len(insights.Weeks)-1] {
+			if *week.GoalMet {
+				progress.WeeksMet++
+			}
+		}
+		insights.Goal = progress
+	}
+
+	insights.Trend.ThisWeekCompletions = current.Completions
+	insights.Trend.ThisWeekMinutes = current.ActiveMinutes
+	previous := insights.Weeks[len(insights.Weeks)-1-insightTrendWeeks : len(insights.Weeks)-1]
+	for _, week := range previous {
+		insights.Trend.AverageWeeklyCompletions += float64(week.Completions) / insightTrendWeeks
+		insights.Trend.AverageWeeklyMinutes += float64(week.ActiveMinutes) / insightTrendWeeks
+	}
+	if recentTimed > 0 {
+		rate := float64(recentOnTime) / float64(recentTimed) * 100
+		insights.Trend.RecentOnTimeRate = &rate
+	}
+	if timed > 0 {
+		rate := float64(onTime) / float64(timed) * 100
+		insights.Trend.OverallOnTimeRate = &rate
+	}
+
+	return insights, nil
+}
+
+// SetGoal sets the number of readings a student aims to complete each week; 0 clears the goal
+func (s *InsightsService) SetGoal(studentID uint, weeklyCompletions int) (*models.ReadingGoal, error) {
+	if weeklyCompletions < 0 || weeklyCompletions > maxWeeklyGoal {
+		return nil, errors.New("invalid goal: weekly completions must be between 0 and 50")
+	}
+	if weeklyCompletions == 0 {
+		return nil, models.DeleteReadingGoal(s.db, studentID)
+	}
+	return models.SetReadingGoal(s.db, studentID, weeklyCompletions)
+}
+
+// isActiveDay checks if the student made any progress on a day
+func isActiveDay(day ActivityDay) bool {
+	return day.Completions > 0 || day.activeSeconds > 0
+}
+
+// startOfDay returns midnight at the start of t's day in t's location
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday starting the week of the given day
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
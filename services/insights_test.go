@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestInsights(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewInsightsService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	var studentAssignments []*models.StudentAssignment
+	for _, title := range []string{"Essay", "Paper"} {
+		assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: title, URL: "https://example.com/" + title})
+		if err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{student.ID}, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign student: %v", err)
+		}
+		sa, _ := models.GetStudentAssignment(db, assignment.ID, student.ID)
+		studentAssignments = append(studentAssignments, sa)
+	}
+
+	// Wednesday afternoon; the student has not done anything yet today
+	now := time.Date(2026, 10, 21, 15, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 10, 0, 0, 0, time.UTC)
+	}
+	complete := func(sa *models.StudentAssignment, at time.Time, timing string) {
+		db.Model(&models.StudentAssignment{}).Where("id = ?", sa.ID).
+			Updates(map[string]interface{}{"status": models.StatusCompleted, "completed_at": at, "completion_timing": timing})
+	}
+	read := func(at time.Time) {
+		session := &models.ReadingSession{StudentAssignmentID: studentAssignments[0].ID, StudentID: student.ID, Source: models.ReadingSourceTimer, StartedAt: at, LastSeenAt: at, ActiveSeconds: 600}
+		if err := models.CreateReadingSession(db, session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+
+	// Five days in a row at the start of the month, then Sunday through Tuesday this week
+	for d := 1; d <= 5; d++ {
+		read(day(10, d))
+	}
+	read(day(10, 18))
+	read(day(10, 19))
+	complete(studentAssignments[0], day(10, 20), models.TimingOnTime)
+	complete(studentAssignments[1], day(8, 1), models.TimingLate)
+
+	if _, err := service.SetGoal(student.ID, 51); err == nil {
+		t.Error("Expected an oversized goal to be rejected")
+	}
+	if _, err := service.SetGoal(student.ID, 2); err != nil {
+		t.Fatalf("Failed to set goal: %v", err)
+	}
+
+	insights, err := service.GetInsights(student.ID, now, time.UTC)
+	if err != nil {
+		t.Fatalf("Failed to get insights: %v", err)
+	}
+	if insights.ActiveToday || insights.CurrentStreak != 3 || insights.LongestStreak != 5 {
+		t.Errorf("Expected a current streak of 3 and a longest of 5, got %d and %d (active today %v)", insights.CurrentStreak, insights.LongestStreak, insights.ActiveToday)
+	}
+	if len(insights.Heatmap) != insightHeatmapDays || insights.Heatmap[len(insights.Heatmap)-1].Date != "2026-10-21" {
+		t.Errorf("Expected a heatmap ending today, got %d days", len(insights.Heatmap))
+	}
+	if yesterday := insights.Heatmap[len(insights.Heatmap)-2]; yesterday.Completions != 1 {
+		t.Errorf("Expected a completion yesterday, got %+v", yesterday)
+	}
+	if monday := insights.Heatmap[len(insights.Heatmap)-3]; monday.ActiveMinutes != 10 {
+		t.Errorf("Expected 10 active minutes on Monday, got %+v", monday)
+	}
+
+	if len(insights.Weeks) != insightHistoryWeeks || insights.Weeks[len(insights.Weeks)-1].Start != "2026-10-19" {
+		t.Errorf("Expected weeks starting on Monday, got %+v", insights.Weeks)
+	}
+	goal := insights.Goal
+	if goal == nil || goal.Target != 2 || goal.Completed != 1 || goal.Met || goal.Remaining != 1 {
+		t.Errorf("Expected one reading left toward the goal, got %+v", goal)
+	}
+	if insights.Trend.ThisWeekMinutes != 10 || insights.Trend.AverageWeeklyMinutes != 15 {
+		t.Errorf("Expected this week compared with the previous four, got %+v", insights.Trend)
+	}
+	if rate := insights.Trend.RecentOnTimeRate; rate == nil || *rate != 100 {
+		t.Errorf("Expected a recent on-time rate of 100, got %v", rate)
+	}
+	if rate := insights.Trend.OverallOnTimeRate; rate == nil || *rate != 50 {
+		t.Errorf("Expected an overall on-time rate of 50, got %v", rate)
+	}
+
+	// Clearing the goal removes it from the insights
+	if _, err := service.SetGoal(student.ID, 0); err != nil {
+		t.Fatalf("Failed to clear goal: %v", err)
+	}
+	insights, _ = service.GetInsights(student.ID, now, time.UTC)
+	if insights.Goal != nil {
+		t.Errorf("Expected no goal, got %+v", insights.Goal)
+	}
+}
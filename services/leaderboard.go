@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// leaderboardCompletionPoints is the score of a completed reading
+	leaderboardCompletionPoints = 10
+	// leaderboardOnTimePoints is the extra score of a reading completed on time
+	leaderboardOnTimePoints = 5
+	// maxPseudonymLength caps the length of a leaderboard pseudonym
+	maxPseudonymLength = 30
+)
+
+// LeaderboardService ranks the students on an instructor's roster for instructors who opt in
+type LeaderboardService struct {
+	db *gorm.DB
+}
+
+// NewLeaderboardService creates a new leaderboard service
+func NewLeaderboardService(db *gorm.DB) *LeaderboardService {
+	return &LeaderboardService{db: db}
+}
+
+// Leaderboard ranks the students on one instructor's roster
+type Leaderboard struct {
+	InstructorID   uint               `json:"instructor_id"`
+	InstructorName string             `json:"instructor_name"`
+	Enabled        bool               `json:"enabled"`
+	Entries        []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardEntry is one student's place on a leaderboard
+type LeaderboardEntry struct {
+	Rank      int    `json:"rank"`
+	StudentID uint   `json:"student_id,omitempty"` // only shown to the instructor
+	Name      string `json:"name"`
+	Score     int    `json:"score"`
+	Completed int    `json:"completed"`
+	OnTime    int    `json:"on_time"`
+	Badges    int    `json:"badges"`
+	IsYou     bool   `json:"is_you,omitempty"`
+}
+
+// LeaderboardProfileInput is how a student wants to appear on leaderboards
+type LeaderboardProfileInput struct {
+	OptOut    bool   `json:"opt_out"`
+	Pseudonym string `json:"pseudonym"`
+}
+
+// GetSettings returns an instructor's leaderboard settings; leaderboards start disabled
+func (s *LeaderboardService) GetSettings(instructorID uint) (*models.LeaderboardSettings, error) {
+	settings, err := models.GetLeaderboardSettings(s.db, instructorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.LeaderboardSettings{InstructorID: instructorID}, nil
+	}
+	return settings, err
+}
+
+// SetEnabled turns an instructor's leaderboard on or off
+func (s *LeaderboardService) SetEnabled(instructorID uint, enabled bool) (*models.LeaderboardSettings, error) {
+	settings, err := s.GetSettings(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	settings.Enabled = enabled
+	if err := models.SaveLeaderboardSettings(s.db, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetInstructorLeaderboard returns an instructor's leaderboard as students would see it, even while it is disabled
+func (s *LeaderboardService) GetInstructorLeaderboard(instructor *models.User) (*Leaderboard, error) {
+	settings, err := s.GetSettings(instructor.ID)
+	if err != nil {
+		return nil, err
+	}
+	board, err := s.build(instructor, 0)
+	if err != nil {
+		return nil, err
+	}
+	board.Enabled = settings.Enabled
+	return board, nil
+}
+
+// GetStudentLeaderboards returns the enabled leaderboards of every instructor who assigned the student readings
+func (s *LeaderboardService) GetStudentLeaderboards(studentID uint) ([]Leaderboard, error) {
+	instructors, err := models.GetLeaderboardInstructors(s.db, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	boards := make([]Leaderboard, 0, len(instructors))
+	for i := range instructors {
+		board, err := s.build(&instructors[i], studentID)
+		if err != nil {
+			return nil, err
+		}
+		board.Enabled = true
+		boards = append(boards, *board)
+	}
+	return boards, nil
+}
+
+// GetProfile returns how a student appears on leaderboards
+func (s *LeaderboardService) GetProfile(studentID uint) (*models.LeaderboardProfile, error) {
+	profile, err := models.GetLeaderboardProfile(s.db, studentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.LeaderboardProfile{StudentID: studentID}, nil
+	}
+	return profile, err
+}
+
+// SaveProfile sets whether a student appears on leaderboards and under which name.
+// A pseudonym cannot be another user's username, so nobody can pose as a classmate.
+func (s *LeaderboardService) SaveProfile(studentID uint, input LeaderboardProfileInput) (*models.LeaderboardProfile, error) {
+	pseudonym := strings.TrimSpace(input.Pseudonym)
+	if utf8.RuneCountInString(pseudonym) > maxPseudonymLength {
+		return nil, errors.New("invalid pseudonym: use at most 30 characters")
+	}
+	if pseudonym != "" {
+		taken, err := models.UsernameTaken(s.db, pseudonym, studentID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, errors.New("invalid pseudonym: it matches another user's name")
+		}
+	}
+
+	profile, err := s.GetProfile(studentID)
+	if err != nil {
+		return nil, err
+	}
+	profile.OptOut = input.OptOut
+	profile.Pseudonym = pseudonym
+	if err := models.SaveLeaderboardProfile(s.db, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// build ranks the students on an instructor's roster who have not opted out. viewerID marks
+// the student looking at the board; 0 means the instructor, who also sees student IDs.
+func (s *LeaderboardService) build(instructor *models.User, viewerID uint) (*Leaderboard, error) {
+	rows, err := models.GetLeaderboardRows(s.db, instructor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		studentIDs = append(studentIDs, row.StudentID)
+	}
+	users, err := models.GetUsersByIDs(s.db, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := models.GetLeaderboardProfiles(s.db, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := models.GetAchievementCounts(s.db, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Leaderboard{InstructorID: instructor.ID, InstructorName: instructor.Username, Entries: []LeaderboardEntry{}}
+	for _, row := range rows {
+		user, ok := users[row.StudentID]
+		profile := profiles[row.StudentID]
+		if !ok || profile.OptOut {
+			continue
+		}
+
+		entry := LeaderboardEntry{
+			Name:      user.Username,
+			Score:     row.Completed*leaderboardCompletionPoints + row.OnTime*leaderboardOnTimePoints,
+			Completed: row.Completed,
+			OnTime:    row.OnTime,
+			Badges:    badges[row.StudentID],
+			IsYou:     viewerID != 0 && row.StudentID == viewerID,
+		}
+		if profile.Pseudonym != "" {
+			entry.Name = profile.Pseudonym
+		}
+		if viewerID == 0 {
+			entry.StudentID = row.StudentID
+		}
+		board.Entries = append(board.Entries, entry)
+	}
+
+	sort.SliceStable(board.Entries, func(i, j int) bool {
+		if board.Entries[i].Score != board.Entries[j].Score {
+			return board.Entries[i].Score > board.Entries[j].Score
+		}
+		return board.Entries[i].Name < board.Entries[j].Name
+	})
+	// Students with the same score share a rank
+	for i := range board.Entries {
+		if i > 0 && board.Entries[i].Score == board.Entries[i-1].Score {
+			board.Entries[i].Rank = board.Entries[i-1].Rank
+		} else {
+			board.Entries[i].Rank = i + 1
+		}
+	}
+
+	return board, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestLeaderboard(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewLeaderboardService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	ada := createTestUser(t, db, "ada", "student")
+	bob := createTestUser(t, db, "bob", "student")
+	cy := createTestUser(t, db, "cy", "student")
+	shy := createTestUser(t, db, "shy", "student")
+	students := []uint{ada.ID, bob.ID, cy.ID, shy.ID}
+
+	for _, title := range []string{"Essay", "Paper"} {
+		assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: title, URL: "https://example.com/" + title})
+		if err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		if err := assignmentService.AssignToMultipleStudents(assignment.ID, students, instructor.ID); err != nil {
+			t.Fatalf("Failed to assign students: %v", err)
+		}
+		// Ada finishes both readings, Bob and Cy one each, Shy both
+		for _, studentID := range students {
+			if title == "Paper" && (studentID == bob.ID || studentID == cy.ID) {
+				continue
+			}
+			sa, _ := models.GetStudentAssignment(db, assignment.ID, studentID)
+			if err := sa.MarkAsCompleted(db); err != nil {
+				t.Fatalf("Failed to complete assignment: %v", err)
+			}
+		}
+	}
+
+	if _, err := service.SaveProfile(ada.ID, LeaderboardProfileInput{Pseudonym: "BOB"}); err == nil {
+		t.Error("Expected a pseudonym matching another username to be rejected")
+	}
+	if _, err := service.SaveProfile(ada.ID, LeaderboardProfileInput{Pseudonym: "  Night Owl  "}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	if _, err := service.SaveProfile(shy.ID, LeaderboardProfileInput{OptOut: true}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	boards, err := service.GetStudentLeaderboards(bob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get leaderboards: %v", err)
+	}
+	if len(boards) != 0 {
+		t.Errorf("Expected no leaderboards until the instructor opts in, got %d", len(boards))
+	}
+
+	if _, err := service.SetEnabled(instructor.ID, true); err != nil {
+		t.Fatalf("Failed to enable leaderboard: %v", err)
+	}
+	boards, _ = service.GetStudentLeaderboards(bob.ID)
+	if len(boards) != 1 {
+		t.Fatalf("Expected 1 leaderboard, got %d", len(boards))
+	}
+	entries := boards[0].Entries
+	if len(entries) != 3 {
+		t.Fatalf("Expected the student who opted out to be left off, got %+v", entries)
+	}
+	if entries[0].Name != "Night Owl" || entries[0].Rank != 1 || entries[0].Score != 30 || entries[0].StudentID != 0 {
+		t.Errorf("Expected Ada first under her pseudonym without an ID, got %+v", entries[0])
+	}
+	if entries[1].Name != "bob" || !entries[1].IsYou || entries[1].Rank != 2 || entries[2].Rank != 2 {
+		t.Errorf("Expected Bob and Cy to share second place, got %+v", entries[1:])
+	}
+
+	board, err := service.GetInstructorLeaderboard(instructor)
+	if err != nil {
+		t.Fatalf("Failed to get instructor leaderboard: %v", err)
+	}
+	if !board.Enabled || board.Entries[0].StudentID != ada.ID {
+		t.Errorf("Expected the instructor to see student IDs, got %+v", board)
+	}
+
+	// A reading that is no longer completed earns nothing, even with a stale on time flag
+	db.Model(&models.StudentAssignment{}).Where("student_id = ?", cy.ID).Update("status", models.StatusInProgress)
+	board, _ = service.GetInstructorLeaderboard(instructor)
+	for _, entry := range board.Entries {
+		if entry.StudentID == cy.ID && (entry.Completed != 0 || entry.OnTime != 0 || entry.Score != 0) {
+			t.Errorf("Expected Cy's reopened reading not to count, got %+v", entry)
+		}
+	}
+}
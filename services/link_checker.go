@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// maxLinkCheckRedirects limits how many redirects a link check follows
+const maxLinkCheckRedirects = 10
+
+// LinkCheckerOptions configures the link-rot checker
+type LinkCheckerOptions struct {
+	Timeout   time.Duration
+	HostDelay time.Duration // minimum time between requests to the same host
+}
+
+// LinkCheckerService periodically verifies that assignment URLs still resolve
+type LinkCheckerService struct {
+	db        *gorm.DB
+	client    *http.Client
+	guard     *addressGuard
+	hostDelay time.Duration
+
+	mu          sync.Mutex
+	lastRequest map[string]time.Time
+}
+
+// NewLinkCheckerService creates a new link checker service
+func NewLinkCheckerService(db *gorm.DB, options LinkCheckerOptions) *LinkCheckerService {
+	s := &LinkCheckerService{
+		db:          db,
+		guard:       &addressGuard{},
+		hostDelay:   options.HostDelay,
+		lastRequest: make(map[string]time.Time),
+	}
+
+	// Redirects are followed by hand so every hop can be recorded
+	s.client = newGuardedClient(s.guard, options.Timeout, func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	})
+
+	return s
+}
+
+// Start checks every assignment URL on the given interval until stop is closed
+func (s *LinkCheckerService) Start(interval time.Duration, stop <-chan struct{}) {
+	runEvery("link checker", interval, stop, func() error {
+		_, err := s.CheckAll()
+		return err
+	})
+}
+
+// CheckAll checks the URL of every assignment and returns the number of links checked
+func (s *LinkCheckerService) CheckAll() (int, error) {
+	var assignments []models.Assignment
+	// Uploaded files are served by the application and cannot rot
+	if err := s.db.Where("url <> '' AND file_id IS NULL").Order("id ASC").Find(&assignments).Error; err != nil {
+		return 0, err
+	}
+
+	checked := 0
+	for i := range assignments {
+		if _, err := s.checkAssignment(&assignments[i]); err != nil {
+			return checked, err
+		}
+		checked++
+	}
+
+	return checked, nil
+}
+
+// CheckAssignment checks a single assignment URL on demand for its instructor
+func (s *LinkCheckerService) CheckAssignment(assignmentID uint, instructorID uint) (*models.LinkCheck, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	if assignment.URL == "" || assignment.FileID != nil {
+		return nil, errors.New("assignment has no URL")
+	}
+
+	return s.checkAssignment(assignment)
+}
+
+// GetLinkProblems returns broken or moved links on an instructor's assignments
+func (s *LinkCheckerService) GetLinkProblems(instructorID uint) ([]models.LinkCheck, error) {
+	return models.GetLinkProblemsByInstructor(s.db, instructorID)
+}
+
+// checkAssignment checks an assignment URL, stores the result and notifies the owning
+// instructor the first time a link turns broken or moved
+func (s *LinkCheckerService) checkAssignment(assignment *models.Assignment) (*models.LinkCheck, error) {
+	check, err := models.GetLinkCheckByAssignment(s.db, assignment.ID)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		check = &models.LinkCheck{AssignmentID: assignment.ID}
+	}
+
+	result := s.checkURL(assignment.URL)
+	check.URL = assignment.URL
+	check.Health = result.Health
+	check.StatusCode = result.StatusCode
+	check.RedirectChain = result.RedirectChain
+	check.FinalURL = result.FinalURL
+	check.Error = result.Error
+	check.CheckedAt = time.Now()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if check.Health == models.LinkHealthOK {
+			check.NotifiedHealth = ""
+		} else if check.NotifiedHealth != check.Health {
+			assignmentID := assignment.ID
+			message := fmt.Sprintf("🔗 The link for '%s' is %s", assignment.Title, check.Health)
+			if check.Health == models.LinkHealthMoved && check.FinalURL != "" {
+				message += fmt.Sprintf(" (now at %s)", check.FinalURL)
+			}
+			if _, err := models.CreateNotification(tx, assignment.CreatedByID, models.NotificationLinkProblem, message, &assignmentID); err != nil {
+				return err
+			}
+			check.NotifiedHealth = check.Health
+		}
+		return models.SaveLinkCheck(tx, check)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return check, nil
+}
+
+// checkURL follows a URL's redirects and classifies the outcome. Permanent redirects
+// mark a link as moved; error statuses and failed requests mark it as broken.
+func (s *LinkCheckerService) checkURL(rawURL string) *models.LinkCheck {
+	result := &models.LinkCheck{Health: models.LinkHealthBroken, RedirectChain: []string{}}
+
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		result.Error = "invalid URL"
+		return result
+	}
+
+	movedPermanently := false
+	for hops := 0; ; hops++ {
+		if err := validateFetchURL(target); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		statusCode, location, err := s.request(target)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.StatusCode = statusCode
+		result.FinalURL = target.String()
+
+		if statusCode < 300 || statusCode >= 400 || location == "" {
+			break
+		}
+
+		if hops >= maxLinkCheckRedirects {
+			result.Error = "too many redirects"
+			return result
+		}
+
+		next, err := target.Parse(location)
+		if err != nil {
+			result.Error = "invalid redirect location"
+			return result
+		}
+
+		if statusCode == http.StatusMovedPermanently || statusCode == http.StatusPermanentRedirect {
+			movedPermanently = true
+		}
+		result.RedirectChain = append(result.RedirectChain, next.String())
+		target = next
+	}
+
+	switch {
+	case result.StatusCode >= http.StatusBadRequest:
+		result.Health = models.LinkHealthBroken
+	case movedPermanently:
+		result.Health = models.LinkHealthMoved
+	default:
+		result.Health = models.LinkHealthOK
+	}
+
+	return result
+}
+
+// request issues a HEAD request, falling back to GET for servers that reject HEAD,
+// and returns the status code and redirect location
+func (s *LinkCheckerService) request(target *url.URL) (int, string, error) {
+	resp, err := s.do(http.MethodHead, target)
+	if err == nil && resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+		resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get("Location"), nil
+	}
+	if err == nil {
+		resp.Body.Close()
+	} else if errors.Is(err, ErrBlockedAddress) {
+		return 0, "", ErrBlockedAddress
+	}
+
+	resp, err = s.do(http.MethodGet, target)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return 0, "", ErrBlockedAddress
+		}
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+// do sends a single request once the host's rate limit allows it
+func (s *LinkCheckerService) do(method string, target *url.URL) (*http.Response, error) {
+	req, err := http.NewRequest(method, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ZipCodeReader-LinkChecker/1.0")
+
+	s.waitForHost(target.Hostname())
+	return s.client.Do(req)
+}
+
+// waitForHost blocks until at least hostDelay has passed since the last request to a host
+func (s *LinkCheckerService) waitForHost(host string) {
+	if s.hostDelay <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	next := s.lastRequest[host].Add(s.hostDelay)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	s.lastRequest[host] = next
+	s.mu.Unlock()
+
+	time.Sleep(time.Until(next))
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+func newTestLinkCheckerService(t *testing.T) (*LinkCheckerService, *gorm.DB) {
+	db := setupTestDB(t)
+	service := NewLinkCheckerService(db, LinkCheckerOptions{Timeout: 2 * time.Second})
+	// httptest servers listen on loopback
+	service.guard.allowPrivateNetworks = true
+	return service, db
+}
+
+func TestLinkCheckerFlagsBrokenLinkAndNotifiesOnce(t *testing.T) {
+	healthy := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	service, db := newTestLinkCheckerService(t)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	assignment, err := NewAssignmentService(db).CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title: "Gone Reading",
+		URL:   server.URL + "/missing",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	// Two runs against the same broken link only notify once
+	for i := 0; i < 2; i++ {
+		if _, err := service.CheckAll(); err != nil {
+			t.Fatalf("Failed to check links: %v", err)
+		}
+	}
+
+	problems, err := service.GetLinkProblems(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get link problems: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 link problem, got %d", len(problems))
+	}
+	if problems[0].Health != models.LinkHealthBroken || problems[0].StatusCode != http.StatusNotFound {
+		t.Errorf("Expected broken link with 404, got %s %d", problems[0].Health, problems[0].StatusCode)
+	}
+	if problems[0].Assignment.Title != "Gone Reading" {
+		t.Errorf("Expected assignment to be loaded with the check, got %q", problems[0].Assignment.Title)
+	}
+
+	notifications, err := models.GetNotificationsByUser(db, instructor.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationLinkProblem {
+		t.Fatalf("Expected 1 link problem notification, got %v", notifications)
+	}
+
+	// A repaired link clears the problem
+	healthy = true
+	check, err := service.CheckAssignment(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to recheck link: %v", err)
+	}
+	if check.Health != models.LinkHealthOK {
+		t.Errorf("Expected link to be ok after repair, got %s", check.Health)
+	}
+
+	problems, err = service.GetLinkProblems(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get link problems: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Expected no link problems after repair, got %d", len(problems))
+	}
+}
+
+func TestLinkCheckerRecordsRedirectChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/interim", http.StatusMovedPermanently)
+		case "/interim":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/temporary":
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := newTestLinkCheckerService(t)
+
+	result := service.checkURL(server.URL + "/old")
+	if result.Health != models.LinkHealthMoved {
+		t.Errorf("Expected permanently redirected link to be moved, got %s", result.Health)
+	}
+	if len(result.RedirectChain) != 2 || result.RedirectChain[1] != server.URL+"/new" {
+		t.Errorf("Expected two-hop redirect chain ending at /new, got %v", result.RedirectChain)
+	}
+	if result.FinalURL != server.URL+"/new" || result.StatusCode != http.StatusOK {
+		t.Errorf("Expected final URL /new with 200, got %s %d", result.FinalURL, result.StatusCode)
+	}
+
+	// Temporary redirects are normal and do not flag the link
+	result = service.checkURL(server.URL + "/temporary")
+	if result.Health != models.LinkHealthOK {
+		t.Errorf("Expected temporarily redirected link to be ok, got %s", result.Health)
+	}
+}
+
+func TestLinkCheckerFallsBackToGet(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, _ := newTestLinkCheckerService(t)
+
+	result := service.checkURL(server.URL + "/no-head")
+	if result.Health != models.LinkHealthOK {
+		t.Errorf("Expected GET fallback to succeed, got %s (%s)", result.Health, result.Error)
+	}
+	if len(methods) != 2 || methods[0] != http.MethodHead || methods[1] != http.MethodGet {
+		t.Errorf("Expected HEAD then GET, got %v", methods)
+	}
+}
+
+func TestLinkCheckerBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service, _ := newTestLinkCheckerService(t)
+	service.guard.allowPrivateNetworks = false
+
+	result := service.checkURL(server.URL)
+	if result.Health != models.LinkHealthBroken || result.Error != ErrBlockedAddress.Error() {
+		t.Errorf("Expected loopback URL to be blocked, got %s (%s)", result.Health, result.Error)
+	}
+}
+
+func TestLinkCheckerRateLimitsPerHost(t *testing.T) {
+	service, _ := newTestLinkCheckerService(t)
+	service.hostDelay = 50 * time.Millisecond
+
+	start := time.Now()
+	service.waitForHost("example.com")
+	service.waitForHost("example.org")
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Errorf("Expected first request to each host without delay, took %s", elapsed)
+	}
+
+	service.waitForHost("example.com")
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected second request to the same host to wait, took %s", elapsed)
+	}
+}
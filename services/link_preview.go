@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+)
+
+// maxPreviewRedirects limits how many redirects a preview fetch follows
+const maxPreviewRedirects = 5
+
+// LinkPreviewOptions configures fetching and caching of link previews
+type LinkPreviewOptions struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	CacheTTL     time.Duration
+}
+
+// LinkPreviewService fetches page metadata to prefill assignment details
+type LinkPreviewService struct {
+	db           *gorm.DB
+	client       *http.Client
+	guard        *addressGuard
+	maxBodyBytes int64
+	cacheTTL     time.Duration
+}
+
+// NewLinkPreviewService creates a new link preview service
+func NewLinkPreviewService(db *gorm.DB, options LinkPreviewOptions) *LinkPreviewService {
+	s := &LinkPreviewService{
+		db:           db,
+		guard:        &addressGuard{},
+		maxBodyBytes: options.MaxBodyBytes,
+		cacheTTL:     options.CacheTTL,
+	}
+
+	s.client = newGuardedClient(s.guard, options.Timeout, func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxPreviewRedirects {
+			return errors.New("too many redirects")
+		}
+		return validateFetchURL(req.URL)
+	})
+
+	return s
+}
+
+// GetPreview returns metadata for a URL, using the cache when it is fresh
+func (s *LinkPreviewService) GetPreview(rawURL string) (*models.LinkPreview, error) {
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, errors.New("invalid URL")
+	}
+
+	if err := validateFetchURL(target); err != nil {
+		return nil, err
+	}
+
+	if cached, err := models.GetLinkPreview(s.db, target.String(), time.Now().Add(-s.cacheTTL)); err == nil {
+		return cached, nil
+	}
+
+	preview, err := s.fetchPreview(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.SaveLinkPreview(s.db, preview); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// fetchPreview downloads the page head and extracts its metadata
+func (s *LinkPreviewService) fetchPreview(target *url.URL) (*models.LinkPreview, error) {
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, errors.New("invalid URL")
+	}
+	req.Header.Set("User-Agent", "ZipCodeReader-LinkPreview/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, ErrBlockedAddress
+		}
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("failed to fetch URL: status %d", resp.StatusCode)
+	}
+
+	// Redirects may land on another host; relative image URLs resolve against the final page
+	finalURL := resp.Request.URL
+	preview := &models.LinkPreview{
+		URL:       target.String(),
+		SiteName:  finalURL.Hostname(),
+		FetchedAt: time.Now(),
+	}
+
+	// Non-HTML resources such as PDFs only get the site name
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return preview, nil
+	}
+
+	metadata := parseLinkMetadata(io.LimitReader(resp.Body, s.maxBodyBytes))
+	preview.Title = firstNonEmpty(metadata["og:title"], metadata["twitter:title"], metadata["title"])
+	preview.Description = firstNonEmpty(metadata["og:description"], metadata["twitter:description"], metadata["description"])
+	preview.SiteName = firstNonEmpty(metadata["og:site_name"], metadata["application-name"], preview.SiteName)
+
+	if image := firstNonEmpty(metadata["og:image"], metadata["og:image:url"], metadata["twitter:image"]); image != "" {
+		if imageURL, err := finalURL.Parse(image); err == nil && (imageURL.Scheme == "http" || imageURL.Scheme == "https") {
+			preview.ImageURL = imageURL.String()
+		}
+	}
+
+	return preview, nil
+}
+
+// parseLinkMetadata collects <title> and OpenGraph, Twitter Card and standard meta tags
+// from the document head. Keys are lowercased property or name values.
+func parseLinkMetadata(r io.Reader) map[string]string {
+	metadata := make(map[string]string)
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return metadata
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = true
+			case "meta":
+				var key, content string
+				for _, attr := range token.Attr {
+					switch strings.ToLower(attr.Key) {
+					case "property", "name":
+						if key == "" {
+							key = strings.ToLower(strings.TrimSpace(attr.Val))
+						}
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				if key != "" && content != "" && metadata[key] == "" {
+					metadata[key] = content
+				}
+			case "body":
+				// Metadata lives in the head; stop before reading the page content
+				return metadata
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = false
+			case "head":
+				return metadata
+			}
+		case html.TextToken:
+			if inTitle && metadata["title"] == "" {
+				metadata["title"] = strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+			}
+		}
+	}
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLinkPreviewService(t *testing.T, maxBodyBytes int64) *LinkPreviewService {
+	db := setupTestDB(t)
+	service := NewLinkPreviewService(db, LinkPreviewOptions{
+		Timeout:      2 * time.Second,
+		MaxBodyBytes: maxBodyBytes,
+		CacheTTL:     time.Hour,
+	})
+	// httptest servers listen on loopback
+	service.guard.allowPrivateNetworks = true
+	return service
+}
+
+func TestLinkPreviewParsesMetadata(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<!DOCTYPE html><html><head>
+			<title>Fallback Title</title>
+			<meta property="og:title" content="Go Concurrency Patterns">
+			<meta name="twitter:description" content="Pipelines &amp; cancellation">
+			<meta property="og:image" content="/images/gopher.png">
+			<meta property="og:site_name" content="The Go Blog">
+			</head><body><meta property="og:title" content="Ignored"></body></html>`)
+	}))
+	defer server.Close()
+
+	service := newTestLinkPreviewService(t, 1<<20)
+
+	preview, err := service.GetPreview(server.URL + "/blog/pipelines")
+	if err != nil {
+		t.Fatalf("Failed to get preview: %v", err)
+	}
+
+	if preview.Title != "Go Concurrency Patterns" {
+		t.Errorf("Expected OpenGraph title, got %q", preview.Title)
+	}
+	if preview.Description != "Pipelines & cancellation" {
+		t.Errorf("Expected Twitter Card description, got %q", preview.Description)
+	}
+	if preview.ImageURL != server.URL+"/images/gopher.png" {
+		t.Errorf("Expected absolute image URL, got %q", preview.ImageURL)
+	}
+	if preview.SiteName != "The Go Blog" {
+		t.Errorf("Expected site name, got %q", preview.SiteName)
+	}
+
+	// A second lookup is served from the cache
+	if _, err := service.GetPreview(server.URL + "/blog/pipelines"); err != nil {
+		t.Fatalf("Failed to get cached preview: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request with caching, got %d", requests)
+	}
+}
+
+func TestLinkPreviewTitleFallbackAndBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/padded" {
+			fmt.Fprint(w, "<html><head><!--"+strings.Repeat("x", 4096)+"--><title>Too Far</title></head></html>")
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>
+			Effective   Go
+		</title><meta name="description" content="Tips for writing clear Go"></head></html>`)
+	}))
+	defer server.Close()
+
+	service := newTestLinkPreviewService(t, 1024)
+
+	preview, err := service.GetPreview(server.URL + "/effective")
+	if err != nil {
+		t.Fatalf("Failed to get preview: %v", err)
+	}
+	if preview.Title != "Effective Go" || preview.Description != "Tips for writing clear Go" {
+		t.Errorf("Expected title and meta description fallbacks, got %q / %q", preview.Title, preview.Description)
+	}
+
+	preview, err = service.GetPreview(server.URL + "/padded")
+	if err != nil {
+		t.Fatalf("Failed to get preview: %v", err)
+	}
+	if preview.Title != "" {
+		t.Errorf("Expected content past the body limit to be ignored, got %q", preview.Title)
+	}
+}
+
+func TestLinkPreviewBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected request to a loopback address to be blocked")
+	}))
+	defer server.Close()
+
+	db := setupTestDB(t)
+	service := NewLinkPreviewService(db, LinkPreviewOptions{Timeout: time.Second, MaxBodyBytes: 1024, CacheTTL: time.Hour})
+
+	if _, err := service.GetPreview(server.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Expected blocked address error, got %v", err)
+	}
+
+	if _, err := service.GetPreview("file:///etc/passwd"); err == nil {
+		t.Error("Expected non-http URL to be rejected")
+	}
+}
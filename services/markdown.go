@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownBullet      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownNumbered    = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	markdownQuote       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	markdownRule        = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	markdownLink        = regexp.MustCompile(`\[([^\]]+)\]\(([^()\s]+)\)`)
+	markdownStrong      = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownEmphasis    = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	markdownLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// renderMarkdown converts the Markdown used in reading notes to HTML. Every character of
+// the source is escaped before formatting is applied, so the output only contains the tags
+// written here and links only point at http, https or mailto URLs.
+func renderMarkdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInlineMarkdown(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+
+		case markdownRule.MatchString(line):
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>\n")
+
+		case markdownHeading.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			match := markdownHeading.FindStringSubmatch(trimmed)
+			// Notes sit inside a page, so their headings start below the page's own
+			level := len(match[1]) + 2
+			if level > 6 {
+				level = 6
+			}
+			out.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, renderInlineMarkdown(match[2]), level))
+
+		case markdownBullet.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInlineMarkdown(markdownBullet.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		case markdownNumbered.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInlineMarkdown(markdownNumbered.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		case markdownQuote.MatchString(line):
+			flushParagraph()
+			closeList()
+			var quote []string
+			for ; i < len(lines) && markdownQuote.MatchString(lines[i]); i++ {
+				quote = append(quote, markdownQuote.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			out.WriteString("<blockquote><p>" + renderInlineMarkdown(strings.Join(quote, "\n")) + "</p></blockquote>\n")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	flushParagraph()
+	closeList()
+
+	return strings.TrimSpace(out.String())
+}
+
+// renderInlineMarkdown escapes text and applies code spans, links and emphasis.
+// Code spans are left exactly as written.
+func renderInlineMarkdown(text string) string {
+	var out strings.Builder
+
+	// NUL marks link placeholders below and never belongs in a note
+	text = strings.ReplaceAll(text, "\x00", "")
+	segments := strings.Split(text, "`")
+	for i, segment := range segments {
+		// Odd segments sit between backticks; an unmatched trailing backtick stays literal
+		if i%2 == 1 && i < len(segments)-1 {
+			out.WriteString("<code>" + html.EscapeString(segment) + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			out.WriteString("`")
+		}
+		out.WriteString(formatInlineMarkdown(html.EscapeString(segment)))
+	}
+
+	return strings.ReplaceAll(out.String(), "\n", "<br>\n")
+}
+
+// formatInlineMarkdown applies links and emphasis to already escaped text. Links are
+// set aside while emphasis is applied so that their URLs are never rewritten.
+func formatInlineMarkdown(escaped string) string {
+	var links []string
+	escaped = markdownLink.ReplaceAllStringFunc(escaped, func(match string) string {
+		parts := markdownLink.FindStringSubmatch(match)
+		target := html.UnescapeString(parts[2])
+		parsed, err := url.Parse(target)
+		if err != nil || !markdownLinkSchemes[strings.ToLower(parsed.Scheme)] {
+			return match
+		}
+		links = append(links, `<a href="`+html.EscapeString(parsed.String())+`" rel="nofollow noopener noreferrer" target="_blank">`+applyEmphasis(parts[1])+`</a>`)
+		return fmt.Sprintf("\x00%d\x00", len(links)-1)
+	})
+
+	escaped = applyEmphasis(escaped)
+
+	for i, link := range links {
+		escaped = strings.Replace(escaped, fmt.Sprintf("\x00%d\x00", i), link, 1)
+	}
+	return escaped
+}
+
+// applyEmphasis turns **strong** and *emphasized* text into tags
+func applyEmphasis(escaped string) string {
+	escaped = markdownStrong.ReplaceAllString(escaped, "<strong>$1$2</strong>")
+	return markdownEmphasis.ReplaceAllString(escaped, "<em>$1$2</em>")
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	source := "# Takeaways\n\nGoroutines are **cheap** and *easy* to start.\nSee `go func()` in [the tour](https://go.dev/tour).\n\n- channels\n- select\n\n1. first\n2. second\n\n> quoted line\n\n```\nfmt.Println(\"<hi>\")\n```"
+	rendered := renderMarkdown(source)
+
+	for _, want := range []string{
+		"<h3>Takeaways</h3>",
+		"<strong>cheap</strong>",
+		"<em>easy</em>",
+		"<code>go func()</code>",
+		`<a href="https://go.dev/tour" rel="nofollow noopener noreferrer" target="_blank">the tour</a>`,
+		"<ul>\n<li>channels</li>\n<li>select</li>\n</ul>",
+		"<ol>\n<li>first</li>\n<li>second</li>\n</ol>",
+		"<blockquote><p>quoted line</p></blockquote>",
+		"<pre><code>fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Expected rendering to contain %q, got:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	tests := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`[click](javascript:alert(1))`,
+		`[click](data:text/html;base64,PHNjcmlwdD4=)`,
+		`[x](https://example.com/"onmouseover="alert(1))`,
+		"`</code><script>alert(1)</script>`",
+	}
+
+	for _, source := range tests {
+		rendered := renderMarkdown(source)
+		for _, forbidden := range []string{"<script", "<img", "<a ", `"onmouseover`} {
+			if strings.Contains(rendered, forbidden) {
+				t.Errorf("Expected %q to be neutralized, got %q", source, rendered)
+			}
+		}
+	}
+
+	// Underscores in link targets are not treated as emphasis
+	rendered := renderMarkdown("[doc](https://go.dev/doc/effective_go_style_x) and _this_")
+	if !strings.Contains(rendered, `href="https://go.dev/doc/effective_go_style_x"`) || !strings.Contains(rendered, "<em>this</em>") {
+		t.Errorf("Expected link target to stay intact, got %q", rendered)
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationService handles in-app notifications
+type NotificationService struct {
+	db *gorm.DB
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+// GetNotifications retrieves a user's notifications
+func (s *NotificationService) GetNotifications(userID uint, unreadOnly bool) ([]models.Notification, error) {
+	return models.GetNotificationsByUser(s.db, userID, unreadOnly)
+}
+
+// MarkAsRead marks one of the user's notifications as read
+func (s *NotificationService) MarkAsRead(notificationID uint, userID uint) error {
+	err := models.MarkNotificationRead(s.db, notificationID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("notification not found")
+	}
+	return err
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxReviewsPerReflection limits how many peers review each reflection
+	maxReviewsPerReflection = 5
+	// maxRubricCriteria limits the length of a peer review rubric
+	maxRubricCriteria = 10
+	// maxReviewComments limits the length of a review's comments in bytes
+	maxReviewComments = 10000
+)
+
+// PeerReviewService handles peer review of student reflections
+type PeerReviewService struct {
+	db                       *gorm.DB
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewPeerReviewService creates a new peer review service
+func NewPeerReviewService(db *gorm.DB) *PeerReviewService {
+	return &PeerReviewService{
+		db:                       db,
+		studentAssignmentService: NewStudentAssignmentService(db),
+	}
+}
+
+// PeerReviewSettingsInput represents an instructor's peer review setup for an assignment
+type PeerReviewSettingsInput struct {
+	ReviewsPerReflection int                          `json:"reviews_per_reflection"`
+	Anonymous            bool                         `json:"anonymous"`
+	DueDate              *time.Time                   `json:"due_date"`
+	Rubric               []models.PeerReviewCriterion `json:"rubric"`
+}
+
+// PeerReviewReport shows an instructor who has reviewed whom and how far along each reviewer is
+type PeerReviewReport struct {
+	Setup     *models.PeerReviewSetup `json:"setup"`
+	Reviews   []models.PeerReview     `json:"reviews"`
+	Reviewers []ReviewerProgress      `json:"reviewers"`
+	Total     int                     `json:"total"`
+	Submitted int                     `json:"submitted"`
+	Closed    bool                    `json:"closed"`
+}
+
+// ReviewerProgress counts the reviews a student was assigned and has submitted
+type ReviewerProgress struct {
+	StudentID   uint   `json:"student_id"`
+	StudentName string `json:"student_name"`
+	Assigned    int    `json:"assigned"`
+	Submitted   int    `json:"submitted"`
+}
+
+// StudentPeerReviews is what a student sees of peer review on an assignment: the
+// reflections they were asked to review and the feedback they received
+type StudentPeerReviews struct {
+	Setup    *models.PeerReviewSetup `json:"setup"`
+	ToReview []models.PeerReview     `json:"to_review"`
+	Received []models.PeerReview     `json:"received"`
+	Closed   bool                    `json:"closed"`
+}
+
+// peerReviewPair is a reviewer assigned to an author's reflection
+type peerReviewPair struct {
+	ReviewerID uint
+	AuthorID   uint
+}
+
+// SaveSettings creates or updates the peer review setup of an instructor's assignment
+func (s *PeerReviewService) SaveSettings(assignmentID uint, instructorID uint, input PeerReviewSettingsInput) (*models.PeerReviewSetup, error) {
+	if _, err := s.checkAssignmentOwner(assignmentID, instructorID); err != nil {
+		return nil, err
+	}
+
+	if input.ReviewsPerReflection < 1 || input.ReviewsPerReflection > maxReviewsPerReflection {
+		return nil, fmt.Errorf("reviews per reflection must be between 1 and %d", maxReviewsPerReflection)
+	}
+	rubric, err := buildPeerReviewRubric(input.Rubric)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := models.GetPeerReviewSetup(s.db, assignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		setup = &models.PeerReviewSetup{AssignmentID: assignmentID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if setup.ID != 0 && !slices.Equal(setup.Rubric, rubric) {
+		submitted, err := models.CountSubmittedPeerReviews(s.db, assignmentID)
+		if err != nil {
+			return nil, err
+		}
+		if submitted > 0 {
+			return nil, errors.New("rubric cannot change once reviews have been submitted")
+		}
+	}
+
+	setup.ReviewsPerReflection = input.ReviewsPerReflection
+	setup.Anonymous = input.Anonymous
+	setup.DueDate = input.DueDate
+	setup.Rubric = rubric
+	if err := models.SavePeerReviewSetup(s.db, setup); err != nil {
+		return nil, err
+	}
+	return setup, nil
+}
+
+// Allocate randomly assigns each submitted reflection to classmates for review and notifies
+// the reviewers. Allocating again reshuffles the reviews until the first one is submitted.
+func (s *PeerReviewService) Allocate(assignmentID uint, instructorID uint) (*PeerReviewReport, error) {
+	assignment, err := s.checkAssignmentOwner(assignmentID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := s.getSetup(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := models.CountSubmittedPeerReviews(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if submitted > 0 {
+		return nil, errors.New("peer reviews have already been submitted")
+	}
+
+	reflections, err := models.GetReflectionsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	submissions := make(map[uint]uint) // author ID to student assignment ID
+	var authors []uint
+	for _, reflection := range reflections {
+		if reflection.WordCount > 0 && reflection.WordCount >= assignment.MinReflectionWords {
+			submissions[reflection.StudentID] = reflection.StudentAssignmentID
+			authors = append(authors, reflection.StudentID)
+		}
+	}
+	if len(authors) < 2 {
+		return nil, errors.New("at least two students must write reflections before peer review")
+	}
+
+	pairs := allocatePeerReviews(authors, setup.ReviewsPerReflection)
+	reviews := make([]models.PeerReview, 0, len(pairs))
+	assigned := make(map[uint]int)
+	for _, pair := range pairs {
+		reviews = append(reviews, models.PeerReview{
+			AssignmentID:        assignmentID,
+			ReviewerID:          pair.ReviewerID,
+			AuthorID:            pair.AuthorID,
+			StudentAssignmentID: submissions[pair.AuthorID],
+		})
+		assigned[pair.ReviewerID]++
+	}
+
+	if err := models.ReplacePeerReviews(s.db, setup, reviews); err != nil {
+		return nil, err
+	}
+
+	for reviewerID, count := range assigned {
+		message := fmt.Sprintf("You have %d classmate reflection(s) to review for %q", count, assignment.Title)
+		if setup.DueDate != nil {
+			message += fmt.Sprintf(" by %s", setup.DueDate.Format("Jan 2, 2006 3:04 PM"))
+		}
+		if _, err := models.CreateNotification(s.db, reviewerID, models.NotificationPeerReviewAssigned, message, &assignmentID); err != nil {
+			log.Printf("failed to notify reviewer %d of assignment %d: %v", reviewerID, assignmentID, err)
+		}
+	}
+
+	return s.GetReport(assignmentID, instructorID)
+}
+
+// GetReport returns the peer review setup of an instructor's assignment and the progress of
+// every review. Instructors always see names, even when review is anonymous to students.
+func (s *PeerReviewService) GetReport(assignmentID uint, instructorID uint) (*PeerReviewReport, error) {
+	if _, err := s.checkAssignmentOwner(assignmentID, instructorID); err != nil {
+		return nil, err
+	}
+
+	setup, err := s.getSetup(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := models.GetPeerReviewsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PeerReviewReport{
+		Setup:     setup,
+		Reviews:   reviews,
+		Reviewers: []ReviewerProgress{},
+		Total:     len(reviews),
+		Closed:    peerReviewClosed(setup, time.Now()),
+	}
+	progress := make(map[uint]int) // reviewer ID to index in report.Reviewers
+	for i := range reviews {
+		review := &reviews[i]
+		review.ReviewerName = review.Reviewer.Username
+		review.AuthorName = review.Author.Username
+
+		index, ok := progress[review.ReviewerID]
+		if !ok {
+			index = len(report.Reviewers)
+			progress[review.ReviewerID] = index
+			report.Reviewers = append(report.Reviewers, ReviewerProgress{
+				StudentID:   review.ReviewerID,
+				StudentName: review.ReviewerName,
+			})
+		}
+		report.Reviewers[index].Assigned++
+		if review.IsSubmitted() {
+			report.Reviewers[index].Submitted++
+			report.Submitted++
+		}
+	}
+
+	return report, nil
+}
+
+// GetStudentPeerReviews returns the reflections a student was asked to review on one of
+// their assignments and the submitted reviews of their own reflection
+func (s *PeerReviewService) GetStudentPeerReviews(studentAssignmentID uint, studentID uint) (*StudentPeerReviews, error) {
+	studentAssignment, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, errors.New("assignment not found")
+	}
+
+	setup, err := s.getSetup(studentAssignment.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	toReview, err := models.GetPeerReviewsByReviewer(s.db, studentAssignment.AssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range toReview {
+		note, err := models.GetReadingNote(s.db, toReview[i].StudentAssignmentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if note != nil {
+			toReview[i].ReflectionHTML = note.ContentHTML
+		}
+		if setup.Anonymous {
+			// IDs would identify the author as surely as a name
+			toReview[i].AuthorID = 0
+			toReview[i].StudentAssignmentID = 0
+		} else {
+			toReview[i].AuthorName = toReview[i].Author.Username
+		}
+	}
+
+	received, err := models.GetSubmittedPeerReviewsByAuthor(s.db, studentAssignment.AssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range received {
+		if setup.Anonymous {
+			received[i].ReviewerID = 0
+		} else {
+			received[i].ReviewerName = received[i].Reviewer.Username
+		}
+	}
+
+	return &StudentPeerReviews{
+		Setup:    setup,
+		ToReview: toReview,
+		Received: received,
+		Closed:   peerReviewClosed(setup, time.Now()),
+	}, nil
+}
+
+// SubmitReview records a student's rubric scores and comments on a reflection they were
+// asked to review. Reviews may be revised until the review due date.
+func (s *PeerReviewService) SubmitReview(reviewID uint, studentID uint, scores []int, comments string) (*models.PeerReview, error) {
+	review, err := models.GetPeerReviewByID(s.db, reviewID)
+	if err != nil || review.ReviewerID != studentID {
+		return nil, errors.New("review not found")
+	}
+
+	setup, err := s.getSetup(review.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if peerReviewClosed(setup, time.Now()) {
+		return nil, errors.New("peer review is closed")
+	}
+
+	if len(scores) != len(setup.Rubric) {
+		return nil, fmt.Errorf("expected %d rubric scores, got %d", len(setup.Rubric), len(scores))
+	}
+	for i, criterion := range setup.Rubric {
+		if scores[i] < 0 || scores[i] > criterion.MaxScore {
+			return nil, fmt.Errorf("score for %q must be between 0 and %d", criterion.Name, criterion.MaxScore)
+		}
+	}
+
+	comments = strings.TrimSpace(comments)
+	if len(comments) > maxReviewComments {
+		return nil, errors.New("comments are too long")
+	}
+	if comments == "" && len(setup.Rubric) == 0 {
+		return nil, errors.New("write feedback for the author")
+	}
+
+	firstSubmission := !review.IsSubmitted()
+	if err := models.SubmitPeerReview(s.db, review, scores, comments); err != nil {
+		return nil, err
+	}
+
+	if firstSubmission {
+		assignmentID := review.AssignmentID
+		message := "A classmate reviewed your reflection"
+		if assignment, err := models.GetAssignmentByID(s.db, assignmentID); err == nil {
+			message = fmt.Sprintf("A classmate reviewed your reflection on %q", assignment.Title)
+		}
+		if _, err := models.CreateNotification(s.db, review.AuthorID, models.NotificationPeerReviewReceived, message, &assignmentID); err != nil {
+			log.Printf("failed to notify author of peer review %d: %v", review.ID, err)
+		}
+	}
+
+	if setup.Anonymous {
+		review.AuthorID = 0
+		review.StudentAssignmentID = 0
+	} else {
+		review.AuthorName = review.Author.Username
+	}
+	return review, nil
+}
+
+// checkAssignmentOwner loads an assignment and ensures the instructor created it
+func (s *PeerReviewService) checkAssignmentOwner(assignmentID uint, instructorID uint) (*models.Assignment, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+	return assignment, nil
+}
+
+// getSetup loads the peer review setup of an assignment
+func (s *PeerReviewService) getSetup(assignmentID uint) (*models.PeerReviewSetup, error) {
+	setup, err := models.GetPeerReviewSetup(s.db, assignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("peer review is not set up for this assignment")
+	}
+	return setup, err
+}
+
+// peerReviewClosed reports whether the review due date has passed
+func peerReviewClosed(setup *models.PeerReviewSetup, now time.Time) bool {
+	return setup.DueDate != nil && now.After(*setup.DueDate)
+}
+
+// buildPeerReviewRubric validates and trims the criteria of a peer review rubric
+func buildPeerReviewRubric(criteria []models.PeerReviewCriterion) ([]models.PeerReviewCriterion, error) {
+	if len(criteria) > maxRubricCriteria {
+		return nil, fmt.Errorf("a rubric may have at most %d criteria", maxRubricCriteria)
+	}
+
+	rubric := make([]models.PeerReviewCriterion, 0, len(criteria))
+	for i, criterion := range criteria {
+		criterion.Name = strings.TrimSpace(criterion.Name)
+		criterion.Description = strings.TrimSpace(criterion.Description)
+		if criterion.Name == "" {
+			return nil, fmt.Errorf("rubric criterion %d must have a name", i+1)
+		}
+		if criterion.MaxScore < 1 || criterion.MaxScore > 100 {
+			return nil, fmt.Errorf("maximum score for %q must be between 1 and 100", criterion.Name)
+		}
+		rubric = append(rubric, criterion)
+	}
+	return rubric, nil
+}
+
+// allocatePeerReviews assigns each author's reflection to n other authors. The authors are
+// shuffled into a circle and each one reviews the n authors that follow, so nobody reviews
+// their own reflection and every author writes and receives the same number of reviews.
+func allocatePeerReviews(authors []uint, n int) []peerReviewPair {
+	if n > len(authors)-1 {
+		n = len(authors) - 1
+	}
+
+	circle := append([]uint(nil), authors...)
+	rand.Shuffle(len(circle), func(i, j int) {
+		circle[i], circle[j] = circle[j], circle[i]
+	})
+
+	pairs := make([]peerReviewPair, 0, len(circle)*n)
+	for i, reviewerID := range circle {
+		for offset := 1; offset <= n; offset++ {
+			pairs = append(pairs, peerReviewPair{
+				ReviewerID: reviewerID,
+				AuthorID:   circle[(i+offset)%len(circle)],
+			})
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestAllocatePeerReviews(t *testing.T) {
+	authors := []uint{1, 2, 3, 4, 5, 6, 7}
+	for n := 1; n <= 3; n++ {
+		pairs := allocatePeerReviews(authors, n)
+		if len(pairs) != len(authors)*n {
+			t.Fatalf("Expected %d reviews, got %d", len(authors)*n, len(pairs))
+		}
+
+		written := make(map[uint]int)
+		received := make(map[uint]int)
+		seen := make(map[peerReviewPair]bool)
+		for _, pair := range pairs {
+			if pair.ReviewerID == pair.AuthorID {
+				t.Errorf("Student %d was asked to review their own reflection", pair.ReviewerID)
+			}
+			if seen[pair] {
+				t.Errorf("Student %d was asked to review student %d twice", pair.ReviewerID, pair.AuthorID)
+			}
+			seen[pair] = true
+			written[pair.ReviewerID]++
+			received[pair.AuthorID]++
+		}
+		for _, author := range authors {
+			if written[author] != n || received[author] != n {
+				t.Errorf("Expected student %d to write and receive %d reviews, got %d and %d", author, n, written[author], received[author])
+			}
+		}
+	}
+
+	// More reviews than classmates are capped at everyone else
+	if pairs := allocatePeerReviews([]uint{1, 2}, 3); len(pairs) != 2 {
+		t.Errorf("Expected two students to review each other once, got %+v", pairs)
+	}
+}
+
+func TestPeerReviewWorkflow(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPeerReviewService(db)
+	assignmentService := NewAssignmentService(db)
+	noteService := NewReadingNoteService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	var students []*models.User
+	var studentIDs []uint
+	for i := 1; i <= 4; i++ {
+		student := createTestUser(t, db, fmt.Sprintf("student%d", i), "student")
+		students = append(students, student)
+		studentIDs = append(studentIDs, student.ID)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, studentIDs, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	studentAssignments := make(map[uint]uint)
+	for _, student := range students {
+		sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+		if err != nil {
+			t.Fatalf("Failed to get student assignment: %v", err)
+		}
+		studentAssignments[student.ID] = sa.ID
+	}
+
+	if _, err := service.Allocate(assignment.ID, instructor.ID); err == nil {
+		t.Error("Expected allocation to require a peer review setup")
+	}
+
+	rubric := []models.PeerReviewCriterion{{Name: " Insight ", MaxScore: 5}, {Name: "Clarity", MaxScore: 3}}
+	if _, err := service.SaveSettings(assignment.ID, instructor.ID, PeerReviewSettingsInput{ReviewsPerReflection: 0, Rubric: rubric}); err == nil {
+		t.Error("Expected zero reviews per reflection to be rejected")
+	}
+	if _, err := service.SaveSettings(assignment.ID, instructor.ID, PeerReviewSettingsInput{ReviewsPerReflection: 2, Rubric: []models.PeerReviewCriterion{{Name: "", MaxScore: 5}}}); err == nil {
+		t.Error("Expected unnamed criteria to be rejected")
+	}
+	setup, err := service.SaveSettings(assignment.ID, instructor.ID, PeerReviewSettingsInput{ReviewsPerReflection: 2, Anonymous: true, Rubric: rubric})
+	if err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	if setup.Rubric[0].Name != "Insight" {
+		t.Errorf("Expected criterion names to be trimmed, got %q", setup.Rubric[0].Name)
+	}
+
+	// Only students who wrote a reflection take part
+	if _, err := noteService.SaveNote(studentAssignments[students[0].ID], students[0].ID, "Channels make ownership explicit."); err != nil {
+		t.Fatalf("Failed to save note: %v", err)
+	}
+	if _, err := service.Allocate(assignment.ID, instructor.ID); err == nil {
+		t.Error("Expected allocation to need at least two reflections")
+	}
+	for _, student := range students[1:3] {
+		if _, err := noteService.SaveNote(studentAssignments[student.ID], student.ID, "Goroutines are cheap but not free."); err != nil {
+			t.Fatalf("Failed to save note: %v", err)
+		}
+	}
+
+	report, err := service.Allocate(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to allocate reviews: %v", err)
+	}
+	if report.Total != 6 || len(report.Reviewers) != 3 || report.Submitted != 0 {
+		t.Errorf("Expected 3 reviewers with 2 reviews each, got %d reviews from %d reviewers", report.Total, len(report.Reviewers))
+	}
+	if report.Reviews[0].ReviewerName == "" || report.Reviews[0].AuthorName == "" {
+		t.Error("Expected instructors to see names even when review is anonymous")
+	}
+	notes, _ := models.GetNotificationsByUser(db, students[0].ID, true)
+	if len(notes) != 1 || notes[0].Type != models.NotificationPeerReviewAssigned {
+		t.Errorf("Expected reviewers to be notified, got %+v", notes)
+	}
+
+	if _, err := service.GetStudentPeerReviews(studentAssignments[students[3].ID], students[0].ID); err == nil {
+		t.Error("Expected students to be unable to see another student's reviews")
+	}
+	mine, err := service.GetStudentPeerReviews(studentAssignments[students[0].ID], students[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get reviews: %v", err)
+	}
+	if len(mine.ToReview) != 2 {
+		t.Fatalf("Expected 2 reflections to review, got %d", len(mine.ToReview))
+	}
+	review := mine.ToReview[0]
+	if review.ReflectionHTML == "" || review.AuthorName != "" || review.AuthorID != 0 {
+		t.Errorf("Expected an anonymous reflection to review, got %+v", review)
+	}
+
+	if _, err := service.SubmitReview(review.ID, students[1].ID, []int{4, 2}, "Nice"); err == nil {
+		t.Error("Expected students to be unable to submit others' reviews")
+	}
+	if _, err := service.SubmitReview(review.ID, students[0].ID, []int{4}, "Nice"); err == nil {
+		t.Error("Expected a score for every criterion")
+	}
+	if _, err := service.SubmitReview(review.ID, students[0].ID, []int{6, 2}, "Nice"); err == nil {
+		t.Error("Expected scores above the maximum to be rejected")
+	}
+	if _, err := service.SubmitReview(review.ID, students[0].ID, []int{4, 2}, "Clear and well argued"); err != nil {
+		t.Fatalf("Failed to submit review: %v", err)
+	}
+
+	report, err = service.GetReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.Submitted != 1 {
+		t.Errorf("Expected 1 submitted review, got %d", report.Submitted)
+	}
+	if _, err := service.Allocate(assignment.ID, instructor.ID); err == nil {
+		t.Error("Expected reallocation to be refused once reviews are submitted")
+	}
+	if _, err := service.SaveSettings(assignment.ID, instructor.ID, PeerReviewSettingsInput{ReviewsPerReflection: 2, Anonymous: true}); err == nil {
+		t.Error("Expected the rubric to be locked once reviews are submitted")
+	}
+
+	var author *models.User
+	for _, student := range students {
+		for _, r := range report.Reviews {
+			if r.ID == review.ID && r.AuthorID == student.ID {
+				author = student
+			}
+		}
+	}
+	feedback, err := service.GetStudentPeerReviews(studentAssignments[author.ID], author.ID)
+	if err != nil {
+		t.Fatalf("Failed to get feedback: %v", err)
+	}
+	if len(feedback.Received) != 1 || feedback.Received[0].ReviewerName != "" || feedback.Received[0].Comments != "Clear and well argued" {
+		t.Errorf("Expected anonymous feedback, got %+v", feedback.Received)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := service.SaveSettings(assignment.ID, instructor.ID, PeerReviewSettingsInput{ReviewsPerReflection: 2, Anonymous: true, DueDate: &past, Rubric: rubric}); err != nil {
+		t.Fatalf("Failed to update due date: %v", err)
+	}
+	if _, err := service.SubmitReview(mine.ToReview[1].ID, students[0].ID, []int{3, 3}, "Late"); err == nil {
+		t.Error("Expected reviews to close at the due date")
+	}
+}
+
+func TestPeerReviewsHiddenBeforeRelease(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewPeerReviewService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	_, sa := createScheduledStudentAssignment(t, db, instructor, student)
+
+	if _, err := service.GetStudentPeerReviews(sa.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a scheduled assignment's peer reviews to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"zipcodereader/database"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	benchmarkAssignments = 200
+	benchmarkStudents    = 30
+)
+
+// seedInstructorProgress creates an instructor with the given number of assignments, each assigned to
+// every student. Due dates cycle through none, past and the coming week; a third of the readings are done.
+func seedInstructorProgress(tb testing.TB, db *gorm.DB, assignmentCount, studentCount int) *models.User {
+	instructor := createTestUser(tb, db, "instructor1", "instructor")
+	students := make([]*models.User, studentCount)
+	for i := range students {
+		students[i] = createTestUser(tb, db, fmt.Sprintf("student%d", i+1), "student")
+	}
+
+	// The indexes database.Initialize creates
+	if err := database.CreateIndexes(db); err != nil {
+		tb.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	now := time.Now()
+	categories := []string{"reading", "research", "review"}
+	for i := 0; i < assignmentCount; i++ {
+		assignment := &models.Assignment{
+			Title:       fmt.Sprintf("Reading %d", i+1),
+			URL:         fmt.Sprintf("https://example.com/%d", i+1),
+			Category:    categories[i%len(categories)],
+			CreatedByID: instructor.ID,
+		}
+		switch i % 3 {
+		case 1:
+			dueDate := now.AddDate(0, 0, -(i%10 + 1))
+			assignment.DueDate = &dueDate
+		case 2:
+			dueDate := now.AddDate(0, 0, i%6+1)
+			assignment.DueDate = &dueDate
+		}
+		if err := db.Create(assignment).Error; err != nil {
+			tb.Fatalf("Failed to create assignment: %v", err)
+		}
+
+		studentAssignments := make([]models.StudentAssignment, studentCount)
+		for j, student := range students {
+			studentAssignments[j] = models.StudentAssignment{AssignmentID: assignment.ID, StudentID: student.ID, Status: models.StatusAssigned}
+			switch (i + j) % 3 {
+			case 0:
+				completedAt := now.Add(-time.Duration(j+1) * time.Hour)
+				studentAssignments[j].Status = models.StatusCompleted
+				studentAssignments[j].CompletedAt = &completedAt
+				studentAssignments[j].CompletionTiming = models.TimingOnTime
+			case 1:
+				studentAssignments[j].Status = models.StatusInProgress
+			}
+		}
+		if err := db.CreateInBatches(studentAssignments, 100).Error; err != nil {
+			tb.Fatalf("Failed to create student assignments: %v", err)
+		}
+	}
+	return instructor
+}
+
+func BenchmarkGetInstructorProgressSummary(b *testing.B) {
+	db := setupTestDB(b)
+	instructor := seedInstructorProgress(b, db, benchmarkAssignments, benchmarkStudents)
+	service := NewProgressTrackingService(db)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetInstructorProgressSummary(instructor.ID); err != nil {
+			b.Fatalf("Failed to get summary: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetInstructorDueDateOverview(b *testing.B) {
+	db := setupTestDB(b)
+	instructor := seedInstructorProgress(b, db, benchmarkAssignments, benchmarkStudents)
+	service := NewDueDateNotificationService(db)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetInstructorDueDateOverview(instructor.ID); err != nil {
+			b.Fatalf("Failed to get overview: %v", err)
+		}
+	}
+}
+
+// loadPerAssignment loads an instructor's student assignments the way the progress summary and
+// due-date overview did before grouped queries, with one query per assignment. It is the
+// baseline for BenchmarkLoadStudentAssignmentsGrouped.
+func loadPerAssignment(db *gorm.DB, instructorID uint) ([]models.StudentAssignment, error) {
+	assignments, err := models.GetAssignmentsByInstructor(db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var studentAssignments []models.StudentAssignment
+	for _, assignment := range assignments {
+		rows, err := models.GetStudentAssignmentsByAssignment(db, assignment.ID)
+		if err != nil {
+			return nil, err
+		}
+		studentAssignments = append(studentAssignments, rows...)
+	}
+	return studentAssignments, nil
+}
+
+// loadGrouped loads an instructor's student assignments with the single query the progress summary uses
+func loadGrouped(db *gorm.DB, instructorID uint) ([]models.StudentAssignment, error) {
+	assignments, err := models.GetAssignmentsByInstructor(db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(assignments))
+	for i, assignment := range assignments {
+		ids[i] = assignment.ID
+	}
+	return models.GetStudentAssignmentStatuses(db, ids)
+}
+
+func TestLoadGroupedMatchesPerAssignment(t *testing.T) {
+	db := setupTestDB(t)
+	instructor := seedInstructorProgress(t, db, 6, 4)
+
+	perAssignment, err := loadPerAssignment(db, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to load per assignment: %v", err)
+	}
+	grouped, err := loadGrouped(db, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to load grouped: %v", err)
+	}
+	if len(grouped) != 24 || len(perAssignment) != len(grouped) {
+		t.Fatalf("Expected 24 student assignments both ways, got %d and %d", len(perAssignment), len(grouped))
+	}
+
+	statuses := make(map[uint]string, len(perAssignment))
+	for _, sa := range perAssignment {
+		statuses[sa.ID] = sa.Status
+	}
+	for _, sa := range grouped {
+		if status, ok := statuses[sa.ID]; !ok || status != sa.Status {
+			t.Errorf("Expected student assignment %d to be %q, got %q", sa.ID, status, sa.Status)
+		}
+	}
+}
+
+func BenchmarkLoadStudentAssignmentsPerAssignment(b *testing.B) {
+	db := setupTestDB(b)
+	instructor := seedInstructorProgress(b, db, benchmarkAssignments, benchmarkStudents)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loadPerAssignment(db, instructor.ID); err != nil {
+			b.Fatalf("Failed to load student assignments: %v", err)
+		}
+	}
+}
+
+func BenchmarkLoadStudentAssignmentsGrouped(b *testing.B) {
+	db := setupTestDB(b)
+	instructor := seedInstructorProgress(b, db, benchmarkAssignments, benchmarkStudents)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loadGrouped(db, instructor.ID); err != nil {
+			b.Fatalf("Failed to load student assignments: %v", err)
+		}
+	}
+}
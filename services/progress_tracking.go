@@ -25,8 +25,16 @@ type DetailedProgressReport struct {
 	TotalStudents         int                     `json:"total_students"`
 	CompletionRate        float64                 `json:"completion_rate"`
 	AverageTimeToComplete int                     `json:"average_time_to_complete_hours"`
+	AverageActiveMinutes  int                     `json:"average_active_reading_minutes"` // recorded reading time of students who completed
 	StatusBreakdown       map[string]int          `json:"status_breakdown"`
 	OverdueCount          int                     `json:"overdue_count"`
+	LateCount             int                     `json:"late_count"`
+	UnopenedCompletions   int                     `json:"completed_without_opening_count"`
+	ExcusedCount          int                     `json:"excused_count"`
+	MinReflectionWords    int                     `json:"min_reflection_words"`
+	HasQuiz               bool                    `json:"has_quiz"`
+	QuizPassingScore      int                     `json:"quiz_passing_score"`
+	QuizPassedCount       int                     `json:"quiz_passed_count"`
 	StudentDetails        []StudentProgressDetail `json:"student_details"`
 	CreatedAt             time.Time               `json:"created_at"`
 	DueDate               *time.Time              `json:"due_date"`
@@ -34,14 +42,28 @@ type DetailedProgressReport struct {
 
 // StudentProgressDetail contains individual student progress information
 type StudentProgressDetail struct {
-	StudentID      uint       `json:"student_id"`
-	StudentName    string     `json:"student_name"`
-	StudentEmail   string     `json:"student_email"`
-	Status         string     `json:"status"`
-	AssignedAt     time.Time  `json:"assigned_at"`
-	CompletedAt    *time.Time `json:"completed_at"`
-	TimeToComplete *int       `json:"time_to_complete_hours"`
-	IsOverdue      bool       `json:"is_overdue"`
+	StudentID        uint       `json:"student_id"`
+	StudentName      string     `json:"student_name"`
+	StudentEmail     string     `json:"student_email"`
+	Status           string     `json:"status"`
+	AssignedAt       time.Time  `json:"assigned_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	TimeToComplete   *int       `json:"time_to_complete_hours"`
+	ActiveMinutes    int        `json:"active_reading_minutes"`
+	IsOverdue        bool       `json:"is_overdue"`
+	DueDate          *time.Time `json:"due_date"`
+	HasExtension     bool       `json:"has_extension"`
+	Excused          bool       `json:"excused"`
+	CompletionTiming string     `json:"completion_timing"`
+	LinkOpenCount    int        `json:"link_open_count"`
+	FirstOpenedAt    *time.Time `json:"first_opened_at"`
+	LastOpenedAt     *time.Time `json:"last_opened_at"`
+	Unopened         bool       `json:"completed_without_opening"` // completed before ever opening the tracked link
+	ReflectionWords  int        `json:"reflection_words"`
+	ReflectionHTML   string     `json:"reflection_html,omitempty"` // sanitized rendering of the student's note
+	QuizAttempts     int        `json:"quiz_attempts"`
+	QuizBestPercent  *int       `json:"quiz_best_percent"` // nil until the student attempts the quiz
+	QuizPassed       bool       `json:"quiz_passed"`
 }
 
 // InstructorProgressSummary contains overall instructor progress statistics
@@ -52,6 +74,7 @@ type InstructorProgressSummary struct {
 	AssignmentsWithDueDates int                        `json:"assignments_with_due_dates"`
 	OverdueAssignments      int                        `json:"overdue_assignments"`
 	AverageCompletionTime   int                        `json:"average_completion_time_hours"`
+	AverageActiveMinutes    int                        `json:"average_active_reading_minutes"`
 	CategoryBreakdown       map[string]CategoryStats   `json:"category_breakdown"`
 	RecentCompletions       []RecentCompletionActivity `json:"recent_completions"`
 	StudentEngagement       map[string]interface{}     `json:"student_engagement"`
@@ -90,12 +113,51 @@ func (s *ProgressTrackingService) GetDetailedProgressReport(assignmentID uint, i
 		return nil, err
 	}
 
+	reflections, err := models.GetReflectionsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	reflectionsByStudentAssignment := make(map[uint]models.ReflectionEntry, len(reflections))
+	for _, reflection := range reflections {
+		reflectionsByStudentAssignment[reflection.StudentAssignmentID] = reflection
+	}
+
+	hasQuiz := false
+	quizPassingScore := 0
+	quiz, err := models.GetQuizByAssignment(s.db, assignmentID)
+	switch {
+	case err == nil:
+		hasQuiz = true
+		quizPassingScore = quiz.PassingScore
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	quizResults, err := models.GetQuizResultsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	readingTimes, err := models.GetReadingTimesByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	quizResultsByStudentAssignment := make(map[uint]models.QuizResult, len(quizResults))
+	for _, result := range quizResults {
+		quizResultsByStudentAssignment[result.StudentAssignmentID] = result
+	}
+
 	// Calculate basic statistics
 	totalStudents := len(studentAssignments)
 	statusBreakdown := make(map[string]int)
 	var completedCount int
 	var totalCompletionTime int
+	var totalActiveSeconds, timedCount int
 	var overdueCount int
+	var lateCount int
+	var unopenedCompletions int
+	var excusedCount int
+	var quizPassedCount int
 	var studentDetails []StudentProgressDetail
 
 	// Initialize status breakdown
@@ -107,13 +169,18 @@ func (s *ProgressTrackingService) GetDetailedProgressReport(assignmentID uint, i
 		// Update status breakdown
 		statusBreakdown[sa.Status]++
 
-		// Check if overdue
-		isOverdue := false
-		if assignment.DueDate != nil && sa.Status != models.StatusCompleted {
-			isOverdue = time.Now().After(*assignment.DueDate)
-			if isOverdue {
-				overdueCount++
-			}
+		// Check if overdue against the student's effective due date
+		sa.Assignment = *assignment
+		isOverdue := sa.IsOverdue()
+		if isOverdue {
+			overdueCount++
+		}
+
+		switch sa.CompletionTiming {
+		case models.TimingLate:
+			lateCount++
+		case models.TimingExcused:
+			excusedCount++
 		}
 
 		// Calculate time to complete
@@ -124,20 +191,56 @@ func (s *ProgressTrackingService) GetDetailedProgressReport(assignmentID uint, i
 			totalCompletionTime += hours
 		}
 
+		completedWithoutOpening := sa.CompletedWithoutOpening()
+		if completedWithoutOpening {
+			unopenedCompletions++
+		}
+
+		readingTime := readingTimes[sa.ID]
 		if sa.Status == models.StatusCompleted {
 			completedCount++
+			if readingTime.Sessions > 0 {
+				totalActiveSeconds += readingTime.ActiveSeconds
+				timedCount++
+			}
+		}
+
+		reflection := reflectionsByStudentAssignment[sa.ID]
+
+		quizResult, attempted := quizResultsByStudentAssignment[sa.ID]
+		var quizBestPercent *int
+		if attempted {
+			best := quizResult.BestPercent
+			quizBestPercent = &best
+		}
+		if quizResult.Passed {
+			quizPassedCount++
 		}
 
 		// Add student detail
 		studentDetails = append(studentDetails, StudentProgressDetail{
-			StudentID:      sa.StudentID,
-			StudentName:    sa.Student.Username,
-			StudentEmail:   sa.Student.Email,
-			Status:         sa.Status,
-			AssignedAt:     sa.CreatedAt,
-			CompletedAt:    sa.CompletedAt,
-			TimeToComplete: timeToComplete,
-			IsOverdue:      isOverdue,
+			StudentID:        sa.StudentID,
+			StudentName:      sa.Student.Username,
+			StudentEmail:     sa.Student.Email,
+			Status:           sa.Status,
+			AssignedAt:       sa.CreatedAt,
+			CompletedAt:      sa.CompletedAt,
+			TimeToComplete:   timeToComplete,
+			ActiveMinutes:    readingTime.ActiveMinutes(),
+			IsOverdue:        isOverdue,
+			DueDate:          sa.EffectiveDueDate(),
+			HasExtension:     sa.DueDateOverride != nil,
+			Excused:          sa.Excused,
+			CompletionTiming: sa.CompletionTiming,
+			LinkOpenCount:    sa.LinkOpenCount,
+			FirstOpenedAt:    sa.FirstOpenedAt,
+			LastOpenedAt:     sa.LastOpenedAt,
+			Unopened:         completedWithoutOpening,
+			ReflectionWords:  reflection.WordCount,
+			ReflectionHTML:   reflection.ContentHTML,
+			QuizAttempts:     quizResult.Attempts,
+			QuizBestPercent:  quizBestPercent,
+			QuizPassed:       quizResult.Passed,
 		})
 	}
 
@@ -153,14 +256,28 @@ func (s *ProgressTrackingService) GetDetailedProgressReport(assignmentID uint, i
 		averageTimeToComplete = totalCompletionTime / completedCount
 	}
 
+	// Average the active reading time of those who completed with the timer running
+	averageActiveMinutes := 0
+	if timedCount > 0 {
+		averageActiveMinutes = models.ReadingTime{ActiveSeconds: totalActiveSeconds / timedCount}.ActiveMinutes()
+	}
+
 	return &DetailedProgressReport{
 		AssignmentID:          assignmentID,
 		Title:                 assignment.Title,
 		TotalStudents:         totalStudents,
 		CompletionRate:        completionRate,
 		AverageTimeToComplete: averageTimeToComplete,
+		AverageActiveMinutes:  averageActiveMinutes,
 		StatusBreakdown:       statusBreakdown,
 		OverdueCount:          overdueCount,
+		LateCount:             lateCount,
+		UnopenedCompletions:   unopenedCompletions,
+		ExcusedCount:          excusedCount,
+		MinReflectionWords:    assignment.MinReflectionWords,
+		HasQuiz:               hasQuiz,
+		QuizPassingScore:      quizPassingScore,
+		QuizPassedCount:       quizPassedCount,
 		StudentDetails:        studentDetails,
 		CreatedAt:             assignment.CreatedAt,
 		DueDate:               assignment.DueDate,
@@ -175,90 +292,65 @@ func (s *ProgressTrackingService) GetInstructorProgressSummary(instructorID uint
 		return nil, err
 	}
 
+	readingTimes, err := models.GetReadingTimesByInstructor(s.db, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
 	totalAssignments := len(assignments)
 	assignmentsWithDueDates := 0
-	categoryBreakdown := make(map[string]CategoryStats)
-
 	var totalStudentAssignments int
 	var totalCompleted int
 	var totalCompletionTime int
+	var totalActiveSeconds, timedCompletions int
 	var completedAssignments int
 	var overdueAssignments int
 
-	// Process each assignment
+	// Load every student assignment in one query rather than one per assignment
+	byID := make(map[uint]models.Assignment, len(assignments))
+	ids := make([]uint, 0, len(assignments))
 	for _, assignment := range assignments {
-		// Count assignments with due dates
 		if assignment.DueDate != nil {
 			assignmentsWithDueDates++
 		}
+		byID[assignment.ID] = assignment
+		ids = append(ids, assignment.ID)
+	}
+	studentAssignments, err := models.GetStudentAssignmentStatuses(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
 
-		// Get student assignments for this assignment
-		studentAssignments, err := models.GetStudentAssignmentsByAssignment(s.db, assignment.ID)
-		if err != nil {
-			continue
-		}
-
-		assignmentCompleted := 0
-		assignmentTotalTime := 0
-		assignmentOverdue := 0
-
-		for _, sa := range studentAssignments {
-			totalStudentAssignments++
+	for _, sa := range studentAssignments {
+		totalStudentAssignments++
 
-			if sa.Status == models.StatusCompleted {
-				totalCompleted++
-				assignmentCompleted++
-				completedAssignments++
+		if sa.Status == models.StatusCompleted {
+			totalCompleted++
+			completedAssignments++
 
-				if sa.CompletedAt != nil {
-					hours := int(sa.CompletedAt.Sub(sa.CreatedAt).Hours())
-					totalCompletionTime += hours
-					assignmentTotalTime += hours
-				}
+			if sa.CompletedAt != nil {
+				hours := int(sa.CompletedAt.Sub(sa.CreatedAt).Hours())
+				totalCompletionTime += hours
 			}
 
-			// Check if overdue
-			if assignment.DueDate != nil && sa.Status != models.StatusCompleted {
-				if time.Now().After(*assignment.DueDate) {
-					overdueAssignments++
-					assignmentOverdue++
-				}
+			if readingTime, ok := readingTimes[sa.ID]; ok {
+				totalActiveSeconds += readingTime.ActiveSeconds
+				timedCompletions++
 			}
 		}
 
-		// Update category breakdown
-		category := assignment.Category
-		if category == "" {
-			category = "uncategorized"
+		// Check if overdue against the student's effective due date
+		sa.Assignment = byID[sa.AssignmentID]
+		if sa.IsOverdue() {
+			overdueAssignments++
 		}
+	}
 
-		if stats, exists := categoryBreakdown[category]; exists {
-			stats.AssignmentCount++
-			// Update completion rate and average time
-			if len(studentAssignments) > 0 {
-				stats.CompletionRate = (stats.CompletionRate*(float64(stats.AssignmentCount-1)) +
-					float64(assignmentCompleted)/float64(len(studentAssignments))*100) / float64(stats.AssignmentCount)
-			}
-			if assignmentCompleted > 0 {
-				newAvgTime := assignmentTotalTime / assignmentCompleted
-				stats.AverageTimeToComplete = (stats.AverageTimeToComplete*(stats.AssignmentCount-1) + newAvgTime) / stats.AssignmentCount
-			}
-			categoryBreakdown[category] = stats
-		} else {
-			completionRate := 0.0
-			if len(studentAssignments) > 0 {
-				completionRate = float64(assignmentCompleted) / float64(len(studentAssignments)) * 100
-			}
-			avgTime := 0
-			if assignmentCompleted > 0 {
-				avgTime = assignmentTotalTime / assignmentCompleted
-			}
-			categoryBreakdown[category] = CategoryStats{
-				AssignmentCount:       1,
-				CompletionRate:        completionRate,
-				AverageTimeToComplete: avgTime,
-			}
-		}
+	// Category statistics pool every student assignment in the category, so a reading
+	// assigned to one student does not count as much as one assigned to the whole class
+	categoryBreakdown, err := s.categoryBreakdown(instructorID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate overall completion rate
@@ -273,6 +365,11 @@ func (s *ProgressTrackingService) GetInstructorProgressSummary(instructorID uint
 		averageCompletionTime = totalCompletionTime / completedAssignments
 	}
 
+	averageActiveMinutes := 0
+	if timedCompletions > 0 {
+		averageActiveMinutes = models.ReadingTime{ActiveSeconds: totalActiveSeconds / timedCompletions}.ActiveMinutes()
+	}
+
 	// Get recent completions
 	recentCompletions, err := s.getRecentCompletions(instructorID, 10)
 	if err != nil {
@@ -289,6 +386,7 @@ func (s *ProgressTrackingService) GetInstructorProgressSummary(instructorID uint
 		AssignmentsWithDueDates: assignmentsWithDueDates,
 		OverdueAssignments:      overdueAssignments,
 		AverageCompletionTime:   averageCompletionTime,
+		AverageActiveMinutes:    averageActiveMinutes,
 		CategoryBreakdown:       categoryBreakdown,
 		RecentCompletions:       recentCompletions,
 		StudentEngagement:       studentEngagement,
@@ -16,7 +16,7 @@ func setupProgressTrackingTestDB() *gorm.DB {
 	}
 
 	// Migrate the schema
-	db.AutoMigrate(&models.User{}, &models.Assignment{}, &models.StudentAssignment{})
+	db.AutoMigrate(models.All()...)
 
 	return db
 }
@@ -236,6 +236,52 @@ func TestProgressTrackingService_WithCompletedAssignment(t *testing.T) {
 	}
 }
 
+func TestProgressTrackingService_ReopenedAssignment(t *testing.T) {
+	db := setupProgressTrackingTestDB()
+	service := NewProgressTrackingService(db)
+
+	instructor, student, assignment := createProgressTrackingTestData(db)
+	pastDue := time.Now().AddDate(0, 0, -2)
+	db.Model(assignment).Update("due_date", &pastDue)
+	db.Create(&models.StudentAssignment{AssignmentID: assignment.ID, StudentID: student.ID, Status: models.StatusAssigned})
+
+	// Completing after the due date counts as late
+	sa, _ := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err := sa.MarkAsCompleted(db); err != nil {
+		t.Fatalf("Failed to complete assignment: %v", err)
+	}
+	report, err := service.GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get detailed progress report: %v", err)
+	}
+	if report.LateCount != 1 {
+		t.Fatalf("Expected 1 late completion, got %d", report.LateCount)
+	}
+
+	// Reopening takes the completion back
+	if err := sa.MarkAsInProgress(db); err != nil {
+		t.Fatalf("Failed to reopen assignment: %v", err)
+	}
+	sa, _ = models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if sa.CompletedAt != nil || sa.CompletionTiming != "" {
+		t.Errorf("Expected reopening to clear the completion, got %v %q", sa.CompletedAt, sa.CompletionTiming)
+	}
+
+	report, err = service.GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get detailed progress report: %v", err)
+	}
+	if report.LateCount != 0 {
+		t.Errorf("Expected no late completions after reopening, got %d", report.LateCount)
+	}
+	if report.StatusBreakdown[models.StatusCompleted] != 0 || report.StatusBreakdown[models.StatusInProgress] != 1 {
+		t.Errorf("Expected 1 in progress and none completed, got %v", report.StatusBreakdown)
+	}
+	if report.CompletionRate != 0 {
+		t.Errorf("Expected completion rate 0, got %f", report.CompletionRate)
+	}
+}
+
 func TestProgressTrackingService_MultipleStudents(t *testing.T) {
 	db := setupProgressTrackingTestDB()
 	service := NewProgressTrackingService(db)
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// maxQuizQuestions limits how many questions a quiz may have
+const maxQuizQuestions = 50
+
+// QuizService handles comprehension quizzes attached to assignments
+type QuizService struct {
+	db                       *gorm.DB
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewQuizService creates a new quiz service
+func NewQuizService(db *gorm.DB) *QuizService {
+	return &QuizService{db: db, studentAssignmentService: NewStudentAssignmentService(db)}
+}
+
+// QuizInput represents an instructor's quiz for an assignment
+type QuizInput struct {
+	PassingScore int                 `json:"passing_score"`
+	MaxAttempts  int                 `json:"max_attempts"`
+	Questions    []QuizQuestionInput `json:"questions"`
+}
+
+// QuizQuestionInput represents one question of a quiz. Answer is the index of the correct
+// choice for multiple choice and "true" or "false" for true/false questions; short answer
+// questions list every acceptable response in AcceptedAnswers.
+type QuizQuestionInput struct {
+	Type            string   `json:"type"`
+	Prompt          string   `json:"prompt"`
+	Choices         []string `json:"choices"`
+	Answer          string   `json:"answer"`
+	AcceptedAnswers []string `json:"accepted_answers"`
+	Points          int      `json:"points"`
+}
+
+// StudentQuiz is a quiz as a student sees it, without answers, along with their attempts
+type StudentQuiz struct {
+	Quiz         *models.Quiz         `json:"quiz"`
+	Attempts     []models.QuizAttempt `json:"attempts"`
+	Passed       bool                 `json:"passed"`
+	BestPercent  int                  `json:"best_percent"`
+	AttemptsLeft *int                 `json:"attempts_left"` // nil when attempts are unlimited
+	CanAttempt   bool                 `json:"can_attempt"`
+	Required     bool                 `json:"required"` // passing is required to complete the assignment
+}
+
+// SaveQuiz creates or replaces the quiz of an instructor's assignment
+func (s *QuizService) SaveQuiz(assignmentID uint, instructorID uint, input QuizInput) (*models.Quiz, error) {
+	if err := s.checkAssignmentOwner(assignmentID, instructorID); err != nil {
+		return nil, err
+	}
+
+	quiz, err := buildQuiz(input)
+	if err != nil {
+		return nil, err
+	}
+	quiz.AssignmentID = assignmentID
+
+	if err := models.SaveQuiz(s.db, quiz); err != nil {
+		return nil, err
+	}
+
+	return models.GetQuizByAssignment(s.db, assignmentID)
+}
+
+// GetQuiz returns the quiz of an instructor's assignment, answers included
+func (s *QuizService) GetQuiz(assignmentID uint, instructorID uint) (*models.Quiz, error) {
+	if err := s.checkAssignmentOwner(assignmentID, instructorID); err != nil {
+		return nil, err
+	}
+	return models.GetQuizByAssignment(s.db, assignmentID)
+}
+
+// DeleteQuiz removes the quiz of an instructor's assignment along with its attempts
+func (s *QuizService) DeleteQuiz(assignmentID uint, instructorID uint) error {
+	quiz, err := s.GetQuiz(assignmentID, instructorID)
+	if err != nil {
+		return err
+	}
+	return models.DeleteQuiz(s.db, quiz)
+}
+
+// GetStudentQuiz returns the quiz on one of a student's assignments and their attempts
+func (s *QuizService) GetStudentQuiz(studentAssignmentID uint, studentID uint) (*StudentQuiz, error) {
+	// Quizzes on scheduled or withdrawn readings are hidden like the readings themselves
+	studentAssignment, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, errors.New("assignment not found")
+	}
+
+	quiz, err := models.GetQuizByAssignment(s.db, studentAssignment.AssignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("quiz not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := models.GetQuizAttempts(s.db, quiz.ID, studentAssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz.HideAnswers()
+	view := &StudentQuiz{
+		Quiz:       quiz,
+		Attempts:   attempts,
+		CanAttempt: true,
+		Required:   quiz.PassingScore > 0,
+	}
+	for _, attempt := range attempts {
+		view.Passed = view.Passed || attempt.Passed
+		if attempt.Percent > view.BestPercent {
+			view.BestPercent = attempt.Percent
+		}
+	}
+	if quiz.MaxAttempts > 0 {
+		left := quiz.MaxAttempts - len(attempts)
+		if left < 0 {
+			left = 0
+		}
+		view.AttemptsLeft = &left
+		view.CanAttempt = left > 0
+	}
+
+	return view, nil
+}
+
+// SubmitAttempt grades and stores a student's answers to the quiz on their assignment
+func (s *QuizService) SubmitAttempt(studentAssignmentID uint, studentID uint, answers map[uint]string) (*models.QuizAttempt, error) {
+	view, err := s.GetStudentQuiz(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !view.CanAttempt {
+		return nil, errors.New("no quiz attempts remaining")
+	}
+
+	// Grade against the stored questions, answers included
+	quiz, err := models.GetQuizByAssignment(s.db, view.Quiz.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only keep answers to questions on this quiz
+	kept := make(map[uint]string, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		if answer, ok := answers[question.ID]; ok {
+			kept[question.ID] = strings.TrimSpace(answer)
+		}
+	}
+
+	attempt := quiz.Grade(kept)
+	attempt.StudentAssignmentID = studentAssignmentID
+	if err := models.CreateQuizAttempt(s.db, attempt); err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// checkAssignmentOwner ensures the assignment exists and belongs to the instructor
+func (s *QuizService) checkAssignmentOwner(assignmentID uint, instructorID uint) error {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return err
+	}
+	if assignment.CreatedByID != instructorID {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+// buildQuiz validates an instructor's input and converts it to a quiz
+func buildQuiz(input QuizInput) (*models.Quiz, error) {
+	if input.PassingScore < 0 || input.PassingScore > 100 {
+		return nil, errors.New("passing score must be between 0 and 100")
+	}
+	if input.MaxAttempts < 0 {
+		return nil, errors.New("max attempts cannot be negative")
+	}
+	if len(input.Questions) == 0 {
+		return nil, errors.New("quiz must have at least one question")
+	}
+	if len(input.Questions) > maxQuizQuestions {
+		return nil, fmt.Errorf("quiz cannot have more than %d questions", maxQuizQuestions)
+	}
+
+	quiz := &models.Quiz{
+		PassingScore: input.PassingScore,
+		MaxAttempts:  input.MaxAttempts,
+	}
+	for i, in := range input.Questions {
+		question, err := buildQuizQuestion(in)
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		quiz.Questions = append(quiz.Questions, *question)
+	}
+	return quiz, nil
+}
+
+// buildQuizQuestion validates one question and normalizes its answers
+func buildQuizQuestion(in QuizQuestionInput) (*models.QuizQuestion, error) {
+	question := &models.QuizQuestion{
+		Type:   in.Type,
+		Prompt: strings.TrimSpace(in.Prompt),
+		Points: in.Points,
+	}
+	if question.Prompt == "" {
+		return nil, errors.New("prompt is required")
+	}
+	if question.Points == 0 {
+		question.Points = 1
+	}
+	if question.Points < 0 {
+		return nil, errors.New("points cannot be negative")
+	}
+
+	switch in.Type {
+	case models.QuestionMultipleChoice:
+		for _, choice := range in.Choices {
+			choice = strings.TrimSpace(choice)
+			if choice == "" {
+				return nil, errors.New("choices cannot be blank")
+			}
+			question.Choices = append(question.Choices, choice)
+		}
+		if len(question.Choices) < 2 {
+			return nil, errors.New("multiple choice questions need at least two choices")
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(in.Answer))
+		if err != nil || index < 0 || index >= len(question.Choices) {
+			return nil, errors.New("answer must be the index of one of the choices")
+		}
+		question.AcceptedAnswers = []string{strconv.Itoa(index)}
+
+	case models.QuestionTrueFalse:
+		answer := strings.ToLower(strings.TrimSpace(in.Answer))
+		if answer != "true" && answer != "false" {
+			return nil, errors.New("answer must be true or false")
+		}
+		question.AcceptedAnswers = []string{answer}
+
+	case models.QuestionShortAnswer:
+		for _, answer := range in.AcceptedAnswers {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, answer)
+			}
+		}
+		if answer := strings.TrimSpace(in.Answer); answer != "" {
+			question.AcceptedAnswers = append(question.AcceptedAnswers, answer)
+		}
+		if len(question.AcceptedAnswers) == 0 {
+			return nil, errors.New("short answer questions need at least one accepted answer")
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown question type %q", in.Type)
+	}
+
+	return question, nil
+}
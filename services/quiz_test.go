@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"zipcodereader/models"
+)
+
+func testQuizInput() QuizInput {
+	return QuizInput{
+		PassingScore: 60,
+		MaxAttempts:  2,
+		Questions: []QuizQuestionInput{
+			{Type: models.QuestionMultipleChoice, Prompt: "Which keyword starts a goroutine?", Choices: []string{"defer", "go", "chan"}, Answer: "1"},
+			{Type: models.QuestionTrueFalse, Prompt: "Channels can be buffered.", Answer: "True"},
+			{Type: models.QuestionShortAnswer, Prompt: "What does a WaitGroup wait for?", AcceptedAnswers: []string{"goroutines", "Goroutines to finish"}, Points: 2},
+		},
+	}
+}
+
+func TestSaveQuizValidation(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewQuizService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	invalid := []QuizInput{
+		{PassingScore: 101, Questions: testQuizInput().Questions},
+		{PassingScore: 50},
+		{Questions: []QuizQuestionInput{{Type: models.QuestionMultipleChoice, Prompt: "Pick", Choices: []string{"a", "b"}, Answer: "2"}}},
+		{Questions: []QuizQuestionInput{{Type: models.QuestionTrueFalse, Prompt: "Yes?", Answer: "maybe"}}},
+		{Questions: []QuizQuestionInput{{Type: models.QuestionShortAnswer, Prompt: "Why?"}}},
+		{Questions: []QuizQuestionInput{{Type: "essay", Prompt: "Discuss"}}},
+	}
+	for i, input := range invalid {
+		if _, err := service.SaveQuiz(assignment.ID, instructor.ID, input); err == nil {
+			t.Errorf("Expected invalid quiz %d to be rejected", i)
+		}
+	}
+
+	if _, err := service.SaveQuiz(assignment.ID, otherInstructor.ID, testQuizInput()); err == nil {
+		t.Error("Expected other instructors to be unable to add a quiz")
+	}
+
+	quiz, err := service.SaveQuiz(assignment.ID, instructor.ID, testQuizInput())
+	if err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	if len(quiz.Questions) != 3 || quiz.Questions[1].AcceptedAnswers[0] != "true" {
+		t.Errorf("Expected three questions with normalized answers, got %+v", quiz.Questions)
+	}
+
+	replacement := testQuizInput()
+	replacement.Questions = replacement.Questions[:1]
+	replaced, err := service.SaveQuiz(assignment.ID, instructor.ID, replacement)
+	if err != nil {
+		t.Fatalf("Failed to replace quiz: %v", err)
+	}
+	if replaced.ID != quiz.ID || len(replaced.Questions) != 1 {
+		t.Errorf("Expected the quiz to be replaced in place, got %+v", replaced)
+	}
+}
+
+func TestQuizAttemptsGateCompletion(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewQuizService(db)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	progressService := NewProgressTrackingService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+
+	if _, err := service.GetStudentQuiz(sa.ID, student.ID); err == nil {
+		t.Error("Expected no quiz before one is added")
+	}
+
+	quiz, err := service.SaveQuiz(assignment.ID, instructor.ID, testQuizInput())
+	if err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	mc, tf, short := quiz.Questions[0].ID, quiz.Questions[1].ID, quiz.Questions[2].ID
+
+	view, err := service.GetStudentQuiz(sa.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student quiz: %v", err)
+	}
+	for _, question := range view.Quiz.Questions {
+		if len(question.AcceptedAnswers) != 0 {
+			t.Errorf("Expected answers to be hidden from students, got %+v", question)
+		}
+	}
+	if !view.Required || !view.CanAttempt {
+		t.Errorf("Expected a required quiz that can be attempted, got %+v", view)
+	}
+
+	if err := studentService.MarkAsCompletedByID(sa.ID, student.ID); !errors.Is(err, models.ErrQuizNotPassed) {
+		t.Fatalf("Expected ErrQuizNotPassed before the quiz, got %v", err)
+	}
+
+	// One of four points is 25%, below the passing score
+	failed, err := service.SubmitAttempt(sa.ID, student.ID, map[uint]string{mc: "0", tf: "true", short: "channels"})
+	if err != nil {
+		t.Fatalf("Failed to submit attempt: %v", err)
+	}
+	if failed.Score != 1 || failed.MaxScore != 4 || failed.Percent != 25 || failed.Passed {
+		t.Errorf("Expected a failing 1/4, got %+v", failed)
+	}
+	if err := studentService.MarkAsCompletedByID(sa.ID, student.ID); !errors.Is(err, models.ErrQuizNotPassed) {
+		t.Errorf("Expected a failed attempt to leave completion blocked, got %v", err)
+	}
+
+	passed, err := service.SubmitAttempt(sa.ID, student.ID, map[uint]string{mc: "1", tf: "false", short: "  goroutines  TO finish "})
+	if err != nil {
+		t.Fatalf("Failed to submit attempt: %v", err)
+	}
+	if passed.Score != 3 || passed.Percent != 75 || !passed.Passed {
+		t.Errorf("Expected a passing 3/4, got %+v", passed)
+	}
+
+	if _, err := service.SubmitAttempt(sa.ID, student.ID, map[uint]string{mc: "1"}); err == nil {
+		t.Error("Expected attempts beyond the limit to be rejected")
+	}
+
+	if err := studentService.MarkAsCompletedByID(sa.ID, student.ID); err != nil {
+		t.Fatalf("Expected completion after passing, got %v", err)
+	}
+
+	report, err := progressService.GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress report: %v", err)
+	}
+	if !report.HasQuiz || report.QuizPassingScore != 60 || report.QuizPassedCount != 1 {
+		t.Errorf("Expected quiz totals on the report, got %+v", report)
+	}
+	detail := report.StudentDetails[0]
+	if detail.QuizAttempts != 2 || detail.QuizBestPercent == nil || *detail.QuizBestPercent != 75 || !detail.QuizPassed {
+		t.Errorf("Expected student quiz results on the report, got %+v", detail)
+	}
+
+	if err := service.DeleteQuiz(assignment.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to delete quiz: %v", err)
+	}
+	results, err := models.GetQuizResultsByAssignment(db, assignment.ID)
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected attempts to be removed with the quiz, got %+v (%v)", results, err)
+	}
+}
+
+func TestQuizHiddenBeforeRelease(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewQuizService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	assignment, sa := createScheduledStudentAssignment(t, db, instructor, student)
+
+	quiz, err := service.SaveQuiz(assignment.ID, instructor.ID, testQuizInput())
+	if err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	if _, err := service.GetStudentQuiz(sa.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a scheduled assignment's quiz to be hidden, got %v", err)
+	}
+	_, err = service.SubmitAttempt(sa.ID, student.ID, map[uint]string{quiz.Questions[0].ID: "1"})
+	if err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected attempts on a scheduled assignment to be rejected, got %v", err)
+	}
+}
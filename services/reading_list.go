@@ -0,0 +1,364 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// ReadingListService handles business logic for reading lists
+type ReadingListService struct {
+	db       *gorm.DB
+	clientIP string // recorded in the audit log
+}
+
+// NewReadingListService creates a new reading list service
+func NewReadingListService(db *gorm.DB) *ReadingListService {
+	return &ReadingListService{db: db}
+}
+
+// WithClientIP returns a copy of the service that records the given address in the audit log
+func (s *ReadingListService) WithClientIP(ip string) *ReadingListService {
+	scoped := *s
+	scoped.clientIP = ip
+	return &scoped
+}
+
+// ReadingListItemInput describes one item of a reading list in order
+type ReadingListItemInput struct {
+	AssignmentID  uint
+	Prerequisites []int // zero-based positions of earlier items that must be completed first
+}
+
+// CreateReadingListInput represents input for creating a reading list
+type CreateReadingListInput struct {
+	Title       string
+	Description string
+	Sequential  bool
+	Items       []ReadingListItemInput
+}
+
+// ReadingListItemProgress is a student's state for a single reading list item
+type ReadingListItemProgress struct {
+	ItemID              uint              `json:"item_id"`
+	Position            int               `json:"position"`
+	Assignment          models.Assignment `json:"assignment"`
+	StudentAssignmentID uint              `json:"student_assignment_id"`
+	Status              string            `json:"status"`
+	Locked              bool              `json:"locked"`
+	BlockedBy           []uint            `json:"blocked_by,omitempty"` // assignment IDs still to be completed
+	released            bool              // scheduled and withdrawn readings are left out of the student's view
+}
+
+// ReadingListProgress is a student's progress through a reading list
+type ReadingListProgress struct {
+	ReadingListID uint                      `json:"reading_list_id"`
+	Title         string                    `json:"title"`
+	Description   string                    `json:"description"`
+	Items         []ReadingListItemProgress `json:"items"`
+	Completed     int                       `json:"completed"`
+	Total         int                       `json:"total"`
+	Percentage    float64                   `json:"percentage"`
+}
+
+// CreateReadingList creates a reading list from assignments the instructor owns
+func (s *ReadingListService) CreateReadingList(instructorID uint, input CreateReadingListInput) (*models.ReadingList, error) {
+	var instructor models.User
+	if err := s.db.First(&instructor, instructorID).Error; err != nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	if !instructor.IsInstructor() {
+		return nil, errors.New("user is not an instructor")
+	}
+
+	if input.Title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	if len(input.Items) == 0 {
+		return nil, errors.New("at least one item is required")
+	}
+
+	seen := make(map[uint]bool)
+	for position, item := range input.Items {
+		if seen[item.AssignmentID] {
+			return nil, errors.New("an assignment can only appear once in a reading list")
+		}
+		seen[item.AssignmentID] = true
+
+		assignment, err := models.GetAssignmentByID(s.db, item.AssignmentID)
+		if err != nil {
+			return nil, fmt.Errorf("assignment %d not found", item.AssignmentID)
+		}
+		if assignment.CreatedByID != instructorID {
+			return nil, errors.New("access denied")
+		}
+
+		// Prerequisites must point backwards, which keeps the graph acyclic
+		for _, prerequisite := range item.Prerequisites {
+			if prerequisite < 0 || prerequisite >= position {
+				return nil, fmt.Errorf("item %d can only depend on earlier items", position+1)
+			}
+		}
+	}
+
+	list := &models.ReadingList{
+		Title:       input.Title,
+		Description: input.Description,
+		Sequential:  input.Sequential,
+		CreatedByID: instructorID,
+	}
+	for position, item := range input.Items {
+		list.Items = append(list.Items, models.ReadingListItem{
+			AssignmentID: item.AssignmentID,
+			Position:     position,
+		})
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := models.CreateReadingList(tx, list); err != nil {
+			return err
+		}
+
+		for position, item := range input.Items {
+			if len(item.Prerequisites) == 0 {
+				continue
+			}
+
+			var prerequisites []models.ReadingListItem
+			for _, prerequisite := range item.Prerequisites {
+				prerequisites = append(prerequisites, list.Items[prerequisite])
+			}
+			if err := tx.Model(&list.Items[position]).Association("Prerequisites").Append(prerequisites); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.GetReadingListByID(s.db, list.ID)
+}
+
+// GetReadingList retrieves a reading list owned by the instructor
+func (s *ReadingListService) GetReadingList(listID uint, instructorID uint) (*models.ReadingList, error) {
+	list, err := models.GetReadingListByID(s.db, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	return list, nil
+}
+
+// GetReadingLists retrieves all reading lists for an instructor
+func (s *ReadingListService) GetReadingLists(instructorID uint) ([]models.ReadingList, error) {
+	return models.GetReadingListsByInstructor(s.db, instructorID)
+}
+
+// DeleteReadingList deletes a reading list owned by the instructor
+func (s *ReadingListService) DeleteReadingList(listID uint, instructorID uint) error {
+	list, err := s.GetReadingList(listID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	return list.Delete(s.db)
+}
+
+// AssignReadingList assigns every item of a reading list to the given students and groups in one step
+func (s *ReadingListService) AssignReadingList(listID uint, studentIDs []uint, groupIDs []uint, instructorID uint) error {
+	list, err := s.GetReadingList(listID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	if len(studentIDs) == 0 && len(groupIDs) == 0 {
+		return errors.New("at least one student or group is required")
+	}
+
+	var students []models.User
+	if len(studentIDs) > 0 {
+		if err := s.db.Where("id IN ? AND role = ?", studentIDs, "student").Find(&students).Error; err != nil {
+			return err
+		}
+
+		if len(students) != len(studentIDs) {
+			return errors.New("some students not found or not valid students")
+		}
+	}
+
+	// Groups are expanded to their current members; students who join later are assigned separately
+	members, err := groupStudentIDs(s.db, groupIDs, instructorID)
+	if err != nil {
+		return err
+	}
+	if len(members) > 0 {
+		studentIDs = uniqueIDs(append(studentIDs, members...))
+		if err := s.db.Where("id IN ?", studentIDs).Find(&students).Error; err != nil {
+			return err
+		}
+	}
+	if len(studentIDs) == 0 {
+		return errors.New("the selected groups have no students")
+	}
+
+	var events []models.AuditEvent
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := list.AddStudents(tx, students); err != nil {
+			return err
+		}
+
+		for _, item := range list.Items {
+			var newStudentIDs []uint
+			for _, studentID := range studentIDs {
+				if _, err := models.GetStudentAssignment(tx, item.AssignmentID, studentID); err != nil {
+					newStudentIDs = append(newStudentIDs, studentID)
+				}
+			}
+
+			if len(newStudentIDs) == 0 {
+				continue
+			}
+			if err := models.BulkCreateStudentAssignments(tx, item.AssignmentID, newStudentIDs); err != nil {
+				return err
+			}
+			events = append(events, studentAuditEvents(instructorID, models.AuditStudentAssigned, item.AssignmentID, newStudentIDs, nil, s.clientIP)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 {
+		recordAudit(s.db, events...)
+	}
+	return nil
+}
+
+// GetStudentReadingLists returns the student's progress through each assigned reading list
+func (s *ReadingListService) GetStudentReadingLists(studentID uint) ([]ReadingListProgress, error) {
+	lists, err := models.GetReadingListsByStudent(s.db, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]ReadingListProgress, 0, len(lists))
+	for i := range lists {
+		listProgress, err := readingListProgress(s.db, &lists[i], studentID)
+		if err != nil {
+			return nil, err
+		}
+
+		released := listProgress.Items[:0]
+		for _, item := range listProgress.Items {
+			if item.released {
+				released = append(released, item)
+			}
+		}
+		listProgress.Items = released
+		progress = append(progress, *listProgress)
+	}
+
+	return progress, nil
+}
+
+// readingListProgress computes the student's status and lock state for every item in a list
+func readingListProgress(db *gorm.DB, list *models.ReadingList, studentID uint) (*ReadingListProgress, error) {
+	assignmentIDs := make([]uint, 0, len(list.Items))
+	for _, item := range list.Items {
+		assignmentIDs = append(assignmentIDs, item.AssignmentID)
+	}
+
+	var studentAssignments []models.StudentAssignment
+	if err := db.Where("student_id = ? AND assignment_id IN ?", studentID, assignmentIDs).Find(&studentAssignments).Error; err != nil {
+		return nil, err
+	}
+
+	byAssignment := make(map[uint]models.StudentAssignment, len(studentAssignments))
+	for _, sa := range studentAssignments {
+		byAssignment[sa.AssignmentID] = sa
+	}
+
+	// Excused items count as done so they never block the rest of the list
+	satisfied := func(assignmentID uint) bool {
+		sa, ok := byAssignment[assignmentID]
+		return ok && (sa.Status == models.StatusCompleted || sa.Excused)
+	}
+
+	progress := &ReadingListProgress{
+		ReadingListID: list.ID,
+		Title:         list.Title,
+		Description:   list.Description,
+	}
+
+	// Every item is kept so locks can be checked, but only released readings count towards progress
+	now := time.Now()
+	for i, item := range list.Items {
+		itemProgress := ReadingListItemProgress{
+			ItemID:     item.ID,
+			Position:   item.Position,
+			Assignment: item.Assignment,
+			released:   item.Assignment.IsPublished(now),
+		}
+
+		if sa, ok := byAssignment[item.AssignmentID]; ok {
+			itemProgress.StudentAssignmentID = sa.ID
+			itemProgress.Status = sa.Status
+		}
+
+		for _, prerequisite := range list.ItemPrerequisites(i) {
+			if !satisfied(prerequisite.AssignmentID) {
+				itemProgress.BlockedBy = append(itemProgress.BlockedBy, prerequisite.AssignmentID)
+			}
+		}
+		itemProgress.Locked = len(itemProgress.BlockedBy) > 0
+
+		if itemProgress.released {
+			progress.Total++
+			if satisfied(item.AssignmentID) {
+				progress.Completed++
+			}
+		}
+		progress.Items = append(progress.Items, itemProgress)
+	}
+
+	if progress.Total > 0 {
+		progress.Percentage = float64(progress.Completed) / float64(progress.Total) * 100
+	}
+
+	return progress, nil
+}
+
+// checkReadingListUnlocked returns an error when the assignment is a locked item in one of the student's reading lists
+func checkReadingListUnlocked(db *gorm.DB, assignmentID, studentID uint) error {
+	lists, err := models.GetReadingListsContainingAssignment(db, assignmentID, studentID)
+	if err != nil {
+		return err
+	}
+
+	for i := range lists {
+		progress, err := readingListProgress(db, &lists[i], studentID)
+		if err != nil {
+			return err
+		}
+
+		for j, item := range progress.Items {
+			if lists[i].Items[j].AssignmentID == assignmentID && item.Locked {
+				return fmt.Errorf("assignment is locked until earlier readings in '%s' are completed", lists[i].Title)
+			}
+		}
+	}
+
+	return nil
+}
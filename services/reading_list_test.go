@@ -0,0 +1,222 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestReadingListPrerequisites(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	service := NewReadingListService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	var assignmentIDs []uint
+	for _, title := range []string{"Part A", "Part B", "Part C"} {
+		assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+			Title: title,
+			URL:   "https://example.com/" + strings.ToLower(strings.ReplaceAll(title, " ", "-")),
+		})
+		if err != nil {
+			t.Fatalf("Failed to create assignment: %v", err)
+		}
+		assignmentIDs = append(assignmentIDs, assignment.ID)
+	}
+
+	list, err := service.CreateReadingList(instructor.ID, CreateReadingListInput{
+		Title:      "Go Fundamentals",
+		Sequential: true,
+		Items: []ReadingListItemInput{
+			{AssignmentID: assignmentIDs[0]},
+			{AssignmentID: assignmentIDs[1]},
+			{AssignmentID: assignmentIDs[2], Prerequisites: []int{0}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reading list: %v", err)
+	}
+	if len(list.Items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(list.Items))
+	}
+
+	if err := service.AssignReadingList(list.ID, []uint{student.ID}, nil, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign reading list: %v", err)
+	}
+
+	// Part B requires Part A
+	if err := studentService.MarkAsCompleted(assignmentIDs[1], student.ID); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("Expected locked error for Part B, got %v", err)
+	}
+
+	if err := studentService.MarkAsCompleted(assignmentIDs[0], student.ID); err != nil {
+		t.Fatalf("Failed to complete Part A: %v", err)
+	}
+
+	progress, err := service.GetStudentReadingLists(student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get reading list progress: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("Expected 1 reading list, got %d", len(progress))
+	}
+	if progress[0].Completed != 1 || progress[0].Total != 3 {
+		t.Errorf("Expected 1 of 3 completed, got %d of %d", progress[0].Completed, progress[0].Total)
+	}
+	// Part C depends only on Part A, so both B and C are unlocked now
+	for _, item := range progress[0].Items {
+		if item.Locked {
+			t.Errorf("Expected item %d to be unlocked", item.Position)
+		}
+	}
+
+	if err := studentService.MarkAsCompleted(assignmentIDs[2], student.ID); err != nil {
+		t.Errorf("Expected Part C to be unlocked after Part A: %v", err)
+	}
+}
+
+func TestReadingListHidesUnreleasedItems(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingListService(db)
+	studentService := NewStudentAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	released, err := NewAssignmentService(db).CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "This Week", URL: "https://example.com/this-week"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	scheduled, _ := createScheduledStudentAssignment(t, db, instructor, student)
+
+	list, err := service.CreateReadingList(instructor.ID, CreateReadingListInput{
+		Title: "Term Reading",
+		Items: []ReadingListItemInput{{AssignmentID: released.ID}, {AssignmentID: scheduled.ID}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reading list: %v", err)
+	}
+	if err := service.AssignReadingList(list.ID, []uint{student.ID}, nil, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign reading list: %v", err)
+	}
+	if err := studentService.MarkAsCompleted(released.ID, student.ID); err != nil {
+		t.Fatalf("Failed to complete assignment: %v", err)
+	}
+
+	// The scheduled reading is neither shown nor counted until it is published
+	progress, err := service.GetStudentReadingLists(student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get reading list progress: %v", err)
+	}
+	if len(progress) != 1 || len(progress[0].Items) != 1 || progress[0].Items[0].Assignment.ID != released.ID {
+		t.Fatalf("Expected only the released reading, got %+v", progress)
+	}
+	if progress[0].Completed != 1 || progress[0].Total != 1 || progress[0].Percentage != 100 {
+		t.Errorf("Expected 1 of 1 completed, got %d of %d (%v%%)", progress[0].Completed, progress[0].Total, progress[0].Percentage)
+	}
+	encoded, _ := json.Marshal(progress)
+	if strings.Contains(string(encoded), "next-week") || strings.Contains(string(encoded), "Next Week") {
+		t.Errorf("Expected the scheduled reading's details to stay hidden, got %s", encoded)
+	}
+}
+
+func TestCreateReadingListValidation(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	service := NewReadingListService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Only", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+
+	// Prerequisites must refer to earlier items
+	_, err = service.CreateReadingList(instructor.ID, CreateReadingListInput{
+		Title: "Forward Reference",
+		Items: []ReadingListItemInput{{AssignmentID: assignment.ID, Prerequisites: []int{0}}},
+	})
+	if err == nil {
+		t.Error("Expected error for self-referencing prerequisite")
+	}
+
+	// Other instructors cannot build lists from someone else's assignments
+	_, err = service.CreateReadingList(otherInstructor.ID, CreateReadingListInput{
+		Title: "Borrowed",
+		Items: []ReadingListItemInput{{AssignmentID: assignment.ID}},
+	})
+	if err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+}
+
+func TestAssignReadingListToGroups(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	groupService := NewGroupService(db)
+	service := NewReadingListService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	alice := createTestUser(t, db, "alice", "student")
+	bob := createTestUser(t, db, "bob", "student")
+	carol := createTestUser(t, db, "carol", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Part A", URL: "https://example.com/part-a"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	list, err := service.CreateReadingList(instructor.ID, CreateReadingListInput{
+		Title: "Go Fundamentals",
+		Items: []ReadingListItemInput{{AssignmentID: assignment.ID}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reading list: %v", err)
+	}
+
+	group, err := groupService.CreateGroup(instructor.ID, GroupInput{Name: "Cohort A", StudentIDs: []uint{alice.ID, bob.ID}})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	empty, err := groupService.CreateGroup(instructor.ID, GroupInput{Name: "Cohort B"})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	otherGroup, err := groupService.CreateGroup(otherInstructor.ID, GroupInput{Name: "Cohort C", StudentIDs: []uint{carol.ID}})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	if err := service.AssignReadingList(list.ID, nil, nil, instructor.ID); err == nil {
+		t.Error("Expected assigning to nobody to be rejected")
+	}
+	if err := service.AssignReadingList(list.ID, nil, []uint{otherGroup.ID}, instructor.ID); err == nil {
+		t.Error("Expected another instructor's group to be rejected")
+	}
+	if err := service.AssignReadingList(list.ID, nil, []uint{empty.ID}, instructor.ID); err == nil {
+		t.Error("Expected a group without students to be rejected")
+	}
+
+	// Alice is both on the roster and in the group
+	if err := service.AssignReadingList(list.ID, []uint{alice.ID}, []uint{group.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign reading list: %v", err)
+	}
+	for _, student := range []*models.User{alice, bob} {
+		lists, err := service.GetStudentReadingLists(student.ID)
+		if err != nil || len(lists) != 1 || lists[0].Total != 1 {
+			t.Errorf("Expected %s to have the reading list, got %+v (%v)", student.Username, lists, err)
+		}
+	}
+	if lists, _ := service.GetStudentReadingLists(carol.ID); len(lists) != 0 {
+		t.Errorf("Expected Carol to be left out, got %+v", lists)
+	}
+	studentAssignments, _ := models.GetStudentAssignmentsByAssignment(db, assignment.ID)
+	if len(studentAssignments) != 2 {
+		t.Errorf("Expected Alice and Bob to be assigned once each, got %d", len(studentAssignments))
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// maxNoteLength limits the size of a reading note in bytes
+const maxNoteLength = 50000
+
+// ReadingNoteService handles student notes and reflections on assignments
+type ReadingNoteService struct {
+	db                       *gorm.DB
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewReadingNoteService creates a new reading note service
+func NewReadingNoteService(db *gorm.DB) *ReadingNoteService {
+	return &ReadingNoteService{db: db, studentAssignmentService: NewStudentAssignmentService(db)}
+}
+
+// GetNote returns a student's note on one of their assignments; a student who has not
+// written anything yet gets an empty note
+func (s *ReadingNoteService) GetNote(studentAssignmentID uint, studentID uint) (*models.ReadingNote, error) {
+	// Notes can only be kept on released readings
+	if _, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID); err != nil {
+		return nil, errors.New("assignment not found")
+	}
+
+	note, err := models.GetReadingNote(s.db, studentAssignmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.ReadingNote{StudentAssignmentID: studentAssignmentID}, nil
+	}
+	return note, err
+}
+
+// SaveNote stores a student's Markdown note along with its sanitized rendering
+func (s *ReadingNoteService) SaveNote(studentAssignmentID uint, studentID uint, content string) (*models.ReadingNote, error) {
+	if len(content) > maxNoteLength {
+		return nil, errors.New("note is too long")
+	}
+
+	note, err := s.GetNote(studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Content = strings.TrimSpace(content)
+	note.ContentHTML = renderMarkdown(note.Content)
+	note.WordCount = models.CountWords(note.Content)
+
+	if err := models.SaveReadingNote(s.db, note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// GetReflections returns what students wrote on an instructor's assignment
+func (s *ReadingNoteService) GetReflections(assignmentID uint, instructorID uint) (*models.Assignment, []models.ReflectionEntry, error) {
+	assignment, err := models.GetAssignmentByID(s.db, assignmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if assignment.CreatedByID != instructorID {
+		return nil, nil, errors.New("access denied")
+	}
+
+	reflections, err := models.GetReflectionsByAssignment(s.db, assignmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return assignment, reflections, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"zipcodereader/models"
+)
+
+func TestSaveNote(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingNoteService(db)
+	assignmentService := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	otherStudent := createTestUser(t, db, "student2", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Concurrency", URL: "https://example.com/concurrency"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+
+	empty, err := service.GetNote(sa.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get empty note: %v", err)
+	}
+	if empty.ID != 0 || empty.Content != "" {
+		t.Errorf("Expected an empty note before writing, got %+v", empty)
+	}
+
+	note, err := service.SaveNote(sa.ID, student.ID, "Channels **synchronize** goroutines <script>x</script>")
+	if err != nil {
+		t.Fatalf("Failed to save note: %v", err)
+	}
+	if note.WordCount != 4 {
+		t.Errorf("Expected 4 words, got %d", note.WordCount)
+	}
+	if !strings.Contains(note.ContentHTML, "<strong>synchronize</strong>") || strings.Contains(note.ContentHTML, "<script>") {
+		t.Errorf("Expected sanitized rendering, got %q", note.ContentHTML)
+	}
+
+	updated, err := service.SaveNote(sa.ID, student.ID, "Rewritten")
+	if err != nil {
+		t.Fatalf("Failed to update note: %v", err)
+	}
+	if updated.ID != note.ID || updated.WordCount != 1 {
+		t.Errorf("Expected the note to be updated in place, got %+v", updated)
+	}
+
+	if _, err := service.SaveNote(sa.ID, otherStudent.ID, "Not mine"); err == nil {
+		t.Error("Expected other students to be unable to write the note")
+	}
+	if _, err := service.SaveNote(sa.ID, student.ID, strings.Repeat("a", maxNoteLength+1)); err == nil {
+		t.Error("Expected overly long notes to be rejected")
+	}
+
+	_, reflections, err := service.GetReflections(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get reflections: %v", err)
+	}
+	if len(reflections) != 1 || reflections[0].StudentName != "student1" || reflections[0].Content != "Rewritten" {
+		t.Errorf("Expected student1's reflection, got %+v", reflections)
+	}
+	if _, _, err := service.GetReflections(assignment.ID, student.ID); err == nil {
+		t.Error("Expected students to be denied the reflections")
+	}
+}
+
+func TestCompletionRequiresReflection(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingNoteService(db)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:              "Concurrency",
+		URL:                "https://example.com/concurrency",
+		MinReflectionWords: 5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign student: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+
+	err = studentService.MarkAsCompletedByID(sa.ID, student.ID)
+	if !errors.Is(err, models.ErrReflectionRequired) {
+		t.Fatalf("Expected ErrReflectionRequired without a note, got %v", err)
+	}
+
+	if _, err := service.SaveNote(sa.ID, student.ID, "Too short really"); err != nil {
+		t.Fatalf("Failed to save note: %v", err)
+	}
+	if err := studentService.MarkAsCompletedByID(sa.ID, student.ID); !errors.Is(err, models.ErrReflectionRequired) {
+		t.Errorf("Expected a short reflection to be rejected, got %v", err)
+	}
+
+	if _, err := service.SaveNote(sa.ID, student.ID, "Channels let goroutines share memory by communicating."); err != nil {
+		t.Fatalf("Failed to save note: %v", err)
+	}
+	if err := studentService.MarkAsCompletedByID(sa.ID, student.ID); err != nil {
+		t.Fatalf("Expected completion with a reflection, got %v", err)
+	}
+
+	report, err := NewProgressTrackingService(db).GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress report: %v", err)
+	}
+	if report.MinReflectionWords != 5 || len(report.StudentDetails) != 1 || report.StudentDetails[0].ReflectionWords != 7 {
+		t.Errorf("Expected the reflection in the progress report, got %+v", report)
+	}
+}
+
+func TestNotesHiddenBeforeRelease(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingNoteService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	_, sa := createScheduledStudentAssignment(t, db, instructor, student)
+
+	if _, err := service.GetNote(sa.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected notes on a scheduled assignment to be hidden, got %v", err)
+	}
+	if _, err := service.SaveNote(sa.ID, student.ID, "Read it early"); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected saving a note on a scheduled assignment to be rejected, got %v", err)
+	}
+}
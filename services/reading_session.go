@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxHeartbeatGap is the longest gap between heartbeats still counted as reading;
+	// pages send one every 30 seconds, and background tabs may be throttled to one a minute
+	maxHeartbeatGap = 2 * time.Minute
+	// maxSessionLength caps the active time a single session can record
+	maxSessionLength = 4 * time.Hour
+)
+
+// ReadingSessionService records the time students actively spend on their readings
+type ReadingSessionService struct {
+	db                       *gorm.DB
+	studentAssignmentService *StudentAssignmentService
+}
+
+// NewReadingSessionService creates a new reading session service
+func NewReadingSessionService(db *gorm.DB) *ReadingSessionService {
+	return &ReadingSessionService{
+		db:                       db,
+		studentAssignmentService: NewStudentAssignmentService(db),
+	}
+}
+
+// StartSession opens a reading session on one of a student's assignments. Any session the
+// student left open on it, say in another tab, is closed first.
+func (s *ReadingSessionService) StartSession(studentAssignmentID uint, studentID uint, source string) (*models.ReadingSession, error) {
+	switch source {
+	case models.ReadingSourceTimer, models.ReadingSourceLink, models.ReadingSourceArchive:
+	default:
+		return nil, errors.New("invalid reading session source")
+	}
+
+	if _, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID); err != nil {
+		return nil, errors.New("assignment not found")
+	}
+
+	open, err := models.GetOpenReadingSessions(s.db, studentAssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range open {
+		ended := open[i].LastSeenAt
+		open[i].EndedAt = &ended
+		if err := models.SaveReadingSession(s.db, &open[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	session := &models.ReadingSession{
+		StudentAssignmentID: studentAssignmentID,
+		StudentID:           studentID,
+		Source:              source,
+		StartedAt:           now,
+		LastSeenAt:          now,
+	}
+	if err := models.CreateReadingSession(s.db, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Heartbeat records that the student is still reading
+func (s *ReadingSessionService) Heartbeat(sessionID uint, studentID uint) (*models.ReadingSession, error) {
+	return s.record(sessionID, studentID, false)
+}
+
+// StopSession records a final heartbeat and closes the session
+func (s *ReadingSessionService) StopSession(sessionID uint, studentID uint) (*models.ReadingSession, error) {
+	return s.record(sessionID, studentID, true)
+}
+
+// GetReadingTime returns the active reading time a student has recorded on one of their assignments
+func (s *ReadingSessionService) GetReadingTime(studentAssignmentID uint, studentID uint) (models.ReadingTime, error) {
+	if _, err := s.studentAssignmentService.GetStudentAssignmentByID(studentAssignmentID, studentID); err != nil {
+		return models.ReadingTime{}, errors.New("assignment not found")
+	}
+	return models.GetReadingTime(s.db, studentAssignmentID)
+}
+
+// record adds the time since the session's last heartbeat, unless the gap is too long
+// to have been spent reading
+func (s *ReadingSessionService) record(sessionID uint, studentID uint, end bool) (*models.ReadingSession, error) {
+	session, err := models.GetReadingSessionByID(s.db, sessionID)
+	if err != nil {
+		return nil, errors.New("reading session not found")
+	}
+	if session.StudentID != studentID {
+		return nil, errors.New("access denied")
+	}
+	if !session.IsOpen() {
+		return nil, errors.New("reading session has ended")
+	}
+	// A reading withdrawn while the session was open stops counting time
+	if _, err := s.studentAssignmentService.GetStudentAssignmentByID(session.StudentAssignmentID, studentID); err != nil {
+		return nil, errors.New("assignment not found")
+	}
+
+	now := time.Now()
+	if gap := now.Sub(session.LastSeenAt); gap > 0 && gap <= maxHeartbeatGap {
+		session.ActiveSeconds += int(gap.Seconds())
+		if limit := int(maxSessionLength.Seconds()); session.ActiveSeconds > limit {
+			session.ActiveSeconds = limit
+		}
+	}
+	session.LastSeenAt = now
+	if end {
+		session.EndedAt = &now
+	}
+
+	if err := models.SaveReadingSession(s.db, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
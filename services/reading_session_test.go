@@ -0,0 +1,155 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestReadingSessions(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingSessionService(db)
+	assignmentService := NewAssignmentService(db)
+	progressService := NewProgressTrackingService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	other := createTestUser(t, db, "student2", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{student.ID, other.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	sa, err := models.GetStudentAssignment(db, assignment.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get student assignment: %v", err)
+	}
+
+	if _, err := service.StartSession(sa.ID, student.ID, "daydream"); err == nil {
+		t.Error("Expected an unknown source to be rejected")
+	}
+	if _, err := service.StartSession(sa.ID, other.ID, models.ReadingSourceTimer); err == nil {
+		t.Error("Expected students to be unable to time another student's reading")
+	}
+
+	// rewind moves a session's last heartbeat into the past
+	rewind := func(session *models.ReadingSession, by time.Duration) {
+		if err := db.Model(session).Update("last_seen_at", time.Now().Add(-by)).Error; err != nil {
+			t.Fatalf("Failed to rewind session: %v", err)
+		}
+	}
+
+	session, err := service.StartSession(sa.ID, student.ID, models.ReadingSourceTimer)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	if _, err := service.Heartbeat(session.ID, other.ID); err == nil {
+		t.Error("Expected students to be unable to send heartbeats for another student")
+	}
+
+	rewind(session, 90*time.Second)
+	if session, err = service.Heartbeat(session.ID, student.ID); err != nil {
+		t.Fatalf("Failed to send heartbeat: %v", err)
+	}
+	if session.ActiveSeconds < 89 || session.ActiveSeconds > 91 {
+		t.Errorf("Expected about 90 active seconds, got %d", session.ActiveSeconds)
+	}
+
+	// A long silence means the student walked away, so it adds nothing
+	rewind(session, time.Hour)
+	if session, err = service.StopSession(session.ID, student.ID); err != nil {
+		t.Fatalf("Failed to stop session: %v", err)
+	}
+	if session.ActiveSeconds > 91 || session.IsOpen() {
+		t.Errorf("Expected a closed session without the idle hour, got %+v", session)
+	}
+	if _, err := service.Heartbeat(session.ID, student.ID); err == nil {
+		t.Error("Expected heartbeats on a stopped session to be rejected")
+	}
+
+	// Starting a new session closes one left open in another tab
+	stale, err := service.StartSession(sa.ID, student.ID, models.ReadingSourceLink)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	rewind(stale, 30*time.Second)
+	archive, err := service.StartSession(sa.ID, student.ID, models.ReadingSourceArchive)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	if _, err := service.Heartbeat(stale.ID, student.ID); err == nil {
+		t.Error("Expected the older session to be closed")
+	}
+	rewind(archive, 60*time.Second)
+	if _, err := service.StopSession(archive.ID, student.ID); err != nil {
+		t.Fatalf("Failed to stop session: %v", err)
+	}
+
+	readingTime, err := service.GetReadingTime(sa.ID, student.ID)
+	if err != nil {
+		t.Fatalf("Failed to get reading time: %v", err)
+	}
+	if readingTime.Sessions != 3 || readingTime.ActiveMinutes() != 3 {
+		t.Errorf("Expected 3 sessions and about 3 active minutes, got %+v", readingTime)
+	}
+
+	// Reports show active reading next to the elapsed time
+	if err := db.Model(sa).Updates(map[string]interface{}{"status": models.StatusCompleted, "completed_at": time.Now()}).Error; err != nil {
+		t.Fatalf("Failed to complete assignment: %v", err)
+	}
+	report, err := progressService.GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.AverageActiveMinutes != 3 {
+		t.Errorf("Expected an average of 3 active minutes, got %d", report.AverageActiveMinutes)
+	}
+	for _, detail := range report.StudentDetails {
+		want := 0
+		if detail.StudentID == student.ID {
+			want = 3
+		}
+		if detail.ActiveMinutes != want {
+			t.Errorf("Expected %s to have %d active minutes, got %d", detail.StudentName, want, detail.ActiveMinutes)
+		}
+	}
+	summary, err := progressService.GetInstructorProgressSummary(instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	if summary.AverageActiveMinutes != 3 {
+		t.Errorf("Expected a summary average of 3 active minutes, got %d", summary.AverageActiveMinutes)
+	}
+}
+
+func TestReadingSessionsFollowRelease(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewReadingSessionService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+	assignment, sa := createScheduledStudentAssignment(t, db, instructor, student)
+
+	if _, err := service.StartSession(sa.ID, student.ID, models.ReadingSourceTimer); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a scheduled assignment to be untimed, got %v", err)
+	}
+	if _, err := service.GetReadingTime(sa.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a scheduled assignment's reading time to be hidden, got %v", err)
+	}
+
+	if err := db.Model(assignment).Update("publish_at", nil).Error; err != nil {
+		t.Fatalf("Failed to release assignment: %v", err)
+	}
+	session, err := service.StartSession(sa.ID, student.ID, models.ReadingSourceTimer)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+
+	if err := db.Model(assignment).Update("unpublish_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("Failed to withdraw assignment: %v", err)
+	}
+	if _, err := service.StopSession(session.ID, student.ID); err == nil || err.Error() != "assignment not found" {
+		t.Errorf("Expected a withdrawn assignment's session to stop counting, got %v", err)
+	}
+}
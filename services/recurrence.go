@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// recurrenceHorizon is how far ahead occurrences are generated; they stay hidden from
+// students until their publish time
+const recurrenceHorizon = 7 * 24 * time.Hour
+
+// RecurrenceService generates assignments from templates on a recurring schedule
+type RecurrenceService struct {
+	db *gorm.DB
+}
+
+// NewRecurrenceService creates a new recurrence service
+func NewRecurrenceService(db *gorm.DB) *RecurrenceService {
+	return &RecurrenceService{db: db}
+}
+
+// CreateRecurrenceInput represents input for creating a recurrence
+type CreateRecurrenceInput struct {
+	TemplateID     uint
+	RRule          string
+	StartsAt       time.Time
+	DueOffsetHours int
+	StudentIDs     []uint
+	GroupIDs       []uint // groups whose members at each occurrence are assigned along with the roster
+}
+
+// CreateRecurrence schedules a template to be assigned to a roster of students, groups or both
+func (s *RecurrenceService) CreateRecurrence(instructorID uint, input CreateRecurrenceInput) (*models.AssignmentRecurrence, error) {
+	template, err := models.GetAssignmentTemplateByID(s.db, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	rule, err := ParseRecurrenceRule(input.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StartsAt.IsZero() {
+		return nil, errors.New("start date is required")
+	}
+
+	if input.DueOffsetHours < 0 {
+		return nil, errors.New("due offset cannot be negative")
+	}
+
+	if len(input.StudentIDs) == 0 && len(input.GroupIDs) == 0 {
+		return nil, errors.New("at least one student or group is required")
+	}
+
+	var students []models.User
+	if len(input.StudentIDs) > 0 {
+		if err := s.db.Where("id IN ? AND role = ?", input.StudentIDs, "student").Find(&students).Error; err != nil {
+			return nil, err
+		}
+
+		if len(students) != len(input.StudentIDs) {
+			return nil, errors.New("some students not found or not valid students")
+		}
+	}
+
+	var groups []models.Group
+	if len(input.GroupIDs) > 0 {
+		if err := s.db.Where("id IN ? AND created_by_id = ?", input.GroupIDs, instructorID).Find(&groups).Error; err != nil {
+			return nil, err
+		}
+
+		if len(groups) != len(input.GroupIDs) {
+			return nil, errors.New("some groups not found")
+		}
+	}
+
+	recurrence := &models.AssignmentRecurrence{
+		TemplateID:     template.ID,
+		RRule:          input.RRule,
+		StartsAt:       input.StartsAt,
+		DueOffsetHours: input.DueOffsetHours,
+		Active:         true,
+		Students:       students,
+		Groups:         groups,
+		CreatedByID:    instructorID,
+	}
+
+	if next, ok := rule.NextAfter(input.StartsAt, nil, 0); ok {
+		recurrence.NextOccurrenceAt = &next
+	} else {
+		return nil, errors.New("recurrence rule produces no occurrences")
+	}
+
+	if err := s.db.Create(recurrence).Error; err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+// GetRecurrences retrieves all recurrences for an instructor
+func (s *RecurrenceService) GetRecurrences(instructorID uint) ([]models.AssignmentRecurrence, error) {
+	return models.GetAssignmentRecurrencesByInstructor(s.db, instructorID)
+}
+
+// DeactivateRecurrence stops a recurrence from generating further assignments
+func (s *RecurrenceService) DeactivateRecurrence(recurrenceID uint, instructorID uint) error {
+	recurrence, err := models.GetAssignmentRecurrenceByID(s.db, recurrenceID)
+	if err != nil {
+		return err
+	}
+
+	if recurrence.CreatedByID != instructorID {
+		return errors.New("access denied")
+	}
+
+	return s.db.Model(recurrence).Update("active", false).Error
+}
+
+// Start generates upcoming occurrences on the given interval until stop is closed
+func (s *RecurrenceService) Start(interval time.Duration, stop <-chan struct{}) {
+	runEvery("recurrence scheduler", interval, stop, func() error {
+		_, err := s.ProcessRecurrences(time.Now())
+		return err
+	})
+}
+
+// ProcessRecurrences creates every occurrence falling within the generation horizon
+// and returns the number of assignments created
+func (s *RecurrenceService) ProcessRecurrences(now time.Time) (int, error) {
+	recurrences, err := models.GetDueRecurrences(s.db, now.Add(recurrenceHorizon))
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i := range recurrences {
+		count, err := s.generateOccurrences(&recurrences[i], now.Add(recurrenceHorizon))
+		created += count
+		if err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+// generateOccurrences creates the recurrence's occurrences up to the horizon
+func (s *RecurrenceService) generateOccurrences(recurrence *models.AssignmentRecurrence, horizon time.Time) (int, error) {
+	rule, err := ParseRecurrenceRule(recurrence.RRule)
+	if err != nil {
+		return 0, err
+	}
+
+	studentIDs, err := s.recurrenceStudentIDs(recurrence)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for recurrence.NextOccurrenceAt != nil && !recurrence.NextOccurrenceAt.After(horizon) {
+		occurrence := *recurrence.NextOccurrenceAt
+
+		var assignment *models.Assignment
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if assignment, err = s.createOccurrence(tx, recurrence, occurrence, studentIDs); err != nil {
+				return err
+			}
+
+			recurrence.OccurrenceCount++
+			recurrence.NextOccurrenceAt = nil
+			if next, ok := rule.NextAfter(recurrence.StartsAt, &occurrence, recurrence.OccurrenceCount); ok {
+				recurrence.NextOccurrenceAt = &next
+			}
+
+			return tx.Model(recurrence).Updates(map[string]interface{}{
+				"occurrence_count":   recurrence.OccurrenceCount,
+				"next_occurrence_at": recurrence.NextOccurrenceAt,
+			}).Error
+		})
+		if err != nil {
+			return created, err
+		}
+		created++
+
+		// The scheduler acts for the instructor who set up the recurrence, so there is no client address
+		events := []models.AuditEvent{assignmentAuditEvent(recurrence.CreatedByID, models.AuditAssignmentCreated, assignment.ID, auditDiff(nil, auditFields(assignment)), "")}
+		events = append(events, studentAuditEvents(recurrence.CreatedByID, models.AuditStudentAssigned, assignment.ID, studentIDs, nil, "")...)
+		recordAudit(s.db, events...)
+	}
+
+	return created, nil
+}
+
+// recurrenceStudentIDs returns the roster together with the current members of the recurrence's groups
+func (s *RecurrenceService) recurrenceStudentIDs(recurrence *models.AssignmentRecurrence) ([]uint, error) {
+	studentIDs := make([]uint, 0, len(recurrence.Students))
+	for _, student := range recurrence.Students {
+		studentIDs = append(studentIDs, student.ID)
+	}
+
+	groupIDs := make([]uint, 0, len(recurrence.Groups))
+	for _, group := range recurrence.Groups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+	members, err := models.GetGroupMemberIDs(s.db, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniqueIDs(append(studentIDs, members...)), nil
+}
+
+// createOccurrence creates a single assignment for an occurrence and assigns the roster
+func (s *RecurrenceService) createOccurrence(tx *gorm.DB, recurrence *models.AssignmentRecurrence, occurrence time.Time, studentIDs []uint) (*models.Assignment, error) {
+	template := recurrence.Template
+	templateID := template.ID
+	recurrenceID := recurrence.ID
+	publishAt := occurrence
+
+	assignment := &models.Assignment{
+		Title:              template.Title,
+		Description:        template.Description,
+		URL:                template.URL,
+		Category:           template.Category,
+		GracePeriodMinutes: template.GracePeriodMinutes,
+		PublishAt:          &publishAt,
+		TemplateID:         &templateID,
+		RecurrenceID:       &recurrenceID,
+		CreatedByID:        recurrence.CreatedByID,
+	}
+
+	if recurrence.DueOffsetHours > 0 {
+		dueDate := occurrence.Add(time.Duration(recurrence.DueOffsetHours) * time.Hour)
+		assignment.DueDate = &dueDate
+	}
+
+	if err := models.InsertAssignment(tx, assignment); err != nil {
+		return nil, err
+	}
+
+	if len(studentIDs) == 0 {
+		return assignment, nil
+	}
+
+	if err := models.BulkCreateStudentAssignments(tx, assignment.ID, studentIDs); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE:
+// FREQ (DAILY or WEEKLY), INTERVAL, BYDAY (weekly only), COUNT and UNTIL.
+type RecurrenceRule struct {
+	Frequency string
+	Interval  int
+	ByDay     []time.Weekday
+	Count     int
+	Until     *time.Time
+}
+
+// maxRecurrencePeriods bounds the search for the next occurrence
+const maxRecurrencePeriods = 1000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRecurrenceRule parses an RRULE string; "daily" and "weekly" are accepted as shorthands
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	switch strings.ToLower(value) {
+	case "daily":
+		value = "FREQ=DAILY"
+	case "weekly":
+		value = "FREQ=WEEKLY"
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = strings.ToUpper(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, errors.New("recurrence interval must be a positive number")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := rruleWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported recurrence day %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, errors.New("recurrence count must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseRRuleUntil(val)
+			if err != nil {
+				return nil, errors.New("recurrence until must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if rule.Frequency != "DAILY" && rule.Frequency != "WEEKLY" {
+		return nil, errors.New("recurrence frequency must be DAILY or WEEKLY")
+	}
+
+	if len(rule.ByDay) > 0 && rule.Frequency != "WEEKLY" {
+		return nil, errors.New("BYDAY is only supported for weekly recurrences")
+	}
+
+	// Order BYDAY from Monday to Sunday so occurrences within a week are chronological
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return mondayOffset(rule.ByDay[i]) < mondayOffset(rule.ByDay[j])
+	})
+
+	return rule, nil
+}
+
+// parseRRuleUntil parses the UNTIL date forms used by RFC 5545
+func parseRRuleUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	until, err := time.ParseInLocation("20060102", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// A date-only UNTIL includes the whole day
+	return until.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// NextAfter returns the first occurrence strictly after the given time, or the first
+// occurrence at or after start when after is nil. generated is the number of occurrences
+// already produced and is checked against COUNT. The second result is false once the
+// rule is exhausted.
+func (r *RecurrenceRule) NextAfter(start time.Time, after *time.Time, generated int) (time.Time, bool) {
+	if r.Count > 0 && generated >= r.Count {
+		return time.Time{}, false
+	}
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, candidate := range r.periodOccurrences(start, period) {
+			if candidate.Before(start) {
+				continue
+			}
+			if after != nil && !candidate.After(*after) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return candidate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// periodOccurrences returns the chronological occurrences within the nth period of the rule
+func (r *RecurrenceRule) periodOccurrences(start time.Time, period int) []time.Time {
+	if r.Frequency == "DAILY" {
+		return []time.Time{start.AddDate(0, 0, period*r.Interval)}
+	}
+
+	if len(r.ByDay) == 0 {
+		return []time.Time{start.AddDate(0, 0, 7*period*r.Interval)}
+	}
+
+	// Weekly with BYDAY: anchor on the Monday of the start week, keeping the start time of day
+	weekStart := start.AddDate(0, 0, -mondayOffset(start.Weekday())+7*period*r.Interval)
+	occurrences := make([]time.Time, 0, len(r.ByDay))
+	for _, day := range r.ByDay {
+		occurrences = append(occurrences, weekStart.AddDate(0, 0, mondayOffset(day)))
+	}
+	return occurrences
+}
+
+// mondayOffset returns the number of days since Monday for a weekday
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// collectOccurrences returns up to limit occurrences of a rule
+func collectOccurrences(t *testing.T, rrule string, start time.Time, limit int) []time.Time {
+	rule, err := ParseRecurrenceRule(rrule)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", rrule, err)
+	}
+
+	var occurrences []time.Time
+	var prev *time.Time
+	for len(occurrences) < limit {
+		next, ok := rule.NextAfter(start, prev, len(occurrences))
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		prev = &next
+	}
+	return occurrences
+}
+
+func TestRecurrenceRuleWeeklyByDay(t *testing.T) {
+	// Wednesday 9:00
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	occurrences := collectOccurrences(t, "FREQ=WEEKLY;BYDAY=MO,WE", start, 4)
+
+	expected := []time.Time{
+		time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC),
+	}
+	if len(occurrences) != len(expected) {
+		t.Fatalf("Expected %d occurrences, got %d", len(expected), len(occurrences))
+	}
+	for i := range expected {
+		if !occurrences[i].Equal(expected[i]) {
+			t.Errorf("Occurrence %d: expected %v, got %v", i, expected[i], occurrences[i])
+		}
+	}
+}
+
+func TestRecurrenceRuleCountAndUntil(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	if occurrences := collectOccurrences(t, "FREQ=DAILY;COUNT=3", start, 10); len(occurrences) != 3 {
+		t.Errorf("Expected COUNT=3 to stop after 3 occurrences, got %d", len(occurrences))
+	}
+
+	occurrences := collectOccurrences(t, "FREQ=WEEKLY;UNTIL=20250115T090000Z", start, 10)
+	if len(occurrences) != 3 {
+		t.Errorf("Expected 3 weekly occurrences through Jan 15, got %d", len(occurrences))
+	}
+
+	occurrences = collectOccurrences(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=2", start, 10)
+	if len(occurrences) != 2 || !occurrences[1].Equal(start.AddDate(0, 0, 14)) {
+		t.Errorf("Expected biweekly occurrences, got %v", occurrences)
+	}
+
+	if occurrences := collectOccurrences(t, "weekly", start, 2); len(occurrences) != 2 {
+		t.Errorf("Expected weekly shorthand to parse, got %v", occurrences)
+	}
+}
+
+func TestParseRecurrenceRuleInvalid(t *testing.T) {
+	invalid := []string{"", "FREQ=MONTHLY", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;INTERVAL=0", "FREQ=WEEKLY;COUNT=abc"}
+	for _, rrule := range invalid {
+		if _, err := ParseRecurrenceRule(rrule); err == nil {
+			t.Errorf("Expected %q to be rejected", rrule)
+		}
+	}
+}
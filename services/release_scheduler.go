@@ -0,0 +1,65 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// ReleaseSchedulerService publishes scheduled assignments and notifies assigned students
+type ReleaseSchedulerService struct {
+	db *gorm.DB
+}
+
+// NewReleaseSchedulerService creates a new release scheduler service
+func NewReleaseSchedulerService(db *gorm.DB) *ReleaseSchedulerService {
+	return &ReleaseSchedulerService{db: db}
+}
+
+// Start processes pending releases on the given interval until stop is closed
+func (s *ReleaseSchedulerService) Start(interval time.Duration, stop <-chan struct{}) {
+	runEvery("release scheduler", interval, stop, func() error {
+		_, err := s.ProcessReleases(time.Now())
+		return err
+	})
+}
+
+// ProcessReleases notifies students of every assignment that went live since the last run
+// and returns the number of assignments released
+func (s *ReleaseSchedulerService) ProcessReleases(now time.Time) (int, error) {
+	assignments, err := models.GetPendingReleases(s.db, now)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, assignment := range assignments {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			studentAssignments, err := models.GetStudentAssignmentsByAssignment(tx, assignment.ID)
+			if err != nil {
+				return err
+			}
+
+			// Skip notifications for readings whose window already closed
+			if assignment.IsPublished(now) {
+				assignmentID := assignment.ID
+				message := fmt.Sprintf("📚 New reading '%s' is now available", assignment.Title)
+				for _, sa := range studentAssignments {
+					if _, err := models.CreateNotification(tx, sa.StudentID, models.NotificationAssignmentReleased, message, &assignmentID); err != nil {
+						return err
+					}
+				}
+			}
+
+			return tx.Model(&models.Assignment{}).Where("id = ?", assignment.ID).Update("released_at", now).Error
+		})
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+
+	return released, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestProcessReleases(t *testing.T) {
+	db := setupTestDB(t)
+	assignmentService := NewAssignmentService(db)
+	studentService := NewStudentAssignmentService(db)
+	scheduler := NewReleaseSchedulerService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student := createTestUser(t, db, "student1", "student")
+
+	// Schedule an assignment for next week and assign it right away
+	publishAt := time.Now().AddDate(0, 0, 7)
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:     "Week 2 Reading",
+		URL:       "https://example.com/week2",
+		PublishAt: &publishAt,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduled assignment: %v", err)
+	}
+
+	if err := assignmentService.AssignToStudent(assignment.ID, student.ID, instructor.ID); err != nil {
+		t.Fatalf("Expected assigning before release to succeed: %v", err)
+	}
+
+	// Hidden from the student before release
+	if _, err := studentService.GetStudentAssignment(assignment.ID, student.ID); err == nil {
+		t.Error("Expected scheduled assignment to be hidden from student")
+	}
+
+	released, err := scheduler.ProcessReleases(time.Now())
+	if err != nil {
+		t.Fatalf("Failed to process releases: %v", err)
+	}
+	if released != 0 {
+		t.Errorf("Expected 0 releases before publish time, got %d", released)
+	}
+
+	// Release once the publish time has passed
+	released, err = scheduler.ProcessReleases(publishAt.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to process releases: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("Expected 1 release, got %d", released)
+	}
+
+	notifications, err := models.GetNotificationsByUser(db, student.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationAssignmentReleased {
+		t.Fatalf("Expected 1 release notification, got %v", notifications)
+	}
+
+	// Processing again must not notify twice
+	released, _ = scheduler.ProcessReleases(publishAt.Add(2 * time.Minute))
+	if released != 0 {
+		t.Errorf("Expected assignment to be released only once, got %d", released)
+	}
+}
+
+func TestCreateAssignmentInvalidSchedule(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAssignmentService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+
+	publishAt := time.Now().AddDate(0, 0, 7)
+	unpublishAt := publishAt.Add(-time.Hour)
+
+	_, err := service.CreateAssignment(instructor.ID, CreateAssignmentInput{
+		Title:       "Backwards Window",
+		URL:         "https://example.com",
+		PublishAt:   &publishAt,
+		UnpublishAt: &unpublishAt,
+	})
+	if err == nil {
+		t.Error("Expected error when unpublish date precedes publish date")
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// runEvery calls job immediately and then once per interval until stop is closed
+func runEvery(name string, interval time.Duration, stop <-chan struct{}, job func() error) {
+	if interval <= 0 {
+		log.Printf("%s disabled (interval %s)", name, interval)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(); err != nil {
+				log.Printf("%s failed: %v", name, err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
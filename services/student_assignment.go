@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 	"zipcodereader/models"
 
 	"gorm.io/gorm"
@@ -29,7 +30,7 @@ func (s *StudentAssignmentService) GetStudentAssignments(studentID uint) ([]mode
 		return nil, errors.New("user is not a student")
 	}
 
-	return models.GetStudentAssignmentsByStudent(s.db, studentID)
+	return withTrackedLinks(models.GetStudentAssignmentsByStudent(s.db, studentID))
 }
 
 // GetStudentAssignmentsByStatus retrieves student assignments by status
@@ -49,7 +50,7 @@ func (s *StudentAssignmentService) GetStudentAssignmentsByStatus(studentID uint,
 		return nil, errors.New("invalid status")
 	}
 
-	return models.GetStudentAssignmentsByStatus(s.db, studentID, status)
+	return withTrackedLinks(models.GetStudentAssignmentsByStatus(s.db, studentID, status))
 }
 
 // GetStudentAssignment retrieves a specific student assignment
@@ -64,7 +65,18 @@ func (s *StudentAssignmentService) GetStudentAssignment(assignmentID uint, stude
 		return nil, errors.New("user is not a student")
 	}
 
-	return models.GetStudentAssignment(s.db, assignmentID, studentID)
+	studentAssignment, err := models.GetStudentAssignment(s.db, assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled assignments stay hidden until they are released
+	if !studentAssignment.Assignment.IsPublished(time.Now()) {
+		return nil, errors.New("assignment not found")
+	}
+
+	studentAssignment.ShowTrackedLink()
+	return studentAssignment, nil
 }
 
 // GetStudentAssignmentByID retrieves a specific student assignment by its ID
@@ -79,7 +91,18 @@ func (s *StudentAssignmentService) GetStudentAssignmentByID(studentAssignmentID
 		return nil, errors.New("user is not a student")
 	}
 
-	return models.GetStudentAssignmentByID(s.db, studentAssignmentID, studentID)
+	studentAssignment, err := models.GetStudentAssignmentByID(s.db, studentAssignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled assignments stay hidden until they are released
+	if !studentAssignment.Assignment.IsPublished(time.Now()) {
+		return nil, errors.New("assignment not found")
+	}
+
+	studentAssignment.ShowTrackedLink()
+	return studentAssignment, nil
 }
 
 // UpdateAssignmentStatus updates the status of a student assignment
@@ -95,8 +118,19 @@ func (s *StudentAssignmentService) UpdateAssignmentStatus(assignmentID uint, stu
 		return err
 	}
 
+	// Reading list items cannot be started before their prerequisites are done
+	if status != models.StatusAssigned {
+		if err := checkReadingListUnlocked(s.db, assignmentID, studentID); err != nil {
+			return err
+		}
+	}
+
 	// Update status
-	return studentAssignment.UpdateStatus(s.db, status)
+	if err := studentAssignment.UpdateStatus(s.db, status); err != nil {
+		return err
+	}
+	evaluateAchievements(s.db, studentAssignment)
+	return nil
 }
 
 // MarkAsCompleted marks an assignment as completed
@@ -107,8 +141,16 @@ func (s *StudentAssignmentService) MarkAsCompleted(assignmentID uint, studentID
 		return err
 	}
 
+	if err := checkReadingListUnlocked(s.db, studentAssignment.AssignmentID, studentID); err != nil {
+		return err
+	}
+
 	// Mark as completed
-	return studentAssignment.MarkAsCompleted(s.db)
+	if err := studentAssignment.MarkAsCompleted(s.db); err != nil {
+		return err
+	}
+	evaluateAchievements(s.db, studentAssignment)
+	return nil
 }
 
 // MarkAsCompletedByID marks an assignment as completed using student assignment ID
@@ -119,8 +161,16 @@ func (s *StudentAssignmentService) MarkAsCompletedByID(studentAssignmentID uint,
 		return err
 	}
 
+	if err := checkReadingListUnlocked(s.db, studentAssignment.AssignmentID, studentID); err != nil {
+		return err
+	}
+
 	// Mark as completed
-	return studentAssignment.MarkAsCompleted(s.db)
+	if err := studentAssignment.MarkAsCompleted(s.db); err != nil {
+		return err
+	}
+	evaluateAchievements(s.db, studentAssignment)
+	return nil
 }
 
 // MarkAsInProgress marks an assignment as in progress
@@ -131,6 +181,10 @@ func (s *StudentAssignmentService) MarkAsInProgress(assignmentID uint, studentID
 		return err
 	}
 
+	if err := checkReadingListUnlocked(s.db, studentAssignment.AssignmentID, studentID); err != nil {
+		return err
+	}
+
 	// Mark as in progress
 	return studentAssignment.MarkAsInProgress(s.db)
 }
@@ -143,6 +197,10 @@ func (s *StudentAssignmentService) MarkAsInProgressByID(studentAssignmentID uint
 		return err
 	}
 
+	if err := checkReadingListUnlocked(s.db, studentAssignment.AssignmentID, studentID); err != nil {
+		return err
+	}
+
 	// Mark as in progress
 	return studentAssignment.MarkAsInProgress(s.db)
 }
@@ -159,7 +217,7 @@ func (s *StudentAssignmentService) GetOverdueAssignments(studentID uint) ([]mode
 		return nil, errors.New("user is not a student")
 	}
 
-	return models.GetOverdueAssignments(s.db, studentID)
+	return withTrackedLinks(models.GetOverdueAssignments(s.db, studentID))
 }
 
 // GetDashboardStats retrieves dashboard statistics for a student
@@ -175,10 +233,11 @@ func (s *StudentAssignmentService) GetDashboardStats(studentID uint) (map[string
 	}
 
 	stats := map[string]int{
-		"total_assignments":       0,
-		"completed_assignments":   0,
-		"in_progress_assignments": 0,
-		"overdue_assignments":     0,
+		"total_assignments":          0,
+		"completed_assignments":      0,
+		"in_progress_assignments":    0,
+		"overdue_assignments":        0,
+		"workload_minutes_this_week": 0,
 	}
 
 	// Get all assignments
@@ -207,6 +266,14 @@ func (s *StudentAssignmentService) GetDashboardStats(studentID uint) (map[string
 
 	stats["overdue_assignments"] = len(overdueAssignments)
 
+	// Estimated reading time of unfinished work due in the next seven days
+	now := time.Now()
+	workload, err := models.GetReadingWorkload(s.db, studentID, now, now.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	stats["workload_minutes_this_week"] = workload
+
 	return stats, nil
 }
 
@@ -228,15 +295,12 @@ func (s *StudentAssignmentService) SearchStudentAssignments(studentID uint, quer
 
 	err := s.db.Preload("Assignment").Preload("Assignment.CreatedBy").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(models.PublishedAt(time.Now())).
 		Where("student_assignments.student_id = ? AND (assignments.title LIKE ? OR assignments.description LIKE ?)",
 			studentID, searchQuery, searchQuery).
 		Find(&studentAssignments).Error
 
-	if err != nil {
-		return nil, err
-	}
-
-	return studentAssignments, nil
+	return withTrackedLinks(studentAssignments, err)
 }
 
 // GetStudentAssignmentsByCategory retrieves student assignments by category
@@ -256,14 +320,11 @@ func (s *StudentAssignmentService) GetStudentAssignmentsByCategory(studentID uin
 
 	err := s.db.Preload("Assignment").Preload("Assignment.CreatedBy").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(models.PublishedAt(time.Now())).
 		Where("student_assignments.student_id = ? AND assignments.category = ?", studentID, category).
 		Find(&studentAssignments).Error
 
-	if err != nil {
-		return nil, err
-	}
-
-	return studentAssignments, nil
+	return withTrackedLinks(studentAssignments, err)
 }
 
 // GetUpcomingAssignments retrieves assignments with upcoming due dates
@@ -283,16 +344,13 @@ func (s *StudentAssignmentService) GetUpcomingAssignments(studentID uint, days i
 
 	err := s.db.Preload("Assignment").Preload("Assignment.CreatedBy").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
-		Where("student_assignments.student_id = ? AND assignments.due_date IS NOT NULL AND assignments.due_date > NOW() AND assignments.due_date <= DATE_ADD(NOW(), INTERVAL ? DAY) AND student_assignments.status != ?",
+		Scopes(models.PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND "+models.EffectiveDueDateSQL+" IS NOT NULL AND "+models.EffectiveDueDateSQL+" > NOW() AND "+models.EffectiveDueDateSQL+" <= DATE_ADD(NOW(), INTERVAL ? DAY) AND student_assignments.status != ?",
 			studentID, days, models.StatusCompleted).
-		Order("assignments.due_date ASC").
+		Order(models.EffectiveDueDateSQL + " ASC").
 		Find(&studentAssignments).Error
 
-	if err != nil {
-		return nil, err
-	}
-
-	return studentAssignments, nil
+	return withTrackedLinks(studentAssignments, err)
 }
 
 // GetRecentlyCompleted retrieves recently completed assignments
@@ -311,16 +369,14 @@ func (s *StudentAssignmentService) GetRecentlyCompleted(studentID uint, days int
 	var studentAssignments []models.StudentAssignment
 
 	err := s.db.Preload("Assignment").Preload("Assignment.CreatedBy").
-		Where("student_id = ? AND status = ? AND completed_at IS NOT NULL AND completed_at >= DATE_SUB(NOW(), INTERVAL ? DAY)",
+		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(models.PublishedAt(time.Now())).
+		Where("student_assignments.student_id = ? AND student_assignments.status = ? AND student_assignments.completed_at IS NOT NULL AND student_assignments.completed_at >= DATE_SUB(NOW(), INTERVAL ? DAY)",
 			studentID, models.StatusCompleted, days).
-		Order("completed_at DESC").
+		Order("student_assignments.completed_at DESC").
 		Find(&studentAssignments).Error
 
-	if err != nil {
-		return nil, err
-	}
-
-	return studentAssignments, nil
+	return withTrackedLinks(studentAssignments, err)
 }
 
 // GetAssignmentCategories retrieves all categories for student's assignments
@@ -341,6 +397,7 @@ func (s *StudentAssignmentService) GetAssignmentCategories(studentID uint) ([]st
 	err := s.db.Model(&models.StudentAssignment{}).
 		Select("DISTINCT assignments.category").
 		Joins("JOIN assignments ON assignments.id = student_assignments.assignment_id").
+		Scopes(models.PublishedAt(time.Now())).
 		Where("student_assignments.student_id = ? AND assignments.category IS NOT NULL AND assignments.category != ''", studentID).
 		Pluck("assignments.category", &categories).Error
 
@@ -350,3 +407,14 @@ func (s *StudentAssignmentService) GetAssignmentCategories(studentID uint) ([]st
 
 	return categories, nil
 }
+
+// withTrackedLinks fills in the tracked links of the student's own assignments
+func withTrackedLinks(studentAssignments []models.StudentAssignment, err error) ([]models.StudentAssignment, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range studentAssignments {
+		studentAssignments[i].ShowTrackedLink()
+	}
+	return studentAssignments, nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// TemplateService handles business logic for assignment templates
+type TemplateService struct {
+	db                *gorm.DB
+	assignmentService *AssignmentService
+}
+
+// NewTemplateService creates a new template service
+func NewTemplateService(db *gorm.DB) *TemplateService {
+	return &TemplateService{db: db, assignmentService: NewAssignmentService(db)}
+}
+
+// TemplateInput represents input for creating or updating an assignment template
+type TemplateInput struct {
+	Name               string
+	Title              string
+	Description        string
+	URL                string
+	Category           string
+	GracePeriodMinutes int
+}
+
+// FromTemplateInput holds the per-assignment values supplied when creating from a template.
+// Empty strings keep the template's values.
+type FromTemplateInput struct {
+	Title       string
+	Description string
+	URL         string
+	Category    string
+	DueDate     *time.Time
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
+
+// CreateTemplate creates a new assignment template
+func (s *TemplateService) CreateTemplate(instructorID uint, input TemplateInput) (*models.AssignmentTemplate, error) {
+	if err := s.validateInstructor(instructorID); err != nil {
+		return nil, err
+	}
+
+	if err := validateTemplateInput(input); err != nil {
+		return nil, err
+	}
+
+	template := &models.AssignmentTemplate{
+		Name:               input.Name,
+		Title:              input.Title,
+		Description:        input.Description,
+		URL:                input.URL,
+		Category:           input.Category,
+		GracePeriodMinutes: input.GracePeriodMinutes,
+		CreatedByID:        instructorID,
+	}
+
+	if err := models.CreateAssignmentTemplate(s.db, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetTemplate retrieves a template owned by the instructor
+func (s *TemplateService) GetTemplate(templateID uint, instructorID uint) (*models.AssignmentTemplate, error) {
+	template, err := models.GetAssignmentTemplateByID(s.db, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.CreatedByID != instructorID {
+		return nil, errors.New("access denied")
+	}
+
+	return template, nil
+}
+
+// GetTemplates retrieves all templates for an instructor
+func (s *TemplateService) GetTemplates(instructorID uint) ([]models.AssignmentTemplate, error) {
+	if err := s.validateInstructor(instructorID); err != nil {
+		return nil, err
+	}
+
+	return models.GetAssignmentTemplatesByInstructor(s.db, instructorID)
+}
+
+// UpdateTemplate updates a template owned by the instructor
+func (s *TemplateService) UpdateTemplate(templateID uint, instructorID uint, input TemplateInput) error {
+	template, err := s.GetTemplate(templateID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTemplateInput(input); err != nil {
+		return err
+	}
+
+	return template.Update(s.db, input.Name, input.Title, input.Description, input.URL, input.Category, input.GracePeriodMinutes)
+}
+
+// DeleteTemplate deletes a template and stops its recurrences
+func (s *TemplateService) DeleteTemplate(templateID uint, instructorID uint) error {
+	template, err := s.GetTemplate(templateID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AssignmentRecurrence{}).Where("template_id = ?", template.ID).Update("active", false).Error; err != nil {
+			return err
+		}
+		return template.Delete(tx)
+	})
+}
+
+// CreateAssignmentFromTemplate creates an assignment using the template's fields
+func (s *TemplateService) CreateAssignmentFromTemplate(templateID uint, instructorID uint, input FromTemplateInput) (*models.Assignment, error) {
+	template, err := s.GetTemplate(templateID, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.assignmentService.CreateAssignment(instructorID, CreateAssignmentInput{
+		Title:              valueOrDefault(input.Title, template.Title),
+		Description:        valueOrDefault(input.Description, template.Description),
+		URL:                valueOrDefault(input.URL, template.URL),
+		Category:           valueOrDefault(input.Category, template.Category),
+		DueDate:            input.DueDate,
+		PublishAt:          input.PublishAt,
+		UnpublishAt:        input.UnpublishAt,
+		GracePeriodMinutes: template.GracePeriodMinutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(assignment).Update("template_id", template.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// FindDuplicates returns other assignments of the same reading as an assignment created from a template
+func (s *TemplateService) FindDuplicates(assignment *models.Assignment) ([]DuplicateAssignment, error) {
+	return s.assignmentService.FindDuplicates(assignment)
+}
+
+// validateInstructor checks that the user exists and has the instructor role
+func (s *TemplateService) validateInstructor(instructorID uint) error {
+	var instructor models.User
+	if err := s.db.First(&instructor, instructorID).Error; err != nil {
+		return errors.New("instructor not found")
+	}
+
+	if !instructor.IsInstructor() {
+		return errors.New("user is not an instructor")
+	}
+
+	return nil
+}
+
+// validateTemplateInput checks the required template fields
+func validateTemplateInput(input TemplateInput) error {
+	if input.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if input.Title == "" {
+		return errors.New("title is required")
+	}
+
+	if input.URL == "" {
+		return errors.New("URL is required")
+	}
+
+	if input.GracePeriodMinutes < 0 {
+		return errors.New("grace period cannot be negative")
+	}
+
+	return nil
+}
+
+// valueOrDefault returns value unless it is empty
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestCreateAssignmentFromTemplate(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTemplateService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+
+	template, err := service.CreateTemplate(instructor.ID, TemplateInput{
+		Name:               "Weekly Article",
+		Title:              "Article of the Week",
+		URL:                "https://example.com/weekly",
+		Category:           "reading",
+		GracePeriodMinutes: 30,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	dueDate := time.Now().AddDate(0, 0, 3)
+	assignment, err := service.CreateAssignmentFromTemplate(template.ID, instructor.ID, FromTemplateInput{
+		Title:   "Article of Week 1",
+		DueDate: &dueDate,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create assignment from template: %v", err)
+	}
+
+	saved, err := models.GetAssignmentByID(db, assignment.ID)
+	if err != nil {
+		t.Fatalf("Failed to load assignment: %v", err)
+	}
+	if saved.Title != "Article of Week 1" || saved.URL != template.URL || saved.Category != template.Category {
+		t.Errorf("Expected template fields with title override, got %+v", saved)
+	}
+	if saved.GracePeriodMinutes != 30 {
+		t.Errorf("Expected grace period from template, got %d", saved.GracePeriodMinutes)
+	}
+	if saved.TemplateID == nil || *saved.TemplateID != template.ID {
+		t.Errorf("Expected assignment to reference template %d", template.ID)
+	}
+
+	// Other instructors cannot use the template
+	if _, err := service.CreateAssignmentFromTemplate(template.ID, otherInstructor.ID, FromTemplateInput{}); err == nil || err.Error() != "access denied" {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+}
+
+func TestProcessRecurrences(t *testing.T) {
+	db := setupTestDB(t)
+	templateService := NewTemplateService(db)
+	recurrenceService := NewRecurrenceService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	student1 := createTestUser(t, db, "student1", "student")
+	student2 := createTestUser(t, db, "student2", "student")
+
+	template, err := templateService.CreateTemplate(instructor.ID, TemplateInput{
+		Name:  "Monday Reading",
+		Title: "Monday Reading",
+		URL:   "https://example.com/monday",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	// Monday 9:00, three occurrences, each due two days later
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	recurrence, err := recurrenceService.CreateRecurrence(instructor.ID, CreateRecurrenceInput{
+		TemplateID:     template.ID,
+		RRule:          "FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+		StartsAt:       start,
+		DueOffsetHours: 48,
+		StudentIDs:     []uint{student1.ID, student2.ID},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create recurrence: %v", err)
+	}
+
+	// Only the first occurrence falls within the horizon the day before
+	created, err := recurrenceService.ProcessRecurrences(start.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("Failed to process recurrences: %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("Expected 1 assignment, got %d", created)
+	}
+
+	var assignments []models.Assignment
+	db.Where("recurrence_id = ?", recurrence.ID).Order("publish_at ASC").Find(&assignments)
+	if len(assignments) != 1 {
+		t.Fatalf("Expected 1 generated assignment, got %d", len(assignments))
+	}
+	if assignments[0].PublishAt == nil || !assignments[0].PublishAt.Equal(start) {
+		t.Errorf("Expected assignment to publish at %v, got %v", start, assignments[0].PublishAt)
+	}
+	if assignments[0].DueDate == nil || !assignments[0].DueDate.Equal(start.Add(48*time.Hour)) {
+		t.Errorf("Expected due date 48 hours after publish, got %v", assignments[0].DueDate)
+	}
+
+	studentAssignments, _ := models.GetStudentAssignmentsByAssignment(db, assignments[0].ID)
+	if len(studentAssignments) != 2 {
+		t.Errorf("Expected roster of 2 students to be assigned, got %d", len(studentAssignments))
+	}
+
+	// Processing far ahead creates the remaining occurrences and exhausts the rule
+	created, err = recurrenceService.ProcessRecurrences(start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("Failed to process recurrences: %v", err)
+	}
+	if created != 2 {
+		t.Errorf("Expected 2 more assignments, got %d", created)
+	}
+
+	saved, err := models.GetAssignmentRecurrenceByID(db, recurrence.ID)
+	if err != nil {
+		t.Fatalf("Failed to load recurrence: %v", err)
+	}
+	if saved.OccurrenceCount != 3 || saved.NextOccurrenceAt != nil {
+		t.Errorf("Expected exhausted recurrence after 3 occurrences, got count %d next %v", saved.OccurrenceCount, saved.NextOccurrenceAt)
+	}
+}
+
+func TestRecurrenceAssignsCurrentGroupMembers(t *testing.T) {
+	db := setupTestDB(t)
+	templateService := NewTemplateService(db)
+	recurrenceService := NewRecurrenceService(db)
+	groupService := NewGroupService(db)
+
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	otherInstructor := createTestUser(t, db, "instructor2", "instructor")
+	alice := createTestUser(t, db, "alice", "student")
+	bob := createTestUser(t, db, "bob", "student")
+	carol := createTestUser(t, db, "carol", "student")
+
+	template, err := templateService.CreateTemplate(instructor.ID, TemplateInput{
+		Name:  "Monday Reading",
+		Title: "Monday Reading",
+		URL:   "https://example.com/monday",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	group, err := groupService.CreateGroup(instructor.ID, GroupInput{Name: "Cohort A", StudentIDs: []uint{alice.ID, bob.ID}})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	otherGroup, err := groupService.CreateGroup(otherInstructor.ID, GroupInput{Name: "Cohort B", StudentIDs: []uint{carol.ID}})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	input := CreateRecurrenceInput{TemplateID: template.ID, RRule: "FREQ=WEEKLY;BYDAY=MO;COUNT=2", StartsAt: start}
+	if _, err := recurrenceService.CreateRecurrence(instructor.ID, input); err == nil {
+		t.Error("Expected a recurrence without students or groups to be rejected")
+	}
+	input.GroupIDs = []uint{otherGroup.ID}
+	if _, err := recurrenceService.CreateRecurrence(instructor.ID, input); err == nil {
+		t.Error("Expected another instructor's group to be rejected")
+	}
+
+	// Alice is also on the roster but is only assigned once
+	input.GroupIDs = []uint{group.ID}
+	input.StudentIDs = []uint{alice.ID}
+	recurrence, err := recurrenceService.CreateRecurrence(instructor.ID, input)
+	if err != nil {
+		t.Fatalf("Failed to create recurrence: %v", err)
+	}
+	if len(recurrence.Groups) != 1 {
+		t.Errorf("Expected the recurrence to target the group, got %+v", recurrence.Groups)
+	}
+
+	assigned := func(week int) []uint {
+		var assignments []models.Assignment
+		db.Where("recurrence_id = ?", recurrence.ID).Order("publish_at ASC").Find(&assignments)
+		if len(assignments) <= week {
+			t.Fatalf("Expected occurrence %d, got %d assignments", week+1, len(assignments))
+		}
+		studentAssignments, _ := models.GetStudentAssignmentsByAssignment(db, assignments[week].ID)
+		var studentIDs []uint
+		for _, sa := range studentAssignments {
+			studentIDs = append(studentIDs, sa.StudentID)
+		}
+		return studentIDs
+	}
+
+	if _, err := recurrenceService.ProcessRecurrences(start.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("Failed to process recurrences: %v", err)
+	}
+	if first := assigned(0); len(first) != 2 {
+		t.Errorf("Expected Alice and Bob on the first occurrence, got %v", first)
+	}
+
+	// Membership changes apply to later occurrences
+	if _, err := groupService.UpdateGroup(group.ID, instructor.ID, GroupInput{Name: "Cohort A", StudentIDs: []uint{bob.ID, carol.ID}}); err != nil {
+		t.Fatalf("Failed to update group: %v", err)
+	}
+	if _, err := recurrenceService.ProcessRecurrences(start.AddDate(0, 0, 6)); err != nil {
+		t.Fatalf("Failed to process recurrences: %v", err)
+	}
+	if second := assigned(1); len(second) != 3 {
+		t.Errorf("Expected Alice from the roster with Bob and Carol from the group, got %v", second)
+	}
+}
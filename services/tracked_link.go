@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"zipcodereader/models"
+
+	"gorm.io/gorm"
+)
+
+// TrackedLinkService records when students open their readings through tracked links
+type TrackedLinkService struct {
+	db *gorm.DB
+}
+
+// NewTrackedLinkService creates a new tracked link service
+func NewTrackedLinkService(db *gorm.DB) *TrackedLinkService {
+	return &TrackedLinkService{db: db}
+}
+
+// Open records a visit to a student's tracked link and returns the reading it points to
+func (s *TrackedLinkService) Open(token string) (string, error) {
+	if token == "" {
+		return "", errors.New("link not found")
+	}
+
+	sa, err := models.GetStudentAssignmentByLinkToken(s.db, token)
+	if err != nil {
+		return "", errors.New("link not found")
+	}
+
+	// Links to scheduled or withdrawn readings behave as if they did not exist
+	now := time.Now()
+	if !sa.Assignment.IsPublished(now) {
+		return "", errors.New("link not found")
+	}
+
+	destination := sa.Assignment.URL
+	if !isRedirectTarget(destination) {
+		return "", errors.New("link has no valid destination")
+	}
+
+	if err := sa.RecordLinkOpen(s.db, now); err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
+// isRedirectTarget reports whether a reading URL is safe to redirect to: an absolute
+// http(s) URL, or a path on this server such as an uploaded file
+func isRedirectTarget(destination string) bool {
+	if strings.HasPrefix(destination, "/") {
+		return !strings.HasPrefix(destination, "//") && !strings.HasPrefix(destination, "/\\")
+	}
+
+	parsed, err := url.Parse(destination)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"zipcodereader/models"
+)
+
+func TestIsRedirectTarget(t *testing.T) {
+	cases := map[string]bool{
+		"https://example.com/essay": true,
+		"http://example.com":        true,
+		"/files/3":                  true,
+		"//evil.example.com":        false,
+		"/\\evil.example.com":       false,
+		"javascript:alert(1)":       false,
+		"https://":                  false,
+		"":                          false,
+	}
+	for destination, want := range cases {
+		if got := isRedirectTarget(destination); got != want {
+			t.Errorf("isRedirectTarget(%q) = %v, want %v", destination, got, want)
+		}
+	}
+}
+
+func TestTrackedLinks(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTrackedLinkService(db)
+	assignmentService := NewAssignmentService(db)
+	progressService := NewProgressTrackingService(db)
+	instructor := createTestUser(t, db, "instructor1", "instructor")
+	reader := createTestUser(t, db, "reader", "student")
+	skimmer := createTestUser(t, db, "skimmer", "student")
+
+	assignment, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Essay", URL: "https://example.com/essay"})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(assignment.ID, []uint{reader.ID, skimmer.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	readerSA, _ := models.GetStudentAssignment(db, assignment.ID, reader.ID)
+	skimmerSA, _ := models.GetStudentAssignment(db, assignment.ID, skimmer.ID)
+	if readerSA.LinkToken == nil || skimmerSA.LinkToken == nil || *readerSA.LinkToken == *skimmerSA.LinkToken {
+		t.Fatal("Expected every student to get their own link token")
+	}
+	if readerSA.TrackedURL() != "/r/"+*readerSA.LinkToken {
+		t.Errorf("Expected a tracked URL, got %q", readerSA.TrackedURL())
+	}
+
+	// Only the student's own view carries the link; instructors never see the token
+	studentView, err := NewStudentAssignmentService(db).GetStudentAssignments(reader.ID)
+	if err != nil || len(studentView) != 1 || studentView[0].TrackedLink != readerSA.TrackedURL() {
+		t.Errorf("Expected the student's view to include their tracked link, got %+v (%v)", studentView, err)
+	}
+	instructorView, _ := assignmentService.GetAssignmentStudents(assignment.ID, instructor.ID)
+	encoded, _ := json.Marshal(instructorView)
+	if strings.Contains(string(encoded), *readerSA.LinkToken) || strings.Contains(string(encoded), "tracked_url") {
+		t.Errorf("Expected the instructor's view to leave out tracked links, got %s", encoded)
+	}
+
+	if _, err := service.Open("not-a-token"); err == nil {
+		t.Error("Expected an unknown token to be rejected")
+	}
+	for i := 0; i < 2; i++ {
+		destination, err := service.Open(*readerSA.LinkToken)
+		if err != nil {
+			t.Fatalf("Failed to open link: %v", err)
+		}
+		if destination != assignment.URL {
+			t.Errorf("Expected a redirect to %q, got %q", assignment.URL, destination)
+		}
+	}
+
+	// Links only open while the reading is published
+	publishAt := time.Now().AddDate(0, 0, 1)
+	scheduled, err := assignmentService.CreateAssignment(instructor.ID, CreateAssignmentInput{Title: "Next", URL: "https://example.com/next", PublishAt: &publishAt})
+	if err != nil {
+		t.Fatalf("Failed to create assignment: %v", err)
+	}
+	if err := assignmentService.AssignToMultipleStudents(scheduled.ID, []uint{reader.ID}, instructor.ID); err != nil {
+		t.Fatalf("Failed to assign students: %v", err)
+	}
+	scheduledSA, _ := models.GetStudentAssignment(db, scheduled.ID, reader.ID)
+	if _, err := service.Open(*scheduledSA.LinkToken); err == nil || err.Error() != "link not found" {
+		t.Errorf("Expected a scheduled reading's link not to be found, got %v", err)
+	}
+	if scheduledSA, _ = models.GetStudentAssignment(db, scheduled.ID, reader.ID); scheduledSA.LinkOpenCount != 0 {
+		t.Errorf("Expected no visit to be recorded before publishing, got %d", scheduledSA.LinkOpenCount)
+	}
+
+	for _, sa := range []*models.StudentAssignment{readerSA, skimmerSA} {
+		if err := sa.MarkAsCompleted(db); err != nil {
+			t.Fatalf("Failed to complete assignment: %v", err)
+		}
+	}
+	// Opening the link after completing does not clear the flag
+	if _, err := service.Open(*skimmerSA.LinkToken); err != nil {
+		t.Fatalf("Failed to open link: %v", err)
+	}
+
+	report, err := progressService.GetDetailedProgressReport(assignment.ID, instructor.ID)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.UnopenedCompletions != 1 {
+		t.Errorf("Expected 1 completion without opening, got %d", report.UnopenedCompletions)
+	}
+	for _, detail := range report.StudentDetails {
+		switch detail.StudentID {
+		case reader.ID:
+			if detail.LinkOpenCount != 2 || detail.FirstOpenedAt == nil || detail.LastOpenedAt == nil || detail.Unopened {
+				t.Errorf("Expected the reader to have opened the link twice before completing, got %+v", detail)
+			}
+		case skimmer.ID:
+			if detail.LinkOpenCount != 1 || !detail.Unopened {
+				t.Errorf("Expected the skimmer to be flagged, got %+v", detail)
+			}
+		}
+	}
+}
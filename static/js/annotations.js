@@ -0,0 +1,273 @@
+// Highlights and margin notes on archived readings.
+// Passages are stored with the quoted text, a little context on either side and their
+// character offsets in the article text. When the page loads, each passage is found again
+// by its offsets, or by its quote and context if the archive changed since.
+
+(function() {
+    const root = document.getElementById('archiveContent');
+    if (!root) {
+        return;
+    }
+
+    const assignmentId = root.dataset.assignmentId;
+    const currentUserId = Number(root.dataset.userId);
+    const toolbar = document.getElementById('annotationToolbar');
+    const panel = document.getElementById('annotationPanel');
+    const contextLength = 32;
+    let pendingSelection = null;
+
+    function el(tag, className, text) {
+        const node = document.createElement(tag);
+        if (className) node.className = className;
+        if (text !== undefined) node.textContent = text;
+        return node;
+    }
+
+    function request(url, method, body) {
+        return fetch(url, {
+            method: method,
+            headers: {'Content-Type': 'application/json'},
+            body: body ? JSON.stringify(body) : undefined
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                throw new Error(data.error);
+            }
+            return data;
+        });
+    }
+
+    function textNodes() {
+        const walker = document.createTreeWalker(root, NodeFilter.SHOW_TEXT);
+        const nodes = [];
+        while (walker.nextNode()) {
+            nodes.push(walker.currentNode);
+        }
+        return nodes;
+    }
+
+    // Character offset of a point in the article text
+    function offsetOf(container, offset) {
+        let total = 0;
+        for (const node of textNodes()) {
+            if (node === container) {
+                return total + offset;
+            }
+            total += node.data.length;
+        }
+        // The point is on an element; count the text before its child at offset
+        const range = document.createRange();
+        range.selectNodeContents(root);
+        range.setEnd(container, offset);
+        return range.toString().length;
+    }
+
+    function selectors(range) {
+        const text = root.textContent;
+        const start = offsetOf(range.startContainer, range.startOffset);
+        const end = offsetOf(range.endContainer, range.endOffset);
+        return {
+            exact: text.slice(start, end),
+            prefix: text.slice(Math.max(0, start - contextLength), start),
+            suffix: text.slice(end, end + contextLength),
+            start: start,
+            end: end
+        };
+    }
+
+    // Finds an annotation's passage in the current text, or returns null if it is gone
+    function anchor(annotation, text) {
+        if (text.slice(annotation.start, annotation.end) === annotation.exact) {
+            return {start: annotation.start, end: annotation.end};
+        }
+
+        let best = null;
+        for (let at = text.indexOf(annotation.exact); at !== -1; at = text.indexOf(annotation.exact, at + 1)) {
+            let score = 0;
+            if (annotation.prefix && text.slice(0, at).endsWith(annotation.prefix)) score += 2;
+            if (annotation.suffix && text.slice(at + annotation.exact.length).startsWith(annotation.suffix)) score += 2;
+            score -= Math.abs(at - annotation.start) / Math.max(text.length, 1);
+            if (!best || score > best.score) {
+                best = {start: at, end: at + annotation.exact.length, score: score};
+            }
+        }
+        return best;
+    }
+
+    // Wraps the text between two offsets in marks; the article text itself is unchanged
+    function highlight(start, end, annotation) {
+        let position = 0;
+        for (const node of textNodes()) {
+            const nodeStart = position;
+            const nodeEnd = position + node.data.length;
+            position = nodeEnd;
+            if (nodeEnd <= start || nodeStart >= end || node.parentNode.closest('mark.annotation[data-id="' + annotation.id + '"]')) {
+                continue;
+            }
+
+            let target = node;
+            if (start > nodeStart) {
+                target = target.splitText(start - nodeStart);
+            }
+            if (end < nodeEnd) {
+                target.splitText(end - Math.max(start, nodeStart));
+            }
+
+            const mark = el('mark', 'annotation');
+            mark.dataset.id = annotation.id;
+            if (annotation.visibility === 'shared') mark.classList.add('shared');
+            if (annotation.note) mark.classList.add('has-note');
+            target.parentNode.insertBefore(mark, target);
+            mark.appendChild(target);
+            mark.addEventListener('click', event => {
+                event.stopPropagation();
+                showAnnotation(annotation);
+            });
+        }
+    }
+
+    function clearHighlights() {
+        root.querySelectorAll('mark.annotation').forEach(mark => {
+            mark.replaceWith(...mark.childNodes);
+        });
+        root.normalize();
+    }
+
+    function load() {
+        return request(`/assignments/${assignmentId}/annotations`, 'GET')
+            .then(data => {
+                clearHighlights();
+                const text = root.textContent;
+                let orphaned = 0;
+                data.annotations.forEach(annotation => {
+                    const position = anchor(annotation, text);
+                    if (position) {
+                        highlight(position.start, position.end, annotation);
+                    } else {
+                        orphaned++;
+                    }
+                });
+                if (orphaned > 0) {
+                    showMessage(`${orphaned} annotation${orphaned === 1 ? '' : 's'} could not be found in this copy of the reading.`);
+                }
+            })
+            .catch(error => console.error('Error loading annotations:', error));
+    }
+
+    function showMessage(message) {
+        panel.replaceChildren(el('div', '', message));
+        panel.style.display = 'block';
+    }
+
+    function visibilitySelect(value) {
+        const select = el('select');
+        [['private', 'Only me'], ['shared', 'Shared with class']].forEach(([optionValue, label]) => {
+            const option = el('option', '', label);
+            option.value = optionValue;
+            select.appendChild(option);
+        });
+        select.value = value || 'private';
+        return select;
+    }
+
+    function showEditor(quote, note, visibility, onSave, onDelete) {
+        const noteInput = el('textarea');
+        noteInput.rows = 4;
+        noteInput.placeholder = 'Margin note (optional)';
+        noteInput.value = note || '';
+        const visibilityInput = visibilitySelect(visibility);
+        const save = el('button', '', 'Save');
+        save.type = 'button';
+        save.addEventListener('click', () => onSave(noteInput.value, visibilityInput.value));
+
+        panel.replaceChildren(el('div', 'annotation-quote', quote), noteInput, visibilityInput, save);
+        if (onDelete) {
+            const remove = el('button', '', 'Delete');
+            remove.type = 'button';
+            remove.addEventListener('click', onDelete);
+            panel.appendChild(remove);
+        }
+        panel.style.display = 'block';
+        noteInput.focus();
+    }
+
+    function showAnnotation(annotation) {
+        if (annotation.user_id !== currentUserId) {
+            panel.replaceChildren(
+                el('div', 'annotation-quote', annotation.exact),
+                el('div', 'annotation-meta', `${annotation.author_name} · shared with class`),
+                el('div', 'annotation-note', annotation.note || 'No note')
+            );
+            panel.style.display = 'block';
+            return;
+        }
+
+        showEditor(annotation.exact, annotation.note, annotation.visibility, (note, visibility) => {
+            request(`/annotations/${annotation.id}`, 'PUT', {note: note, visibility: visibility})
+                .then(() => { panel.style.display = 'none'; return load(); })
+                .catch(error => alert(error.message));
+        }, () => {
+            if (!confirm('Delete this annotation?')) {
+                return;
+            }
+            request(`/annotations/${annotation.id}`, 'DELETE')
+                .then(() => { panel.style.display = 'none'; return load(); })
+                .catch(error => alert(error.message));
+        });
+    }
+
+    function create(selection, note, visibility) {
+        return request(`/assignments/${assignmentId}/annotations`, 'POST', Object.assign({}, selection, {
+            note: note,
+            visibility: visibility
+        }))
+        .then(() => {
+            panel.style.display = 'none';
+            window.getSelection().removeAllRanges();
+            return load();
+        })
+        .catch(error => alert(error.message));
+    }
+
+    document.addEventListener('mouseup', event => {
+        if (toolbar.contains(event.target) || panel.contains(event.target)) {
+            return;
+        }
+        const selection = window.getSelection();
+        if (selection.isCollapsed || !root.contains(selection.anchorNode) || !root.contains(selection.focusNode)) {
+            toolbar.style.display = 'none';
+            return;
+        }
+
+        const range = selection.getRangeAt(0);
+        pendingSelection = selectors(range);
+        if (!pendingSelection.exact.trim()) {
+            toolbar.style.display = 'none';
+            return;
+        }
+
+        const rect = range.getBoundingClientRect();
+        toolbar.style.top = (window.scrollY + rect.top - 40) + 'px';
+        toolbar.style.left = (window.scrollX + rect.left) + 'px';
+        toolbar.style.display = 'block';
+    });
+
+    toolbar.addEventListener('click', event => {
+        const action = event.target.dataset.action;
+        if (!action || !pendingSelection) {
+            return;
+        }
+        toolbar.style.display = 'none';
+        const selection = pendingSelection;
+        pendingSelection = null;
+
+        if (action === 'highlight') {
+            create(selection, '', 'private');
+        } else {
+            showEditor(selection.exact, '', 'private', (note, visibility) => create(selection, note, visibility));
+        }
+    });
+
+    load();
+})();
@@ -35,3 +35,96 @@ function showNotification(message, type = 'info') {
     console.log(`${type.toUpperCase()}: ${message}`);
     // Will implement actual notification system in later phases
 }
+
+// Time-on-task tracking. A reading session starts when a student opens the reading from
+// its assignment page, opens the archived copy, or starts the reading timer, and sends a
+// heartbeat every 30 seconds until it is stopped or the page closes. The server only
+// counts time between heartbeats that arrive close together.
+const ReadingTracker = (function() {
+    const heartbeatInterval = 30000;
+    let session = null;
+    let timer = null;
+
+    function post(url, body) {
+        return fetch(url, {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json'},
+            body: JSON.stringify(body || {})
+        }).then(response => response.json());
+    }
+
+    function clear() {
+        clearInterval(timer);
+        timer = null;
+        session = null;
+    }
+
+    function heartbeat() {
+        if (!session) {
+            return;
+        }
+        // The archived copy only counts while it is on screen
+        if (session.source === 'archive' && document.hidden) {
+            return;
+        }
+        post(`/student/reading-sessions/${session.id}/heartbeat`)
+            .then(data => {
+                // Another tab started a session on the same reading and closed this one
+                if (data.error) {
+                    clear();
+                }
+            })
+            .catch(error => console.error('Error sending reading heartbeat:', error));
+    }
+
+    function start(studentAssignmentId, source) {
+        stop();
+        return post(`/student/assignments/${studentAssignmentId}/reading-sessions`, {source: source})
+            .then(data => {
+                if (data.error) {
+                    throw new Error(data.error);
+                }
+                session = data.session;
+                timer = setInterval(heartbeat, heartbeatInterval);
+                return session;
+            });
+    }
+
+    function stop() {
+        if (!session) {
+            return;
+        }
+        navigator.sendBeacon(`/student/reading-sessions/${session.id}/stop`);
+        clear();
+    }
+
+    function source() {
+        return session ? session.source : null;
+    }
+
+    window.addEventListener('pagehide', stop);
+
+    document.addEventListener('visibilitychange', function() {
+        // A student coming back to the assignment page has finished reading the linked page
+        if (!document.hidden && source() === 'link') {
+            stop();
+        }
+    });
+
+    document.addEventListener('DOMContentLoaded', function() {
+        document.querySelectorAll('[data-reading-link]').forEach(link => {
+            link.addEventListener('click', () => {
+                start(link.dataset.readingLink, 'link')
+                    .catch(error => console.error('Error starting reading session:', error));
+            });
+        });
+
+        const archive = document.querySelector('[data-reading-archive]');
+        if (archive) {
+            start(archive.dataset.readingArchive, 'archive')
+                .catch(error => console.error('Error starting reading session:', error));
+        }
+    });
+
+    return {start: start, stop: stop, source: source};
+})();
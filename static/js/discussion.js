@@ -0,0 +1,155 @@
+// Assignment discussion threads, shared by the instructor and student assignment pages.
+// Post HTML is rendered and sanitized by the server; everything else is inserted as text.
+
+function initDiscussion(container, assignmentId, currentUserId) {
+    const baseURL = `/assignments/${assignmentId}/discussion`;
+    let moderator = false;
+
+    function el(tag, className, text) {
+        const node = document.createElement(tag);
+        if (className) node.className = className;
+        if (text !== undefined) node.textContent = text;
+        return node;
+    }
+
+    function button(label, onClick) {
+        const node = el('button', 'text-xs text-blue-600 hover:text-blue-800', label);
+        node.type = 'button';
+        node.addEventListener('click', onClick);
+        return node;
+    }
+
+    function request(url, method, body) {
+        return fetch(url, {
+            method: method,
+            headers: {'Content-Type': 'application/json'},
+            body: body ? JSON.stringify(body) : undefined
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                throw new Error(data.error);
+            }
+            return data;
+        });
+    }
+
+    function composer(placeholder, submitLabel, initial, onSubmit) {
+        const form = el('div', 'mt-2 space-y-2');
+        const input = el('textarea', 'w-full border border-gray-300 rounded-lg px-3 py-2 text-sm');
+        input.rows = 3;
+        input.placeholder = placeholder;
+        input.value = initial || '';
+        const submit = el('button', 'bg-blue-600 hover:bg-blue-700 text-white px-3 py-1 rounded-lg text-sm', submitLabel);
+        submit.type = 'button';
+        submit.addEventListener('click', () => {
+            onSubmit(input.value)
+                .then(load)
+                .catch(error => alert(error.message));
+        });
+        form.append(input, submit);
+        return form;
+    }
+
+    function renderPost(post, depth) {
+        const item = el('div', depth === 0
+            ? 'border border-gray-200 rounded-lg p-4'
+            : 'border-l-2 pl-4 mt-3 ' + (post.pinned ? 'border-green-400' : 'border-gray-200'));
+
+        const header = el('div', 'flex items-center space-x-2 text-xs text-gray-500 mb-1');
+        header.appendChild(el('span', 'font-medium text-gray-900', post.deleted ? 'Deleted post' : post.author_name));
+        if (post.author_role === 'instructor' && !post.deleted) {
+            header.appendChild(el('span', 'bg-blue-100 text-blue-800 px-1 rounded', 'Instructor'));
+        }
+        if (post.pinned) {
+            header.appendChild(el('span', 'bg-green-100 text-green-800 px-1 rounded', depth === 0 ? 'Pinned' : 'Answer'));
+        }
+        if (post.hidden) {
+            header.appendChild(el('span', 'bg-red-100 text-red-800 px-1 rounded', 'Hidden'));
+        }
+        header.appendChild(el('span', '', new Date(post.created_at).toLocaleString() + (post.edited_at ? ' (edited)' : '')));
+        item.appendChild(header);
+
+        const body = el('div', 'prose max-w-none text-sm text-gray-800');
+        if (post.deleted) {
+            body.appendChild(el('em', 'text-gray-400', 'This post was deleted.'));
+        } else if (post.hidden && !moderator) {
+            body.appendChild(el('em', 'text-gray-400', 'This post was hidden by the instructor.'));
+        } else {
+            body.innerHTML = post.content_html;
+        }
+        item.appendChild(body);
+
+        if (!post.deleted) {
+            const actions = el('div', 'flex space-x-3 mt-2');
+            const slot = el('div');
+            if (!post.hidden) {
+                actions.appendChild(button('Reply', () => {
+                    slot.replaceChildren(composer('Write a reply...', 'Reply', '', content =>
+                        request(baseURL, 'POST', {parent_id: post.id, content: content})));
+                }));
+            }
+            if (post.author_id === currentUserId) {
+                actions.appendChild(button('Edit', () => {
+                    slot.replaceChildren(composer('', 'Save', post.content, content =>
+                        request(`/discussion/posts/${post.id}`, 'PUT', {content: content})));
+                }));
+            }
+            if (post.edited_at && (moderator || post.author_id === currentUserId)) {
+                actions.appendChild(button('History', () => {
+                    request(`/discussion/posts/${post.id}/history`, 'GET')
+                        .then(data => {
+                            const list = el('div', 'mt-2 space-y-2');
+                            data.revisions.forEach(revision => {
+                                const entry = el('div', 'bg-gray-50 rounded p-2 text-xs text-gray-700 whitespace-pre-wrap');
+                                entry.appendChild(el('div', 'text-gray-500 mb-1', new Date(revision.created_at).toLocaleString()));
+                                entry.appendChild(el('div', '', revision.content));
+                                list.appendChild(entry);
+                            });
+                            slot.replaceChildren(list);
+                        })
+                        .catch(error => alert(error.message));
+                }));
+            }
+            if (moderator) {
+                actions.appendChild(button(post.pinned ? 'Unpin' : (depth === 0 ? 'Pin' : 'Pin as answer'), () => {
+                    request(`/discussion/posts/${post.id}/pin`, 'PUT', {pinned: !post.pinned}).then(load).catch(error => alert(error.message));
+                }));
+                actions.appendChild(button(post.hidden ? 'Unhide' : 'Hide', () => {
+                    request(`/discussion/posts/${post.id}/hide`, 'PUT', {hidden: !post.hidden}).then(load).catch(error => alert(error.message));
+                }));
+            }
+            if (moderator || post.author_id === currentUserId) {
+                actions.appendChild(button('Delete', () => {
+                    if (confirm('Delete this post?')) {
+                        request(`/discussion/posts/${post.id}`, 'DELETE').then(load).catch(error => alert(error.message));
+                    }
+                }));
+            }
+            item.append(actions, slot);
+        }
+
+        (post.replies || []).forEach(reply => item.appendChild(renderPost(reply, depth + 1)));
+        return item;
+    }
+
+    function load() {
+        return request(baseURL, 'GET')
+            .then(data => {
+                moderator = data.moderator;
+                const list = el('div', 'space-y-4');
+                if (data.threads.length === 0) {
+                    list.appendChild(el('p', 'text-sm text-gray-500', 'No questions yet. Start the discussion below.'));
+                }
+                data.threads.forEach(thread => list.appendChild(renderPost(thread, 0)));
+                container.replaceChildren(list, composer('Ask a question or start a discussion...', 'Post', '', content =>
+                    request(baseURL, 'POST', {content: content})));
+            })
+            .catch(error => {
+                console.error('Error loading discussion:', error);
+                container.replaceChildren(el('p', 'text-sm text-red-600', 'Error loading discussion.'));
+            });
+    }
+
+    load();
+}
@@ -0,0 +1,34 @@
+// Package storage stores uploaded files on local disk or in S3-compatible object storage.
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage is a flat key/value store for file contents. Keys are slash-separated paths.
+type Storage interface {
+	// Put stores size bytes from body under key, replacing any existing object
+	Put(key string, body io.ReadSeeker, size int64, contentType string) error
+	// Get opens the object stored under key
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(key string) error
+}
+
+// validateKey rejects empty keys and keys that could escape the storage root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.New("invalid storage key")
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.New("invalid storage key")
+		}
+	}
+	return nil
+}